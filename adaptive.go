@@ -0,0 +1,91 @@
+package jitjson
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Adaptive switches the values of a workload between deferred and eager decoding by
+// sampling how often they are actually decoded, so the choice need not be tuned by hand
+// for each parse percentage. Values created with WithAdaptive by NewFromBytes,
+// UnmarshalJSON or SetBytes are counted, as are those of them later read with Unmarshal
+// or UnmarshalValue for the first time. At the end of each window of Window values, new
+// values are decoded eagerly if the reads counted during the window exceed Threshold of
+// its values, and deferred otherwise:
+//
+//	var orders jitjson.Adaptive
+//	jit := jitjson.NewFromBytes[Order](data, jitjson.WithAdaptive(&orders))
+//
+// Values start deferred. An Adaptive should be shared by the values of one workload,
+// such as the items of one message type, and is safe for concurrent use. It does not
+// apply with WithBytesTransform or a Retention dropping the value.
+type Adaptive struct {
+	// Threshold is the ratio of values read above which new values are decoded eagerly.
+	// Zero means 0.5.
+	Threshold float64
+	// Window is the number of values sampled for each ratio. Zero means 1000.
+	Window int
+
+	created, read atomic.Int64
+	ratio         atomic.Uint64
+	eager         atomic.Bool
+}
+
+// WithAdaptive makes JitJSON[T] decode eagerly or deferred as decided by a.
+func WithAdaptive(a *Adaptive) Option {
+	return func(o *options) {
+		o.adaptive = a
+	}
+}
+
+// Eager reports whether new values are currently decoded eagerly.
+func (a *Adaptive) Eager() bool {
+	return a.eager.Load()
+}
+
+// Ratio returns the ratio of values read in the last complete window, or zero if no
+// window has completed.
+func (a *Adaptive) Ratio() float64 {
+	return math.Float64frombits(a.ratio.Load())
+}
+
+// sample counts a created value, deciding the mode for new values at the end of each
+// window.
+func (a *Adaptive) sample() {
+	window := int64(a.Window)
+	if window <= 0 {
+		window = 1000
+	}
+	if a.created.Add(1) < window {
+		return
+	}
+	created := a.created.Swap(0)
+	read := a.read.Swap(0)
+	if created <= 0 {
+		return
+	}
+	ratio := min(float64(read)/float64(created), 1)
+	threshold := a.Threshold
+	if threshold <= 0 {
+		threshold = 0.5
+	}
+	a.ratio.Store(math.Float64bits(ratio))
+	a.eager.Store(ratio > threshold)
+}
+
+// sample counts JitJSON[T] as created for the configured Adaptive, if any.
+func (jit *JitJSON[T]) sample() {
+	if jit.opts == nil || jit.opts.adaptive == nil {
+		return
+	}
+	jit.extend().sampled = true
+	jit.opts.adaptive.sample()
+}
+
+// touch counts the first read of JitJSON[T] for the configured Adaptive.
+func (jit *JitJSON[T]) touch() {
+	if jit.ext != nil && jit.ext.sampled {
+		jit.ext.sampled = false
+		jit.opts.adaptive.read.Add(1)
+	}
+}
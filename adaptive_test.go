@@ -0,0 +1,48 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestAdaptive(t *testing.T) {
+	a := &jitjson.Adaptive{Window: 10, Threshold: 0.5}
+	data := []byte(`{"Name":"John"}`)
+
+	// a window where every value is read switches new values to eager decoding
+	for i := 0; i < 10; i++ {
+		jit := jitjson.NewFromBytes[Person](data, jitjson.WithAdaptive(a))
+		if i < 9 {
+			if _, err := jit.Unmarshal(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if !a.Eager() || a.Ratio() != 0.9 {
+		t.Fatalf("expected eager decoding, got %v at %v", a.Eager(), a.Ratio())
+	}
+
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	var jits []*jitjson.JitJSON[Person]
+	for i := 0; i < 10; i++ {
+		jit := new(jitjson.JitJSON[Person])
+		jit.SetOptions(jitjson.WithAdaptive(a), count)
+		if err := jit.UnmarshalJSON(data); err != nil {
+			t.Fatal(err)
+		}
+		jits = append(jits, jit)
+	}
+	if decodes != 10 {
+		t.Errorf("expected eager decodes, got %d", decodes)
+	}
+
+	// a window where no values were read switches back to deferred decoding
+	if a.Eager() || a.Ratio() != 0 {
+		t.Errorf("expected deferred decoding, got %v at %v", a.Eager(), a.Ratio())
+	}
+	if p, err := jits[0].Unmarshal(); err != nil || p.Name != "John" {
+		t.Errorf("unexpected value %v, %v", p, err)
+	}
+}
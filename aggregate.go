@@ -0,0 +1,98 @@
+package jitjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Aggregate holds the count, sum, minimum and maximum of the numbers at a path across a
+// set of JSON values, as computed by AggregateField and AggregateDecoder. Min and Max
+// are zero when Count is zero.
+type Aggregate struct {
+	Count    int
+	Sum      float64
+	Min, Max float64
+}
+
+// Mean returns the average of the numbers, or zero when there are none.
+func (a Aggregate) Mean() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Sum / float64(a.Count)
+}
+
+// add includes the member at path of the JSON value data in the aggregate. Missing and
+// null members are skipped.
+func (a *Aggregate) add(data []byte, path string) error {
+	raw, ok, err := pathBytes(data, path)
+	if err != nil || !ok {
+		return err
+	}
+	raw = bytes.TrimSpace(raw)
+	if string(raw) == "null" {
+		return nil
+	}
+	if !isNumberLiteral(raw) {
+		return fmt.Errorf("%s: %s is not a number", path, raw)
+	}
+	f, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if a.Count == 0 || f < a.Min {
+		a.Min = f
+	}
+	if a.Count == 0 || f > a.Max {
+		a.Max = f
+	}
+	a.Count++
+	a.Sum += f
+	return nil
+}
+
+// AggregateField computes the Aggregate of the numbers at path across items, scanning
+// the raw encoding of each item without decoding it or building a column, for quick
+// report endpoints:
+//
+//	agg, err := jitjson.AggregateField(orders, "payment.total")
+//	fmt.Println(agg.Count, agg.Sum, agg.Min, agg.Max, agg.Mean())
+//
+// Path is matched as by Find. Items where path is missing or null are skipped. Items
+// holding only a value are marshaled first. An error is returned, naming the item, if
+// an item cannot be marshaled, is not valid JSON along path, or holds a value other
+// than a number at path.
+func AggregateField[T any](items []*JitJSON[T], path string) (Aggregate, error) {
+	var agg Aggregate
+	for i, item := range items {
+		data, err := item.Marshal()
+		if err != nil {
+			return Aggregate{}, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		if err := agg.add(data, path); err != nil {
+			return Aggregate{}, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+	}
+	return agg, nil
+}
+
+// AggregateDecoder computes the Aggregate of the numbers at path across the JSON values
+// read from dec, like AggregateField, holding only one value in memory at a time. It
+// reads values until dec.More reports false, so it covers a stream of values such as
+// JSON Lines or, once the opening bracket has been read with dec.Token, the elements of
+// an array. Values are counted from zero in errors.
+func AggregateDecoder(dec *json.Decoder, path string) (Aggregate, error) {
+	var agg Aggregate
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return Aggregate{}, fmt.Errorf("jitjson: value %d: %w", i, err)
+		}
+		if err := agg.add(raw, path); err != nil {
+			return Aggregate{}, fmt.Errorf("jitjson: value %d: %w", i, err)
+		}
+	}
+	return agg, nil
+}
@@ -0,0 +1,68 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestAggregateField(t *testing.T) {
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	items := []*jitjson.JitJSON[Person]{
+		jitjson.NewFromBytes[Person]([]byte(`{"Name": "John", "Age": 30}`), count),
+		jitjson.NewFromBytes[Person]([]byte(`{"Name": "Jane", "Age": null}`), count),
+		jitjson.NewFromBytes[Person]([]byte(`{"Name": "Jim"}`), count),
+		jitjson.New(Person{Name: "Joe", Age: 42}, count),
+		jitjson.NewFromBytes[Person]([]byte(`{"Name": "Ann", "Age": 18.5}`), count),
+	}
+
+	agg, err := jitjson.AggregateField(items, "age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := jitjson.Aggregate{Count: 3, Sum: 90.5, Min: 18.5, Max: 42}
+	if agg != want {
+		t.Errorf("expected %+v, got %+v", want, agg)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no items to be decoded, got %d", decodes)
+	}
+	if mean := agg.Mean(); mean < 30.16 || mean > 30.17 {
+		t.Errorf("expected a mean of about 30.17, got %v", mean)
+	}
+
+	if _, err := jitjson.AggregateField(items, "Name"); err == nil {
+		t.Error("expected an error for a non-numeric member")
+	}
+	if agg, err := jitjson.AggregateField(items, "missing"); err != nil || agg != (jitjson.Aggregate{}) {
+		t.Errorf("expected an empty aggregate, got %+v, %v", agg, err)
+	}
+}
+
+func TestAggregateDecoder(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`[
+		{"order": {"total": 12.5}},
+		{"order": {"total": 7}},
+		{"order": null},
+		{"order": {"total": -1}}
+	]`))
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	agg, err := jitjson.AggregateDecoder(dec, "order.total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := jitjson.Aggregate{Count: 3, Sum: 18.5, Min: -1, Max: 12.5}
+	if agg != want {
+		t.Errorf("expected %+v, got %+v", want, agg)
+	}
+
+	dec = json.NewDecoder(strings.NewReader("{\"n\": 1}\n{\"n\": \"x\"}\n"))
+	if _, err := jitjson.AggregateDecoder(dec, "n"); err == nil || !strings.Contains(err.Error(), "value 1") {
+		t.Errorf("expected an error naming value 1, got %v", err)
+	}
+}
@@ -3,17 +3,22 @@ package jitjson
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
+	"unicode/utf8"
+	"unsafe"
 )
 
+// The patterns only allow JSON whitespace around values, which unlike \s excludes
+// form feeds, and reject control characters and unknown escapes within strings.
 var (
-	nullRegex   = regexp.MustCompile(`^\s*null\s*$`)
-	arrayRegex  = regexp.MustCompile(`^\s*\[\s*(.|\n)*\]\s*$`)
-	objectRegex = regexp.MustCompile(`^\s*\{\s*(.|\n)*\}\s*$`)
-	boolRegex   = regexp.MustCompile(`^\s*(true|false)\s*$`)
-	numberRegex = regexp.MustCompile(`^\s*-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?\s*$`)
-	stringRegex = regexp.MustCompile(`^\s*"(\\.|[^"\\])*"\s*$`)
+	nullRegex   = regexp.MustCompile(`^[ \t\n\r]*null[ \t\n\r]*$`)
+	boolRegex   = regexp.MustCompile(`^[ \t\n\r]*(true|false)[ \t\n\r]*$`)
+	numberRegex = regexp.MustCompile(`^[ \t\n\r]*-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?[ \t\n\r]*$`)
+	stringRegex = regexp.MustCompile(`^[ \t\n\r]*"(\\(["\\/bfnrt]|u[0-9a-fA-F]{4})|[^"\\\x00-\x1f])*"[ \t\n\r]*$`)
 )
 
 // ValueType represents the JSON type of the value stored in AnyJitJSON.
@@ -32,6 +37,9 @@ const (
 )
 
 func (v ValueType) String() string {
+	if v < TypeNull || v > TypeInvalid {
+		return "ValueType(" + strconv.Itoa(int(v)) + ")"
+	}
 	return []string{
 		"TypeNull",
 		"TypeBool",
@@ -95,22 +103,112 @@ func (v ValueType) String() string {
 type AnyJitJSON struct {
 	val  interface{}
 	data []byte
+	// paths is the *pathCache created by Get, accessed atomically. It is not held in an
+	// atomic.Pointer so that AnyJitJSON values can still be copied; a copy shares the
+	// cache until either is changed.
+	paths unsafe.Pointer
 }
 
-// NewAny creates a new AnyJitJSON from JSON data.
+// NewAny creates a new AnyJitJSON from JSON data. The data is validated with ScanValid
+// so that malformed documents are rejected before any parsing is deferred.
 func NewAny(data []byte) (*AnyJitJSON, error) {
 	var a = &AnyJitJSON{}
+	if !ScanValid(data) {
+		return a, errors.New("invalid json")
+	}
 	err := a.UnmarshalJSON(data)
 	return a, err
 }
 
+// stringMaxBytes is the length at which String cuts its output short.
+const stringMaxBytes = 4 << 10
+
+// String returns the value as indented JSON, cut short with an ellipsis after 4 KiB so
+// that printing a large document does not flood logs and debuggers. Use StringN to
+// choose the limit.
 func (a *AnyJitJSON) String() string {
-	var prettyJSON bytes.Buffer
-	err := json.Indent(&prettyJSON, a.data, "", "  ")
-	if err != nil {
-		return string(a.data)
+	return a.StringN(stringMaxBytes)
+}
+
+// StringN returns the value as indented JSON of at most maxBytes, cut at a UTF-8
+// character boundary and ending with an ellipsis if it is longer. Only as much of the
+// document as is needed is formatted. A maxBytes of zero or less means no limit.
+func (a *AnyJitJSON) StringN(maxBytes int) string {
+	data, _ := a.encoded()
+	if maxBytes <= 0 {
+		maxBytes = math.MaxInt - 1
+	}
+	return truncate(appendIndent(nil, data, maxBytes), maxBytes)
+}
+
+// GoString returns a description of the value and its parse state for the %#v verb,
+// without its contents: its type, the size of its encoding, and whether it has been
+// parsed.
+func (a *AnyJitJSON) GoString() string {
+	if a == nil {
+		return "(*jitjson.AnyJitJSON)(nil)"
+	}
+	parsed := false
+	switch val := a.val.(type) {
+	case []*AnyJitJSON:
+		parsed = val != nil
+	case map[string]*AnyJitJSON:
+		parsed = val != nil
+	case *JitJSON[bool]:
+		parsed = val.val != nil
+	case *JitJSON[json.Number]:
+		parsed = val.val != nil
+	case *JitJSON[string]:
+		parsed = val.val != nil
+	case nil:
+		parsed = true
+	}
+	return fmt.Sprintf("&jitjson.AnyJitJSON{Type: %v, Bytes: %d, Parsed: %t}", a.Type(), len(a.data), parsed)
+}
+
+// appendIndent appends the JSON data to dst indented by two spaces per level, as by
+// json.Indent, stopping once dst exceeds limit bytes. The data is not validated.
+func appendIndent(dst, data []byte, limit int) []byte {
+	depth := 0
+	newline := func() {
+		dst = append(dst, '\n')
+		for range depth {
+			dst = append(dst, "  "...)
+		}
+	}
+	for i := 0; i < len(data) && len(dst) <= limit; i++ {
+		switch c := data[i]; c {
+		case ' ', '\t', '\n', '\r':
+		case '"':
+			end := stringEnd(data, i)
+			if end < 0 {
+				return append(dst, data[i:]...)
+			}
+			dst = append(dst, data[i:end]...)
+			i = end - 1
+		case '{', '[':
+			dst = append(dst, c)
+			if j := skipSpace(data, i+1); j < len(data) && (data[j] == '}' || data[j] == ']') {
+				dst = append(dst, data[j])
+				i = j
+				continue
+			}
+			depth++
+			newline()
+		case '}', ']':
+			depth--
+			newline()
+			dst = append(dst, c)
+		case ',':
+			dst = append(dst, c)
+			newline()
+		case ':':
+			dst = append(dst, ": "...)
+		default:
+			dst = append(dst, c)
+		}
 	}
-	return prettyJSON.String()
+	return dst
 }
 
 // Type returns the ValueType of the current AnyJitJSON value. This method can be used
@@ -198,103 +296,215 @@ func (a *AnyJitJSON) AsString() (string, bool) {
 }
 
 // AsArray returns a []*AnyJitJSON from AnyJitJSON if possible.
-// This method will return false if the value is not an array.
+// This method will return false if the value is not an array. The elements
+// reference the array's underlying bytes, so no data is copied.
 func (a *AnyJitJSON) AsArray() ([]*AnyJitJSON, bool) {
-	if a.data == nil {
+	arr, ok := a.val.([]*AnyJitJSON)
+	if !ok {
 		return nil, false
 	}
-	if _, ok := a.val.([]*AnyJitJSON); !ok {
-		return nil, false
+	if arr != nil {
+		return arr, true
 	}
 
-	var arr []*AnyJitJSON
-	if err := json.Unmarshal(a.data, &arr); err != nil {
+	elems, ok := splitArray(a.data)
+	if !ok {
 		return nil, false
 	}
+	nodes := make([]AnyJitJSON, len(elems))
+	arr = make([]*AnyJitJSON, len(elems))
+	for i, elem := range elems {
+		if err := nodes[i].set(elem); err != nil {
+			return nil, false
+		}
+		arr[i] = &nodes[i]
+	}
 
-	a.data = nil
+	a.val = arr
 	return arr, true
 }
 
 // AsObject returns a map[string]*AnyJitJSON from AnyJitJSON if possible.
-// This method will return false if the value is not an object.
+// This method will return false if the value is not an object. The members
+// reference the object's underlying bytes, so no data is copied.
 func (a *AnyJitJSON) AsObject() (map[string]*AnyJitJSON, bool) {
-	if a.data == nil {
+	obj, ok := a.val.(map[string]*AnyJitJSON)
+	if !ok {
 		return nil, false
 	}
-	if _, ok := a.val.(map[string]*AnyJitJSON); !ok {
-		return nil, false
+	if obj != nil {
+		return obj, true
 	}
 
-	var obj map[string]*AnyJitJSON
-	if err := json.Unmarshal(a.data, &obj); err != nil {
+	obj = map[string]*AnyJitJSON{}
+	ok = splitObject(a.data, func(key, val []byte) bool {
+		k, err := unquote(key)
+		if err != nil {
+			return false
+		}
+		member := &AnyJitJSON{}
+		if err := member.set(val); err != nil {
+			return false
+		}
+		obj[k] = member
+		return true
+	})
+	if !ok {
 		return nil, false
 	}
 
-	a.data = nil
+	a.val = obj
 	return obj, true
 }
 
+// ToInterface fully decodes AnyJitJSON into plain Go values, as json.Unmarshal does into
+// an interface value: map[string]any for objects, []any for arrays, float64 for numbers,
+// and bool, string or nil for the other types. It suits libraries such as template
+// engines that require vanilla maps, without a round trip through json.Marshal. Elements
+// already decoded by the As methods are reused.
+func (a *AnyJitJSON) ToInterface() (any, error) {
+	switch a.Type() {
+	case TypeNull:
+		return nil, nil
+	case TypeBool:
+		b, err := a.val.(*JitJSON[bool]).Unmarshal()
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	case TypeNumber:
+		n, err := a.val.(*JitJSON[json.Number]).Unmarshal()
+		if err != nil {
+			return nil, err
+		}
+		return n.Float64()
+	case TypeString:
+		s, err := a.val.(*JitJSON[string]).Unmarshal()
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case TypeArray:
+		arr, ok := a.AsArray()
+		if !ok {
+			return nil, errors.New("invalid json")
+		}
+		out := make([]any, len(arr))
+		for i, elem := range arr {
+			v, err := elem.ToInterface()
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case TypeObject:
+		obj, ok := a.AsObject()
+		if !ok {
+			return nil, errors.New("invalid json")
+		}
+		out := make(map[string]any, len(obj))
+		for k, member := range obj {
+			v, err := member.ToInterface()
+			if err != nil {
+				return nil, err
+			}
+			out[k] = v
+		}
+		return out, nil
+	}
+	return nil, errors.New("invalid json")
+}
+
+// unquote decodes a raw JSON string, avoiding the decoder when there are no escapes.
+// Strings the decoder would reject or rewrite, such as those with control characters
+// or invalid UTF-8, are still passed to it so the results agree.
+func unquote(raw []byte) (string, error) {
+	if bytes.IndexByte(raw, '\\') < 0 && scanString(raw, 0) == len(raw) && utf8.Valid(raw) {
+		return string(raw[1 : len(raw)-1]), nil
+	}
+	var s string
+	err := json.Unmarshal(raw, &s)
+	return s, err
+}
+
 // MarshalJSON returns the JSON encoding of the value.
 func (a *AnyJitJSON) MarshalJSON() ([]byte, error) {
-	return a.data, nil
+	if a == nil {
+		return []byte("null"), nil
+	}
+	return a.encoded()
 }
 
 // UnmarshalJSON parses the JSON data and stores the value in AnyJitJSON. The method
 // supports all valid JSON value types (null, boolean, number, string, array, object).
+// Arrays and objects are copied once; their elements share the copied buffer. Data
+// exceeding the limits set by Configure is rejected.
 func (a *AnyJitJSON) UnmarshalJSON(data []byte) error {
+	if err := (*options)(nil).checkLimits(data); err != nil {
+		return err
+	}
+	if i := skipSpace(data, 0); i < len(data) && (data[i] == '[' || data[i] == '{') {
+		buf := make([]byte, len(data))
+		copy(buf, data)
+		data = buf
+	}
+	return a.set(data)
+}
+
+// isContainer reports whether data is delimited by the open and close brackets, ignoring
+// surrounding whitespace. The contents are not scanned, which matters for large documents.
+func isContainer(data []byte, open, close byte) bool {
+	i, j := skipSpace(data, 0), len(data)-1
+	for j > i && isSpace(data[j]) {
+		j--
+	}
+	return j > i && data[i] == open && data[j] == close
+}
+
+// set classifies data and stores it in AnyJitJSON without copying. The first
+// significant byte selects which pattern is checked, so each value is only
+// matched against a single regular expression.
+func (a *AnyJitJSON) set(data []byte) error {
 	a.val = nil
 	a.data = data
-	var err error
+	a.storePaths(nil)
+
+	var first byte
+	if i := skipSpace(data, 0); i < len(data) {
+		first = data[i]
+	}
 
+	switch {
 	// if the value is null
-	if nullRegex.Match(data) {
-		a.val = nil
+	case first == 'n' && nullRegex.Match(data):
 		return nil
-	}
 
 	// if the value is a boolean
-	if boolRegex.Match(data) {
-		var b JitJSON[bool]
-		if err = json.Unmarshal(data, &b); err == nil {
-			a.val = &b
-			return nil
-		}
-	}
+	case (first == 't' || first == 'f') && boolRegex.Match(data):
+		a.val = NewFromBytes[bool](data)
+		return nil
 
 	// if the value is an number
-	if numberRegex.Match(data) {
-		var num JitJSON[json.Number]
-		if err = json.Unmarshal(data, &num); err == nil {
-			a.val = &num
-			return nil
-		}
-	}
+	case (first == '-' || first >= '0' && first <= '9') && numberRegex.Match(data):
+		a.val = NewFromBytes[json.Number](data)
+		return nil
 
 	// if the value is a string
-	if stringRegex.Match(data) {
-		var str JitJSON[string]
-		if err = json.Unmarshal(data, &str); err == nil {
-			a.val = &str
-			return nil
-		}
-	}
+	case first == '"' && stringRegex.Match(data):
+		a.val = NewFromBytes[string](data)
+		return nil
 
 	// if the value is an array
-	if arrayRegex.Match(data) {
-		a.val = []*AnyJitJSON{}
-		a.data = make([]byte, len(data))
-		copy(a.data, data)
+	case first == '[' && isContainer(data, '[', ']'):
+		a.val = []*AnyJitJSON(nil)
 		return nil
-	}
 
 	// if the value is an object
-	if objectRegex.Match(data) {
-		a.val = map[string]*AnyJitJSON{}
-		a.data = make([]byte, len(data))
-		copy(a.data, data)
+	case first == '{' && isContainer(data, '{', '}'):
+		a.val = map[string]*AnyJitJSON(nil)
 		return nil
 	}
 
-	return fmt.Errorf("invalid json: %w", err)
+	return errors.New("invalid json")
 }
@@ -1,8 +1,13 @@
 package jitjson
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"unsafe"
 )
 
 func TestUnmarshalJSON_TypeMatching(t *testing.T) {
@@ -314,3 +319,164 @@ func TestAnyJitJSON_Type(t *testing.T) {
 		}
 	})
 }
+
+func TestAnyJitJSON_SharedBuffer(t *testing.T) {
+	data := []byte(`{"arr": [1, "two", {"three": 3}], "str": "value"}`)
+	var a AnyJitJSON
+	if err := json.Unmarshal(data, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, ok := a.AsObject()
+	if !ok {
+		t.Fatal("expected object type")
+	}
+	arr, ok := obj["arr"].AsArray()
+	if !ok {
+		t.Fatal("expected array type")
+	}
+
+	within := func(child []byte) bool {
+		start := &a.data[0]
+		end := &a.data[len(a.data)-1]
+		p := &child[0]
+		return uintptr(unsafe.Pointer(p)) >= uintptr(unsafe.Pointer(start)) &&
+			uintptr(unsafe.Pointer(p)) <= uintptr(unsafe.Pointer(end))
+	}
+	for i, elem := range arr {
+		if !within(elem.data) {
+			t.Errorf("index %d: element does not share the parent buffer", i)
+		}
+	}
+
+	again, ok := obj["arr"].AsArray()
+	if !ok || len(again) != len(arr) {
+		t.Fatal("expected repeated AsArray to return the cached elements")
+	}
+	if again[0] != arr[0] {
+		t.Error("expected repeated AsArray to reuse elements")
+	}
+
+	out, err := a.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("expected %s, got %s", data, out)
+	}
+}
+
+func TestAnyJitJSON_MalformedContainers(t *testing.T) {
+	for _, input := range []string{`[1, 2`, `[1 2]`, `{"a" 1}`, `{"a": 1,}`, `[}`} {
+		t.Run(input, func(t *testing.T) {
+			a, err := NewAny([]byte(input))
+			if err != nil {
+				return
+			}
+			if _, ok := a.AsArray(); ok {
+				t.Error("expected AsArray to fail")
+			}
+			if _, ok := a.AsObject(); ok {
+				t.Error("expected AsObject to fail")
+			}
+		})
+	}
+}
+
+func TestAnyJitJSON_ToInterface(t *testing.T) {
+	data := []byte(`{"name": "John", "age": 30, "tags": ["a", true, null], "meta": {"score": 1.5}}`)
+
+	a, err := NewAny(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.AsObject(); !ok {
+		t.Fatal("expected an object")
+	}
+	got, err := a.ToInterface()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want any
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	built, err := NewAnyFromValue(map[string]any{"ids": []int{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = built.ToInterface()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]any{"ids": []any{1.0, 2.0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	var bad AnyJitJSON
+	if err := bad.UnmarshalJSON([]byte(`{"a": [1 2]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bad.ToInterface(); err == nil {
+		t.Error("expected an error for malformed nested data")
+	}
+}
+
+func TestAnyJitJSON_String(t *testing.T) {
+	for _, input := range []string{
+		`{"a": [1, "x,:{", {}], "b": {"c": null, "d": []}, "e": "\"q\""}`,
+		`[ ]`,
+		`"text"`,
+		`  42 `,
+	} {
+		a, err := NewAny([]byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want bytes.Buffer
+		if err := json.Indent(&want, []byte(strings.TrimSpace(input)), "", "  "); err != nil {
+			t.Fatal(err)
+		}
+		if got := a.String(); got != want.String() {
+			t.Errorf("expected %q, got %q", want.String(), got)
+		}
+	}
+
+	large, err := NewAny([]byte(`[` + strings.Repeat(`"ééé",`, 10000) + `0]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := large.String()
+	if len(s) > stringMaxBytes+len("…") || !strings.HasSuffix(s, "…") {
+		t.Errorf("expected String to be cut short, got %d bytes", len(s))
+	}
+	if s := large.StringN(10); s != "[\n  \"éé…" {
+		t.Errorf("expected a 10 byte prefix, got %q", s)
+	}
+	if s := large.StringN(0); strings.HasSuffix(s, "…") || !strings.HasSuffix(s, "0\n]") {
+		t.Error("expected StringN(0) not to cut the output")
+	}
+}
+
+func TestAnyJitJSON_GoString(t *testing.T) {
+	a, err := NewAny([]byte(`{"a": 1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprintf("%#v", a); s != "&jitjson.AnyJitJSON{Type: TypeObject, Bytes: 8, Parsed: false}" {
+		t.Errorf("unexpected %s", s)
+	}
+	obj, _ := a.AsObject()
+	if s := fmt.Sprintf("%#v", a); !strings.HasSuffix(s, "Parsed: true}") {
+		t.Errorf("expected the object to be parsed, got %s", s)
+	}
+	if s := fmt.Sprintf("%#v", obj["a"]); s != "&jitjson.AnyJitJSON{Type: TypeNumber, Bytes: 1, Parsed: false}" {
+		t.Errorf("unexpected %s", s)
+	}
+}
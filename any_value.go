@@ -0,0 +1,186 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// NewAnyFromValue builds an AnyJitJSON tree from a Go value, so dynamic documents can be
+// assembled programmatically and read with the As methods or emitted without an eager
+// json.Marshal of the whole value:
+//
+//	doc, err := jitjson.NewAnyFromValue(map[string]any{
+//		"id":    42,
+//		"tags":  []string{"a", "b"},
+//		"order": orderJit, // a *JitJSON[Order] holding received bytes
+//	})
+//	data, err := json.Marshal(doc)
+//
+// Maps with string keys, slices and arrays become objects and arrays, and booleans,
+// numbers and strings become scalars, following the encoding/json rules for each. No
+// encoding is performed until MarshalJSON, which encodes each branch once and caches
+// it. *AnyJitJSON values are used as they are, and other values implementing
+// json.Marshaler, such as *JitJSON[T], splice in their MarshalJSON result. Values of
+// any other type, such as structs, are marshaled when the tree is built.
+func NewAnyFromValue(v any) (*AnyJitJSON, error) {
+	if a, ok := v.(*AnyJitJSON); ok && a != nil {
+		return a, nil
+	}
+	a := &AnyJitJSON{}
+	if m, ok := v.(json.Marshaler); ok && !isNilPointer(v) {
+		data, err := m.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			data = []byte("null")
+		}
+		return a, a.set(data)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return a, a.set([]byte("null"))
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return a, a.set([]byte("null"))
+		}
+		return NewAnyFromValue(rv.Elem().Interface())
+	case reflect.Bool:
+		a.val = New(rv.Bool())
+		return a, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		a.val = New(json.Number(strconv.FormatInt(rv.Int(), 10)))
+		return a, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		a.val = New(json.Number(strconv.FormatUint(rv.Uint(), 10)))
+		return a, nil
+	case reflect.String:
+		if n, ok := v.(json.Number); ok {
+			if !isNumberLiteral([]byte(n)) {
+				return nil, &json.UnsupportedValueError{Value: rv, Str: string(n)}
+			}
+			a.val = New(n)
+			return a, nil
+		}
+		a.val = New(rv.String())
+		return a, nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return a, a.set([]byte("null"))
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break // encoded as base64 by encoding/json
+		}
+		fallthrough
+	case reflect.Array:
+		arr := make([]*AnyJitJSON, rv.Len())
+		for i := range arr {
+			elem, err := NewAnyFromValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = elem
+		}
+		a.val = arr
+		return a, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return a, a.set([]byte("null"))
+		}
+		obj := make(map[string]*AnyJitJSON, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			member, err := NewAnyFromValue(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			obj[iter.Key().String()] = member
+		}
+		a.val = obj
+		return a, nil
+	}
+
+	// floats are formatted as by encoding/json, and other types are marshaled now
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return a, a.set(data)
+}
+
+// isNilPointer reports whether v is a nil pointer.
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// encoded returns the JSON encoding of a. Trees built by NewAnyFromValue are encoded
+// on first use, and the encoding is cached.
+func (a *AnyJitJSON) encoded() ([]byte, error) {
+	if a.data != nil || a.val == nil {
+		return a.data, nil
+	}
+
+	var data []byte
+	switch val := a.val.(type) {
+	case json.Marshaler:
+		b, err := val.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	case []*AnyJitJSON:
+		data = append(data, '[')
+		for i, elem := range val {
+			if i > 0 {
+				data = append(data, ',')
+			}
+			b, err := elem.encoded()
+			if err != nil {
+				return nil, err
+			}
+			data = appendValue(data, b)
+		}
+		data = append(data, ']')
+	case map[string]*AnyJitJSON:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		data = append(data, '{')
+		for i, k := range keys {
+			if i > 0 {
+				data = append(data, ',')
+			}
+			var err error
+			if data, err = appendString(data, k); err != nil {
+				return nil, err
+			}
+			data = append(data, ':')
+			b, err := val[k].encoded()
+			if err != nil {
+				return nil, err
+			}
+			data = appendValue(data, b)
+		}
+		data = append(data, '}')
+	}
+	a.data = data
+	return data, nil
+}
+
+// appendValue appends the encoding b to dst, writing null for an empty encoding.
+func appendValue(dst, b []byte) []byte {
+	if b == nil {
+		return append(dst, "null"...)
+	}
+	return append(dst, b...)
+}
@@ -0,0 +1,79 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestNewAnyFromValue(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	lazy := NewFromBytes[point]([]byte(`{"X": 1, "Y": 2}`))
+	raw, err := NewAny([]byte(`[true, null]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := map[string]interface{}{
+		"id":     42,
+		"ratio":  0.5,
+		"name":   "doc",
+		"ok":     true,
+		"none":   nil,
+		"tags":   []string{"a", "b"},
+		"empty":  []int{},
+		"nilMap": map[string]int(nil),
+		"nested": map[string]interface{}{"n": json.Number("1e3")},
+		"point":  point{3, 4},
+		"lazy":   lazy,
+		"raw":    raw,
+		"bytes":  []byte("hi"),
+	}
+
+	a, err := NewAnyFromValue(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.data != nil {
+		t.Error("expected no encoding until MarshalJSON")
+	}
+
+	obj, ok := a.AsObject()
+	if !ok {
+		t.Fatalf("expected object, got %v", a.Type())
+	}
+	if n, ok := obj["id"].AsNumber(); !ok || n != "42" {
+		t.Errorf("expected 42, got %v", n)
+	}
+	if tags, ok := obj["tags"].AsArray(); !ok || len(tags) != 2 {
+		t.Errorf("expected two tags, got %v", tags)
+	}
+	if !obj["none"].IsNull() || !obj["nilMap"].IsNull() {
+		t.Error("expected nil values to be null")
+	}
+	if p, ok := obj["point"].AsObject(); !ok || len(p) != 2 {
+		t.Errorf("expected point object, got %v", obj["point"].Type())
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(map[string]interface{}{
+		"id": 42, "ratio": 0.5, "name": "doc", "ok": true, "none": nil, "tags": []string{"a", "b"},
+		"empty": []int{}, "nilMap": nil, "nested": map[string]interface{}{"n": json.Number("1e3")},
+		"point": point{3, 4}, "bytes": []byte("hi"),
+		"lazy": json.RawMessage(`{"X": 1, "Y": 2}`), "raw": json.RawMessage(`[true, null]`),
+	})
+	if string(data) != string(want) {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	if _, err := NewAnyFromValue(math.Inf(1)); err == nil {
+		t.Error("expected error for unsupported float")
+	}
+	if _, err := NewAnyFromValue(json.Number("x")); err == nil {
+		t.Error("expected error for invalid number")
+	}
+}
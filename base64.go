@@ -0,0 +1,127 @@
+package jitjson
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Base64[T] holds a JSON string whose content is a base64 encoded JSON document, as
+// carried in the data members of Pub/Sub messages and CloudEvents envelopes. Neither the
+// string nor the document is decoded when the envelope is unmarshaled; both are decoded
+// only when the value is requested with Get or Inner:
+//
+//	type PushRequest struct {
+//		Message struct {
+//			Data jitjson.Base64[Order] `json:"data"`
+//		} `json:"message"`
+//	}
+//
+// Standard and URL-safe base64, with or without padding, are accepted. Base64[T]
+// marshals the string it was unmarshaled from as is, or the value set with Set encoded
+// as standard base64.
+type Base64[T any] struct {
+	raw  []byte
+	jit  *JitJSON[T]
+	opts *options
+}
+
+// NewBase64 creates Base64[T] from a value. The options apply to the inner document.
+func NewBase64[T any](val T, opts ...Option) *Base64[T] {
+	o := newOptions(opts)
+	return &Base64[T]{jit: &JitJSON[T]{val: &val, opts: o}, opts: o}
+}
+
+// Get returns the value of the inner document, decoding the string and the document on
+// first use. The zero value of T is returned if Base64[T] is empty or null.
+func (b *Base64[T]) Get() (T, error) {
+	jit, err := b.Inner()
+	if err != nil || jit == nil {
+		var zero T
+		return zero, err
+	}
+	return jit.Unmarshal()
+}
+
+// Inner returns the inner document as a JitJSON[T], decoding only the string. It returns
+// nil if Base64[T] is empty or null.
+func (b *Base64[T]) Inner() (*JitJSON[T], error) {
+	if b.jit != nil || b.raw == nil {
+		return b.jit, nil
+	}
+	s, err := unquote(b.raw)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeBase64(s)
+	if err != nil {
+		return nil, fmt.Errorf("jitjson: decoding base64 document: %w", err)
+	}
+	recordDeferredUnmarshal(len(data))
+	jit := &JitJSON[T]{opts: b.opts}
+	if err := jit.setData(data); err != nil {
+		return nil, err
+	}
+	b.jit = jit
+	return jit, nil
+}
+
+// Set Base64[T] to a new value.
+func (b *Base64[T]) Set(val T) {
+	if b.jit == nil {
+		b.jit = &JitJSON[T]{opts: b.opts}
+	}
+	b.jit.Set(val)
+	b.raw = nil
+}
+
+// MarshalJSON marshals Base64[T] as a JSON string of the base64 encoded document.
+func (b Base64[T]) MarshalJSON() ([]byte, error) {
+	if b.raw != nil {
+		return b.raw, nil
+	}
+	if b.jit == nil {
+		return []byte("null"), nil
+	}
+	data, err := b.jit.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(data))
+}
+
+// UnmarshalJSON stores the JSON string data to be decoded later. Data exceeding the
+// limits set by WithMaxBytes and WithMaxDepth, or by Configure, is rejected.
+func (b *Base64[T]) UnmarshalJSON(data []byte) error {
+	if b.opts == nil {
+		b.opts = defaults.Load()
+	}
+	data, err := b.opts.accept(data)
+	if err != nil {
+		return err
+	}
+	b.jit = nil
+	if scanLiteral(data, 0, "null") == len(data) {
+		b.raw = nil
+		return nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		return &TypeError{Err: errors.New("jitjson: base64 document is not a json string")}
+	}
+	if b.opts != nil && b.opts.copyData {
+		data = append([]byte(nil), data...)
+	}
+	b.raw = data
+	return nil
+}
+
+// decodeBase64 decodes s as standard or URL-safe base64, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
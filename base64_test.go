@@ -0,0 +1,93 @@
+package jitjson_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type pushRequest struct {
+	Message struct {
+		ID   string                 `json:"id"`
+		Data jitjson.Base64[Person] `json:"data"`
+	} `json:"message"`
+}
+
+func TestBase64(t *testing.T) {
+	inner := base64.StdEncoding.EncodeToString([]byte(`{"Name": "John", "Age": 30}`))
+	body := []byte(`{"message": {"id": "1", "data": "` + inner + `"}}`)
+
+	var req pushRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"message":{"id":"1","data":"` + inner + `"}}`; string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+
+	// a payload that does not decode into T fails only when it is read
+	bad := base64.StdEncoding.EncodeToString([]byte(`{"Age": "thirty"}`))
+	var lazy pushRequest
+	if err := json.Unmarshal([]byte(`{"message": {"data": "`+bad+`"}}`), &lazy); err != nil {
+		t.Errorf("expected no decoding, got %v", err)
+	}
+	if _, err := lazy.Message.Data.Get(); err == nil {
+		t.Error("expected the decode error on Get")
+	}
+
+	p, err := req.Message.Data.Get()
+	if err != nil || p.Name != "John" || p.Age != 30 {
+		t.Errorf("unexpected value %v, %v", p, err)
+	}
+
+	p.Age++
+	req.Message.Data.Set(p)
+	out, err = req.Message.Data.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s string
+	if err := json.Unmarshal(out, &s); err != nil {
+		t.Fatal(err)
+	}
+	decoded, _ := base64.StdEncoding.DecodeString(s)
+	if want := `{"Name":"John","Age":31,"City":""}`; string(decoded) != want {
+		t.Errorf("expected %s, got %s", want, decoded)
+	}
+}
+
+func TestBase64Encodings(t *testing.T) {
+	doc := []byte(`{"Name": "Zoë?>"}`)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		var b jitjson.Base64[Person]
+		if err := b.UnmarshalJSON([]byte(`"` + enc.EncodeToString(doc) + `"`)); err != nil {
+			t.Fatal(err)
+		}
+		if p, err := b.Get(); err != nil || p.Name != "Zoë?>" {
+			t.Errorf("unexpected value %v, %v", p, err)
+		}
+	}
+
+	var b jitjson.Base64[Person]
+	if err := b.UnmarshalJSON([]byte(`null`)); err != nil {
+		t.Fatal(err)
+	}
+	if jit, err := b.Inner(); jit != nil || err != nil {
+		t.Errorf("expected no document for null, got %v, %v", jit, err)
+	}
+	if err := b.UnmarshalJSON([]byte(`{"Name": "John"}`)); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+	if err := b.UnmarshalJSON([]byte(`"not base64!"`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get(); err == nil {
+		t.Error("expected an error for invalid base64")
+	}
+}
@@ -1,6 +1,7 @@
 package jitjson_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -316,3 +317,167 @@ func BenchmarkNestedParse(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkAnyJitJSON benchmarks walking the large fixture with AnyJitJSON, where
+// every nested element is a view into the single buffer copied at unmarshal time.
+func BenchmarkAnyJitJSON(b *testing.B) {
+	b.Run("AnyJitJSON/Large", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var any jitjson.AnyJitJSON
+			err := json.Unmarshal(largeData, &any)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			arr, ok := any.AsArray()
+			if !ok {
+				b.Fatal("not an array")
+			}
+			for _, elem := range arr {
+				obj, ok := elem.AsObject()
+				if !ok {
+					b.Fatal("not an object")
+				}
+				if _, ok := obj["slice"].AsArray(); !ok {
+					b.Fatal("not an array")
+				}
+			}
+		}
+	})
+
+	b.Run("Stdlib/Large", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var arr []map[string]json.RawMessage
+			err := json.Unmarshal(largeData, &arr)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, obj := range arr {
+				var slice []json.RawMessage
+				err := json.Unmarshal(obj["slice"], &slice)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkScanValid compares ScanValid to json.Valid on the large fixture.
+func BenchmarkScanValid(b *testing.B) {
+	b.Run("ScanValid/Large", func(b *testing.B) {
+		b.SetBytes(int64(len(largeData)))
+		for i := 0; i < b.N; i++ {
+			if !jitjson.ScanValid(largeData) {
+				b.Fatal("invalid json")
+			}
+		}
+	})
+
+	b.Run("Stdlib/Large", func(b *testing.B) {
+		b.SetBytes(int64(len(largeData)))
+		for i := 0; i < b.N; i++ {
+			if !json.Valid(largeData) {
+				b.Fatal("invalid json")
+			}
+		}
+	})
+}
+
+// BenchmarkEagerThreshold compares decoding a small object deferred, eagerly below the
+// threshold set by WithEagerThreshold, and with encoding/json.
+func BenchmarkEagerThreshold(b *testing.B) {
+	data := []byte(`{"Name": "John", "Age": 30, "City": "New York"}`)
+
+	b.Run("Deferred/Small", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			jit := jitjson.NewFromBytes[Person](data)
+			if _, err := jit.Unmarshal(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Eager/Small", func(b *testing.B) {
+		jitjson.Configure(jitjson.Config{EagerThreshold: 256})
+		defer jitjson.Configure(jitjson.Config{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			jit := jitjson.NewFromBytes[Person](data)
+			if _, err := jit.Unmarshal(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Stdlib/Small", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var p Person
+			if err := json.Unmarshal(data, &p); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkUnmarshalValue compares Unmarshal, which stores the decoded value behind a
+// *T, with UnmarshalValue, which returns it by copy.
+func BenchmarkUnmarshalValue(b *testing.B) {
+	var arr []*jitjson.JitJSON[Person]
+	data := []byte(`[{"Name": "John", "Age": 30}, {"Name": "Jane", "Age": 25}]`)
+	if err := json.Unmarshal(data, &arr); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Unmarshal/Small", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, jit := range arr {
+				jit.Release()
+				if _, err := jit.Unmarshal(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("UnmarshalValue/Small", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, jit := range arr {
+				jit.Release()
+				if _, err := jit.UnmarshalValue(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"items": [`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, `{"id": %d, "name": "item %d", "tags": ["a", "b"]}`, i, i)
+	}
+	buf.WriteString(`], "status": "new"}`)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		doc, err := jitjson.NewAny(buf.Bytes())
+		if err != nil {
+			b.Fatal(err)
+		}
+		err = doc.Update(func(tx *jitjson.Tx) error {
+			return tx.Set("status", "shipped")
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
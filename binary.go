@@ -0,0 +1,89 @@
+package jitjson
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// binaryVersion is the version of the binary encoding produced by MarshalBinary.
+const binaryVersion = 1
+
+// MarshalBinary implements encoding.BinaryMarshaler, which is also used by encoding/gob.
+// The encoding holds the name of the value's parser and its raw encoded bytes, so the
+// value can be cached or sent between processes and rehydrated still lazy. A value
+// without bytes is marshaled with its parser first.
+func (jit *JitJSON[T]) MarshalBinary() ([]byte, error) {
+	data, err := jit.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	name := jit.opts.codecName()
+	buf := make([]byte, 0, 2+binary.MaxVarintLen64+len(name)+len(data))
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(name)))
+	buf = append(buf, name...)
+	if data == nil {
+		return append(buf, 0), nil
+	}
+	buf = append(buf, 1)
+	return append(buf, data...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, which is also used by
+// encoding/gob. The encoded bytes are stored without decoding, using the parser
+// recorded by MarshalBinary, which must be registered in this process.
+func (jit *JitJSON[T]) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 || buf[0] != binaryVersion {
+		return errors.New("jitjson: unsupported binary encoding")
+	}
+	n, size := binary.Uvarint(buf[1:])
+	if size <= 0 || n >= uint64(len(buf)-1-size) {
+		return errors.New("jitjson: truncated binary encoding")
+	}
+	i := 1 + size
+	name := string(buf[i : i+int(n)])
+	i += int(n)
+
+	if name != jit.opts.codecName() {
+		if _, ok := LookupParser(name); !ok {
+			return fmt.Errorf("jitjson: parser %q is not registered", name)
+		}
+		jit.SetOptions(WithParser(name))
+	}
+
+	jit.val = nil
+	jit.data = nil
+	jit.dropEncoding()
+	if buf[i] == 0 {
+		return nil
+	}
+	data := append([]byte(nil), buf[i+1:]...)
+	recordDeferredUnmarshal(len(data))
+	return jit.setData(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which is also used by encoding/gob.
+// The encoding holds the raw JSON bytes.
+func (a *AnyJitJSON) MarshalBinary() ([]byte, error) {
+	data, err := a.encoded()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, 1+len(data))
+	buf = append(buf, binaryVersion)
+	return append(buf, data...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, which is also used by
+// encoding/gob. The JSON bytes are stored without being parsed beyond their type.
+func (a *AnyJitJSON) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 1 || buf[0] != binaryVersion {
+		return errors.New("jitjson: unsupported binary encoding")
+	}
+	if len(buf) == 1 {
+		return a.set([]byte("null"))
+	}
+	return a.set(append([]byte(nil), buf[1:]...))
+}
@@ -0,0 +1,101 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type cacheEntry struct {
+	Key    string
+	Person *jitjson.JitJSON[Person]
+	Extra  *jitjson.AnyJitJSON
+}
+
+func TestJitJSON_Gob(t *testing.T) {
+	extra, err := jitjson.NewAny([]byte(`{"tags": ["a", "b"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := cacheEntry{
+		Key:    "john",
+		Person: jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`)),
+		Extra:  extra,
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out cacheEntry
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Person.DebugState().HasValue {
+		t.Error("expected value to be decoded lazily after rehydration")
+	}
+	p, err := out.Person.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" || p.Age != 30 {
+		t.Error("values do not match")
+	}
+	if out.Extra.Type() != jitjson.TypeObject {
+		t.Errorf("expected TypeObject, got %v", out.Extra.Type())
+	}
+}
+
+func TestJitJSON_Binary(t *testing.T) {
+	parser := &countingParser{}
+	registerParser(t, "binary-test", parser)
+
+	jit := jitjson.New(Person{Name: "Jane"}, jitjson.WithParser("binary-test"))
+	data, err := jit.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out jitjson.JitJSON[Person]
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := out.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+	if parser.unmarshals != 1 {
+		t.Error("expected the recorded parser to be restored")
+	}
+
+	var empty jitjson.JitJSON[Person]
+	data, err = empty.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := out.Unmarshal(); p != (Person{}) {
+		t.Error("expected empty value")
+	}
+
+	if err := out.UnmarshalBinary([]byte{1, 4, 'n', 'o', 'p', 'e', 0}); err == nil {
+		t.Error("expected error for unregistered parser")
+	}
+	if err := out.UnmarshalBinary([]byte{9}); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+	for _, buf := range [][]byte{
+		{1, 10, 'a'},
+		{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0},
+		{1, 1, 'a'},
+	} {
+		if err := out.UnmarshalBinary(buf); err == nil {
+			t.Errorf("%v: expected error for truncated encoding", buf)
+		}
+	}
+}
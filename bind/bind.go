@@ -0,0 +1,96 @@
+// Package bind provides JitJSON-aware request binders and response renderers for web
+// frameworks, so lazily-held values pass through as raw bytes without a decode/encode
+// round trip. The types only depend on net/http and satisfy the frameworks' interfaces
+// structurally, so no framework is imported. The jitgin, jitecho and jitfiber modules
+// build on them to plug into gin, echo and fiber:
+//
+//	jitgin.ShouldBind(c, &jit)            // gin, with bind.JSON
+//	jitgin.JSON(c, http.StatusOK, &jit)   // gin, with bind.Render
+//
+//	e.JSONSerializer = jitecho.Serializer{}            // echo: c.Bind and c.JSON
+//	app := fiber.New(jitfiber.Config(fiber.Config{})) // fiber: c.BodyParser and c.JSON
+package bind
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Request binds the JSON body of r to v. When v is a jitjson.Lazy value the body is
+// stored without decoding; any other value is decoded by encoding/json. The request
+// is checked as described by jitjson.BindRequest.
+func Request(r *http.Request, v any) error {
+	jit, err := jitjson.BindRequest[json.RawMessage](r)
+	if err != nil {
+		return err
+	}
+	data, err := jit.Marshal()
+	if err != nil {
+		return err
+	}
+	if lazy, ok := v.(jitjson.Lazy); ok {
+		return lazy.UnmarshalJSON(data)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Write writes the JSON encoding of v to w with the given status code. Lazy values
+// are written from their stored bytes as-is.
+func Write(w http.ResponseWriter, status int, v any) error {
+	data, err := jitjson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// Binding is a JSON request binder that stores bodies bound to jitjson.Lazy values
+// without decoding them. It satisfies gin's binding.Binding and binding.BindingBody.
+type Binding struct{}
+
+// JSON is the Binding for JSON request bodies.
+var JSON = Binding{}
+
+// Name returns the name of the binding.
+func (Binding) Name() string {
+	return "json"
+}
+
+// Bind binds the JSON body of req to obj.
+func (Binding) Bind(req *http.Request, obj any) error {
+	return Request(req, obj)
+}
+
+// BindBody binds the JSON body to obj. The body is copied when obj is lazy.
+func (Binding) BindBody(body []byte, obj any) error {
+	return jitjson.Unmarshal(body, obj)
+}
+
+// Render writes Data as a JSON response, passing lazy values through as raw bytes.
+// It satisfies gin's render.Render.
+type Render struct {
+	Data any
+}
+
+// Render writes the JSON encoding of Data to w.
+func (r Render) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	data, err := jitjson.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteContentType sets the JSON Content-Type on w if none is set.
+func (r Render) WriteContentType(w http.ResponseWriter) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+}
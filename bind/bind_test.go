@@ -0,0 +1,74 @@
+package bind_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/bind"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestBinding(t *testing.T) {
+	jsonData := `{"Name": "John",  "Age": 30}`
+
+	t.Run("Lazy value", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(jsonData))
+		var jit jitjson.JitJSON[Person]
+		if err := bind.JSON.Bind(r, &jit); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := jit.Marshal()
+		if string(data) != jsonData {
+			t.Errorf("expected raw bytes, got %s", data)
+		}
+	})
+
+	t.Run("Plain value", func(t *testing.T) {
+		var p Person
+		if err := bind.JSON.BindBody([]byte(jsonData), &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" || p.Age != 30 {
+			t.Error("values do not match")
+		}
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"Name":`))
+		var jit jitjson.JitJSON[Person]
+		if err := bind.JSON.Bind(r, &jit); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestRender(t *testing.T) {
+	jsonData := `{"Name": "John",  "Age": 30}`
+	jit := jitjson.NewFromBytes[Person]([]byte(jsonData))
+
+	w := httptest.NewRecorder()
+	if err := (bind.Render{Data: jit}).Render(w); err != nil {
+		t.Fatal(err)
+	}
+	if w.Body.String() != jsonData {
+		t.Errorf("expected raw bytes, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if err := bind.Write(w, http.StatusCreated, jit); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated || w.Body.String() != jsonData {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("unexpected content type %s", ct)
+	}
+}
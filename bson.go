@@ -0,0 +1,610 @@
+package jitjson
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BSON element types supported by the conversion between JSON and BSON.
+const (
+	bsonDouble   byte = 0x01
+	bsonString   byte = 0x02
+	bsonDocument byte = 0x03
+	bsonArray    byte = 0x04
+	bsonBinary   byte = 0x05
+	bsonObjectID byte = 0x07
+	bsonBool     byte = 0x08
+	bsonDateTime byte = 0x09
+	bsonNull     byte = 0x0A
+	bsonInt32    byte = 0x10
+	bsonInt64    byte = 0x12
+)
+
+// MarshalBSONValue implements the bson.ValueMarshaler interface of the MongoDB Go
+// driver (v2), so JitJSON[T] fields can be stored without an intermediate Go value.
+// The JSON encoding is converted directly to BSON in a single pass. Extended JSON objects
+// for $oid, $date, $numberInt, $numberLong and $numberDouble are converted to their BSON
+// types. Integers outside the int64 range are rejected rather than rounded to doubles.
+func (jit *JitJSON[T]) MarshalBSONValue() (byte, []byte, error) {
+	data, err := jit.Marshal()
+	if err != nil {
+		return 0, nil, err
+	}
+	if data == nil {
+		return bsonNull, nil, nil
+	}
+	return jsonToBSON(data)
+}
+
+// UnmarshalBSONValue implements the bson.ValueUnmarshaler interface of the MongoDB Go
+// driver (v2). The BSON value is converted to relaxed extended JSON and stored without
+// being decoded into T until Unmarshal is called.
+func (jit *JitJSON[T]) UnmarshalBSONValue(typ byte, data []byte) error {
+	out, err := bsonToJSON(nil, typ, data)
+	if err != nil {
+		return err
+	}
+	return jit.UnmarshalJSON(out)
+}
+
+// MarshalBSON implements the bson.Marshaler interface, so JitJSON[T] holding a JSON
+// object can be passed to the driver as a top-level document.
+func (jit *JitJSON[T]) MarshalBSON() ([]byte, error) {
+	typ, data, err := jit.MarshalBSONValue()
+	if err != nil {
+		return nil, err
+	}
+	if typ != bsonDocument {
+		return nil, errors.New("bson: top-level value must be a JSON object")
+	}
+	return data, nil
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface for top-level documents.
+func (jit *JitJSON[T]) UnmarshalBSON(data []byte) error {
+	return jit.UnmarshalBSONValue(bsonDocument, data)
+}
+
+// MarshalBSONValue implements the bson.ValueMarshaler interface of the MongoDB Go
+// driver (v2). See JitJSON[T].MarshalBSONValue for details.
+func (a *AnyJitJSON) MarshalBSONValue() (byte, []byte, error) {
+	if a == nil || a.IsNull() {
+		return bsonNull, nil, nil
+	}
+	data, err := a.encoded()
+	if err != nil {
+		return 0, nil, err
+	}
+	return jsonToBSON(data)
+}
+
+// UnmarshalBSONValue implements the bson.ValueUnmarshaler interface of the MongoDB Go
+// driver (v2). The BSON value is converted to relaxed extended JSON.
+func (a *AnyJitJSON) UnmarshalBSONValue(typ byte, data []byte) error {
+	out, err := bsonToJSON(nil, typ, data)
+	if err != nil {
+		return err
+	}
+	return a.set(out)
+}
+
+// jsonToBSON converts a JSON value to a BSON element type and value. The conversion
+// is a single pass over data: nested documents are written as they are scanned and
+// their lengths filled in once they are closed.
+func jsonToBSON(data []byte) (byte, []byte, error) {
+	i := skipSpace(data, 0)
+	if i >= len(data) {
+		return 0, nil, errors.New("bson: empty json value")
+	}
+	e := bsonEncoder{data: data}
+	typ, out, i, err := e.appendValue(nil, i)
+	if err != nil {
+		return 0, nil, err
+	}
+	if skipSpace(data, i) != len(data) {
+		return 0, nil, errors.New("bson: invalid json")
+	}
+	return typ, out, nil
+}
+
+// bsonEncoder converts the JSON value in data to BSON.
+type bsonEncoder struct {
+	data []byte
+}
+
+// appendValue appends the BSON encoding of the JSON value starting at data[i] to dst,
+// and returns its BSON type and the index just past it.
+func (e *bsonEncoder) appendValue(dst []byte, i int) (byte, []byte, int, error) {
+	data := e.data
+	switch c := data[i]; {
+	case c == 'n':
+		if j := scanLiteral(data, i, "null"); j >= 0 {
+			return bsonNull, dst, j, nil
+		}
+	case c == 't':
+		if j := scanLiteral(data, i, "true"); j >= 0 {
+			return bsonBool, append(dst, 1), j, nil
+		}
+	case c == 'f':
+		if j := scanLiteral(data, i, "false"); j >= 0 {
+			return bsonBool, append(dst, 0), j, nil
+		}
+	case c == '"':
+		j := scanString(data, i)
+		if j < 0 {
+			break
+		}
+		s, err := unquote(data[i:j])
+		if err != nil {
+			return 0, nil, 0, err
+		}
+		return bsonString, appendBSONString(dst, s), j, nil
+	case c == '-' || c >= '0' && c <= '9':
+		j := scanNumber(data, i)
+		if j < 0 {
+			break
+		}
+		typ, dst, err := appendBSONNumber(dst, string(data[i:j]))
+		return typ, dst, j, err
+	case c == '[':
+		return e.appendArray(dst, i)
+	case c == '{':
+		if typ, dst, j, ok, err := e.appendExtended(dst, i); ok || err != nil {
+			return typ, dst, j, err
+		}
+		return e.appendDocument(dst, i)
+	}
+	return 0, nil, 0, fmt.Errorf("bson: invalid json value at offset %d", i)
+}
+
+// appendArray appends the JSON array starting at data[i] as a BSON array.
+func (e *bsonEncoder) appendArray(dst []byte, i int) (byte, []byte, int, error) {
+	data := e.data
+	start := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == ']' {
+		return bsonArray, closeBSONDocument(dst, start), i + 1, nil
+	}
+	for n := 0; ; n++ {
+		var err error
+		if dst, i, err = e.appendElement(dst, strconv.Itoa(n), i); err != nil {
+			return 0, nil, 0, err
+		}
+		if i = skipSpace(data, i); i >= len(data) {
+			return 0, nil, 0, errors.New("bson: invalid json array")
+		}
+		if data[i] == ']' {
+			return bsonArray, closeBSONDocument(dst, start), i + 1, nil
+		}
+		if data[i] != ',' {
+			return 0, nil, 0, errors.New("bson: invalid json array")
+		}
+		i = skipSpace(data, i+1)
+	}
+}
+
+// appendDocument appends the JSON object starting at data[i] as a BSON document.
+func (e *bsonEncoder) appendDocument(dst []byte, i int) (byte, []byte, int, error) {
+	data := e.data
+	start := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return bsonDocument, closeBSONDocument(dst, start), i + 1, nil
+	}
+	for {
+		key, j, ok := e.key(i)
+		if !ok {
+			return 0, nil, 0, errors.New("bson: invalid json object")
+		}
+		var err error
+		if dst, i, err = e.appendElement(dst, key, j); err != nil {
+			return 0, nil, 0, err
+		}
+		if i = skipSpace(data, i); i >= len(data) {
+			return 0, nil, 0, errors.New("bson: invalid json object")
+		}
+		if data[i] == '}' {
+			return bsonDocument, closeBSONDocument(dst, start), i + 1, nil
+		}
+		if data[i] != ',' {
+			return 0, nil, 0, errors.New("bson: invalid json object")
+		}
+		i = skipSpace(data, i+1)
+	}
+}
+
+// key reads the object key and colon starting at data[i], and returns the key and the
+// index of the member value.
+func (e *bsonEncoder) key(i int) (string, int, bool) {
+	data := e.data
+	if i >= len(data) || data[i] != '"' {
+		return "", 0, false
+	}
+	j := scanString(data, i)
+	if j < 0 {
+		return "", 0, false
+	}
+	key, err := unquote(data[i:j])
+	if err != nil {
+		return "", 0, false
+	}
+	j = skipSpace(data, j)
+	if j >= len(data) || data[j] != ':' {
+		return "", 0, false
+	}
+	j = skipSpace(data, j+1)
+	if j >= len(data) {
+		return "", 0, false
+	}
+	return key, j, true
+}
+
+// appendElement appends a document element named key holding the JSON value starting
+// at data[i]. The type byte is written once the value has been converted.
+func (e *bsonEncoder) appendElement(dst []byte, key string, i int) ([]byte, int, error) {
+	if strings.IndexByte(key, 0) >= 0 {
+		return nil, 0, errors.New("bson: keys must not contain null bytes")
+	}
+	if i >= len(e.data) {
+		return nil, 0, errors.New("bson: truncated json value")
+	}
+	at := len(dst)
+	dst = append(dst, 0)
+	dst = append(dst, key...)
+	dst = append(dst, 0)
+	typ, dst, i, err := e.appendValue(dst, i)
+	if err != nil {
+		return nil, 0, err
+	}
+	dst[at] = typ
+	return dst, i, nil
+}
+
+// appendExtended appends single-member extended JSON objects such as {"$oid": ...},
+// starting at data[i], as their BSON types. It reports false if the object is not one,
+// having read no further than its first member, so that it is converted as a document.
+func (e *bsonEncoder) appendExtended(dst []byte, i int) (byte, []byte, int, bool, error) {
+	data := e.data
+	key, j, ok := e.key(skipSpace(data, i+1))
+	if !ok || !strings.HasPrefix(key, "$") {
+		return 0, nil, 0, false, nil
+	}
+
+	var typ byte
+	var val []byte
+	var err error
+	if key == "$date" {
+		var ms int64
+		ms, j, ok, err = e.extendedDate(j)
+		typ, val = bsonDateTime, binary.LittleEndian.AppendUint64(nil, uint64(ms))
+	} else {
+		var s string
+		if s, j, ok = e.extendedString(j); ok {
+			typ, val, ok, err = extendedScalar(key, s)
+		}
+	}
+	if !ok {
+		return 0, nil, 0, false, nil
+	}
+	if j = skipSpace(data, j); j >= len(data) || data[j] != '}' {
+		return 0, nil, 0, false, nil
+	}
+	if err != nil {
+		return 0, nil, 0, true, err
+	}
+	return typ, append(dst, val...), j + 1, true, nil
+}
+
+// extendedString reads the JSON string starting at data[i], reporting false if there
+// is none.
+func (e *bsonEncoder) extendedString(i int) (string, int, bool) {
+	if e.data[i] != '"' {
+		return "", 0, false
+	}
+	j := scanString(e.data, i)
+	if j < 0 {
+		return "", 0, false
+	}
+	s, err := unquote(e.data[i:j])
+	return s, j, err == nil
+}
+
+// extendedDate reads the value of a $date member starting at data[i] as milliseconds
+// since the epoch. It is an RFC 3339 string, an integer or {"$numberLong": "..."}.
+func (e *bsonEncoder) extendedDate(i int) (int64, int, bool, error) {
+	data := e.data
+	switch c := data[i]; {
+	case c == '"':
+		s, j, ok := e.extendedString(i)
+		if !ok {
+			return 0, 0, false, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return 0, j, true, fmt.Errorf("bson: invalid $date: %w", err)
+		}
+		return t.UnixMilli(), j, true, nil
+	case c == '-' || c >= '0' && c <= '9':
+		j := scanNumber(data, i)
+		if j < 0 {
+			return 0, 0, false, nil
+		}
+		ms, err := strconv.ParseInt(string(data[i:j]), 10, 64)
+		if err != nil {
+			return 0, j, true, errors.New("bson: invalid $date")
+		}
+		return ms, j, true, nil
+	case c == '{':
+		key, j, ok := e.key(skipSpace(data, i+1))
+		if !ok || key != "$numberLong" {
+			return 0, 0, false, nil
+		}
+		s, j, ok := e.extendedString(j)
+		if j = skipSpace(data, j); !ok || j >= len(data) || data[j] != '}' {
+			return 0, 0, false, nil
+		}
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, j + 1, true, errors.New("bson: invalid $date")
+		}
+		return ms, j + 1, true, nil
+	}
+	return 0, 0, false, nil
+}
+
+// extendedScalar converts the string value s of the extended JSON member key to its
+// BSON type. It reports false for keys it does not convert.
+func extendedScalar(key, s string) (byte, []byte, bool, error) {
+	switch key {
+	case "$oid":
+		oid, err := hex.DecodeString(s)
+		if err != nil || len(oid) != 12 {
+			return 0, nil, true, errors.New("bson: invalid $oid")
+		}
+		return bsonObjectID, oid, true, nil
+	case "$numberInt":
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, nil, true, fmt.Errorf("bson: invalid $numberInt %q", s)
+		}
+		return bsonInt32, binary.LittleEndian.AppendUint32(nil, uint32(int32(n))), true, nil
+	case "$numberLong":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, nil, true, fmt.Errorf("bson: invalid $numberLong %q", s)
+		}
+		return bsonInt64, binary.LittleEndian.AppendUint64(nil, uint64(n)), true, nil
+	case "$numberDouble":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, nil, true, fmt.Errorf("bson: invalid $numberDouble %q", s)
+		}
+		return bsonDouble, binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)), true, nil
+	}
+	return 0, nil, false, nil
+}
+
+// appendBSONNumber appends a JSON number as the narrowest BSON number type. Integers
+// are stored as int32 or int64; those out of the int64 range are rejected rather than
+// rounded to a double.
+func appendBSONNumber(dst []byte, s string) (byte, []byte, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("bson: integer %s overflows int64", s)
+		}
+		if n >= math.MinInt32 && n <= math.MaxInt32 {
+			return bsonInt32, binary.LittleEndian.AppendUint32(dst, uint32(int32(n))), nil
+		}
+		return bsonInt64, binary.LittleEndian.AppendUint64(dst, uint64(n)), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("bson: invalid number %s", s)
+	}
+	return bsonDouble, binary.LittleEndian.AppendUint64(dst, math.Float64bits(f)), nil
+}
+
+// closeBSONDocument terminates the document starting at dst[start] and writes its
+// total length.
+func closeBSONDocument(dst []byte, start int) []byte {
+	dst = append(dst, 0)
+	binary.LittleEndian.PutUint32(dst[start:], uint32(len(dst)-start))
+	return dst
+}
+
+// appendBSONString appends s as a length-prefixed BSON string.
+func appendBSONString(dst []byte, s string) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(s)+1))
+	dst = append(dst, s...)
+	return append(dst, 0)
+}
+
+// bsonToJSON appends the relaxed extended JSON form of a BSON value to dst.
+func bsonToJSON(dst []byte, typ byte, data []byte) ([]byte, error) {
+	errShort := fmt.Errorf("bson: truncated value of type 0x%02x", typ)
+
+	switch typ {
+	case bsonNull:
+		return append(dst, "null"...), nil
+	case bsonBool:
+		if len(data) < 1 {
+			return nil, errShort
+		}
+		return strconv.AppendBool(dst, data[0] != 0), nil
+	case bsonInt32:
+		if len(data) < 4 {
+			return nil, errShort
+		}
+		return strconv.AppendInt(dst, int64(int32(binary.LittleEndian.Uint32(data))), 10), nil
+	case bsonInt64:
+		if len(data) < 8 {
+			return nil, errShort
+		}
+		return strconv.AppendInt(dst, int64(binary.LittleEndian.Uint64(data)), 10), nil
+	case bsonDouble:
+		if len(data) < 8 {
+			return nil, errShort
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(data))
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.New("bson: cannot represent non-finite double as json")
+		}
+		return strconv.AppendFloat(dst, f, 'g', -1, 64), nil
+	case bsonString:
+		s, _, err := readBSONString(data)
+		if err != nil {
+			return nil, err
+		}
+		return appendJSONString(dst, s)
+	case bsonObjectID:
+		if len(data) < 12 {
+			return nil, errShort
+		}
+		dst = append(dst, `{"$oid":"`...)
+		dst = hex.AppendEncode(dst, data[:12])
+		return append(dst, `"}`...), nil
+	case bsonDateTime:
+		if len(data) < 8 {
+			return nil, errShort
+		}
+		t := time.UnixMilli(int64(binary.LittleEndian.Uint64(data))).UTC()
+		dst = append(dst, `{"$date":"`...)
+		dst = t.AppendFormat(dst, "2006-01-02T15:04:05.999Z07:00")
+		return append(dst, `"}`...), nil
+	case bsonBinary:
+		if len(data) < 5 {
+			return nil, errShort
+		}
+		n := int(binary.LittleEndian.Uint32(data))
+		if n < 0 || len(data) < 5+n {
+			return nil, errShort
+		}
+		dst = append(dst, `{"$binary":{"base64":"`...)
+		dst = base64.StdEncoding.AppendEncode(dst, data[5:5+n])
+		dst = append(dst, `","subType":"`...)
+		dst = hex.AppendEncode(dst, data[4:5])
+		return append(dst, `"}}`...), nil
+	case bsonDocument, bsonArray:
+		return bsonDocumentToJSON(dst, data, typ == bsonArray)
+	default:
+		return nil, fmt.Errorf("bson: unsupported type 0x%02x", typ)
+	}
+}
+
+// bsonDocumentToJSON appends a BSON document or array as a JSON object or array.
+func bsonDocumentToJSON(dst []byte, data []byte, array bool) ([]byte, error) {
+	if len(data) < 5 {
+		return nil, errors.New("bson: truncated document")
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	if n < 5 || n > len(data) || data[n-1] != 0 {
+		return nil, errors.New("bson: invalid document length")
+	}
+
+	open, close := byte('{'), byte('}')
+	if array {
+		open, close = '[', ']'
+	}
+	dst = append(dst, open)
+
+	for i, first := 4, true; i < n-1; first = false {
+		typ := data[i]
+		end := bytes.IndexByte(data[i+1:n], 0)
+		if end < 0 {
+			return nil, errors.New("bson: unterminated key")
+		}
+		key := string(data[i+1 : i+1+end])
+		i += 2 + end
+		if i > n-1 {
+			return nil, errors.New("bson: truncated document")
+		}
+
+		size, err := bsonValueSize(typ, data[i:n-1])
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			dst = append(dst, ',')
+		}
+		if !array {
+			if dst, err = appendJSONString(dst, key); err != nil {
+				return nil, err
+			}
+			dst = append(dst, ':')
+		}
+		if dst, err = bsonToJSON(dst, typ, data[i:i+size]); err != nil {
+			return nil, err
+		}
+		i += size
+	}
+	return append(dst, close), nil
+}
+
+// bsonValueSize returns the encoded size of the BSON value of type typ at data[0].
+func bsonValueSize(typ byte, data []byte) (int, error) {
+	var size int
+	switch typ {
+	case bsonNull:
+		size = 0
+	case bsonBool:
+		size = 1
+	case bsonInt32:
+		size = 4
+	case bsonInt64, bsonDouble, bsonDateTime:
+		size = 8
+	case bsonObjectID:
+		size = 12
+	case bsonString, bsonBinary:
+		if len(data) < 4 {
+			return 0, errors.New("bson: truncated value")
+		}
+		size = 4 + int(binary.LittleEndian.Uint32(data))
+		if typ == bsonBinary {
+			size++
+		}
+	case bsonDocument, bsonArray:
+		if len(data) < 4 {
+			return 0, errors.New("bson: truncated value")
+		}
+		size = int(binary.LittleEndian.Uint32(data))
+	default:
+		return 0, fmt.Errorf("bson: unsupported type 0x%02x", typ)
+	}
+	if size < 0 || size > len(data) {
+		return 0, errors.New("bson: truncated value")
+	}
+	return size, nil
+}
+
+// readBSONString reads a length-prefixed BSON string.
+func readBSONString(data []byte) (string, int, error) {
+	if len(data) < 5 {
+		return "", 0, errors.New("bson: truncated string")
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	if n < 1 || len(data) < 4+n || data[3+n] != 0 {
+		return "", 0, errors.New("bson: invalid string length")
+	}
+	return string(data[4 : 3+n]), 4 + n, nil
+}
+
+// appendJSONString appends s as a quoted JSON string.
+func appendJSONString(dst []byte, s string) ([]byte, error) {
+	quoted, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, quoted...), nil
+}
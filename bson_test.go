@@ -0,0 +1,151 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestJitJSON_BSON(t *testing.T) {
+	t.Run("Known document", func(t *testing.T) {
+		// {"hello": "world"} from the BSON specification
+		want := []byte("\x16\x00\x00\x00\x02hello\x00\x06\x00\x00\x00world\x00\x00")
+
+		jit := jitjson.NewFromBytes[map[string]string]([]byte(`{"hello": "world"}`))
+		data, err := jit.MarshalBSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("expected %q, got %q", want, data)
+		}
+
+		var out jitjson.JitJSON[map[string]string]
+		if err := out.UnmarshalBSON(data); err != nil {
+			t.Fatal(err)
+		}
+		m, err := out.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m["hello"] != "world" {
+			t.Error("values do not match")
+		}
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		input := `{"str":"text \"quoted\"","int":42,"long":9007199254740993,"neg":-1,"float":1.5,` +
+			`"bool":true,"null":null,"arr":[1,"two",[false]],"obj":{"nested":{}},` +
+			`"id":{"$oid":"5f1a2b3c4d5e6f7a8b9c0d1e"},"at":{"$date":"2024-01-02T03:04:05.678Z"}}`
+
+		var a jitjson.AnyJitJSON
+		if err := a.UnmarshalJSON([]byte(input)); err != nil {
+			t.Fatal(err)
+		}
+		typ, data, err := a.MarshalBSONValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out jitjson.AnyJitJSON
+		if err := out.UnmarshalBSONValue(typ, data); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := out.MarshalJSON()
+		if string(got) != input {
+			t.Errorf("expected %s, got %s", input, got)
+		}
+	})
+
+	t.Run("Scalars", func(t *testing.T) {
+		jit := jitjson.New(int64(1) << 40)
+		typ, data, err := jit.MarshalBSONValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != 0x12 || len(data) != 8 {
+			t.Errorf("expected int64, got type 0x%02x", typ)
+		}
+
+		if _, err := jitjson.New("text").MarshalBSON(); err == nil {
+			t.Error("expected error for non-document top-level value")
+		}
+	})
+
+	t.Run("Extended JSON", func(t *testing.T) {
+		tests := []struct {
+			input string
+			typ   byte
+		}{
+			{`{"$oid":"5f1a2b3c4d5e6f7a8b9c0d1e"}`, 0x07},
+			{`{"$date":"2024-01-02T03:04:05.678Z"}`, 0x09},
+			{`{"$date":1704164645678}`, 0x09},
+			{`{ "$date" : { "$numberLong" : "1704164645678" } }`, 0x09},
+			{`{"$numberInt":"7"}`, 0x10},
+			{`{"$numberLong":"7"}`, 0x12},
+			{`{"$numberDouble":"7"}`, 0x01},
+			{`{"$oid":"5f1a2b3c4d5e6f7a8b9c0d1e","extra":1}`, 0x03},
+			{`{"$oid":5}`, 0x03},
+			{`{"$set":{"a":1}}`, 0x03},
+		}
+		for _, tt := range tests {
+			typ, _, err := jitjson.NewFromBytes[any]([]byte(tt.input)).MarshalBSONValue()
+			if err != nil {
+				t.Errorf("%s: %v", tt.input, err)
+			} else if typ != tt.typ {
+				t.Errorf("%s: expected type 0x%02x, got 0x%02x", tt.input, tt.typ, typ)
+			}
+		}
+
+		for _, input := range []string{`{"$oid":"xyz"}`, `{"$date":"yesterday"}`, `{"$numberInt":"1e3"}`} {
+			if _, _, err := jitjson.NewFromBytes[any]([]byte(input)).MarshalBSONValue(); err == nil {
+				t.Errorf("%s: expected error", input)
+			}
+		}
+	})
+
+	t.Run("Integer overflow", func(t *testing.T) {
+		for _, input := range []string{`18446744073709551616`, `{"a":[-9223372036854775809]}`} {
+			if _, _, err := jitjson.NewFromBytes[any]([]byte(input)).MarshalBSONValue(); err == nil {
+				t.Errorf("%s: expected error for integer beyond int64", input)
+			}
+		}
+		typ, _, err := jitjson.NewFromBytes[any]([]byte(`9223372036854775807`)).MarshalBSONValue()
+		if err != nil || typ != 0x12 {
+			t.Errorf("expected int64 for the largest int64, got type 0x%02x, %v", typ, err)
+		}
+	})
+
+	t.Run("Nested documents", func(t *testing.T) {
+		input := `{"a":{"b":[{"c":{"$oid":"5f1a2b3c4d5e6f7a8b9c0d1e"}},[],{}]},"d":"e"}`
+		var a jitjson.AnyJitJSON
+		if err := a.UnmarshalJSON([]byte(input)); err != nil {
+			t.Fatal(err)
+		}
+		typ, data, err := a.MarshalBSONValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out jitjson.AnyJitJSON
+		if err := out.UnmarshalBSONValue(typ, data); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := out.MarshalJSON(); string(got) != input {
+			t.Errorf("expected %s, got %s", input, got)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		var jit jitjson.JitJSON[map[string]any]
+		if err := jit.UnmarshalBSON([]byte("\x05\x00\x00")); err == nil {
+			t.Error("expected error for truncated document")
+		}
+		if err := jit.UnmarshalBSON([]byte("\x07\x00\x00\x00\x02a\x00")); err == nil {
+			t.Error("expected error for document ending after a key")
+		}
+		if err := jit.UnmarshalBSONValue(0x7F, nil); err == nil {
+			t.Error("expected error for unsupported type")
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package jitjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrBudgetExceeded is wrapped by the *LimitError returned when a decode would exceed
+// its Budget.
+var ErrBudgetExceeded = errors.New("jitjson: decode budget exceeded")
+
+// Budget limits the decoding done on behalf of a single request, protecting latency
+// objectives from handlers that accidentally decode every element of a large payload.
+// A Budget is attached to values with WithBudget, or to a request context with
+// ContextWithBudget, from which BindRequest and DoJSON attach it to the values they
+// create. Every decode of a value carrying the budget is charged: Unmarshal, the member
+// decodes of UnmarshalPartial and Find, and the functions registered with
+// RegisterVersion. Values nested in a decoded value are not covered, since they are
+// created by UnmarshalJSON without options, and neither are AnyJitJSON, which takes no
+// options, or functions scanning raw bytes such as FieldBytes, which do not decode. A
+// Budget is safe for concurrent use.
+type Budget struct {
+	// MaxBytes limits the total size of the data decoded; zero means no limit.
+	MaxBytes int64
+	// MaxParses limits the number of decodes; zero means no limit.
+	MaxParses int64
+
+	bytes  atomic.Int64
+	parses atomic.Int64
+}
+
+// Used returns the bytes decoded and the number of decodes charged to the budget,
+// including those that exceeded it.
+func (b *Budget) Used() (bytes, parses int64) {
+	return b.bytes.Load(), b.parses.Load()
+}
+
+// charge charges a decode of n bytes to the budget, returning a *LimitError wrapping
+// ErrBudgetExceeded if it exceeds the budget.
+func (b *Budget) charge(n int) error {
+	bytes := b.bytes.Add(int64(n))
+	parses := b.parses.Add(1)
+	switch {
+	case b.MaxBytes > 0 && bytes > b.MaxBytes:
+		return &LimitError{Err: fmt.Errorf("%w: %d bytes decoded of %d", ErrBudgetExceeded, bytes, b.MaxBytes)}
+	case b.MaxParses > 0 && parses > b.MaxParses:
+		return &LimitError{Err: fmt.Errorf("%w: %d decodes of %d", ErrBudgetExceeded, parses, b.MaxParses)}
+	}
+	return nil
+}
+
+// WithBudget charges every decode of JitJSON[T] to b. Decodes that would exceed it
+// fail with a *LimitError wrapping ErrBudgetExceeded, without decoding.
+func WithBudget(b *Budget) Option {
+	return func(o *options) {
+		o.budget = b
+	}
+}
+
+type budgetKey struct{}
+
+// ContextWithBudget returns a copy of ctx carrying b, for BindRequest, DoJSON and
+// GetJSON to attach to the values they create:
+//
+//	func middleware(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			b := &jitjson.Budget{MaxBytes: 1 << 20, MaxParses: 1000}
+//			next.ServeHTTP(w, r.WithContext(jitjson.ContextWithBudget(r.Context(), b)))
+//		})
+//	}
+func ContextWithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// BudgetFromContext returns the Budget carried by ctx, or nil if there is none.
+func BudgetFromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	return b
+}
+
+// withContextBudget returns opts preceded by WithBudget for the Budget carried by ctx,
+// so that options passed explicitly take precedence.
+func withContextBudget(ctx context.Context, opts []Option) []Option {
+	b := BudgetFromContext(ctx)
+	if b == nil {
+		return opts
+	}
+	return append([]Option{WithBudget(b)}, opts...)
+}
+
+// charge charges a decode of data to the configured Budget, if any.
+func (o *options) charge(data []byte) error {
+	if o == nil || o.budget == nil {
+		return nil
+	}
+	return o.budget.charge(len(data))
+}
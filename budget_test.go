@@ -0,0 +1,111 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestBudget(t *testing.T) {
+	b := &jitjson.Budget{MaxParses: 2}
+	data := []byte(`{"Name":"John"}`)
+	for i := 0; i < 2; i++ {
+		if _, err := jitjson.NewFromBytes[Person](data, jitjson.WithBudget(b)).Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	_, err := jitjson.NewFromBytes[Person](data, jitjson.WithBudget(b), count).Unmarshal()
+	if !errors.Is(err, jitjson.ErrBudgetExceeded) || !jitjson.IsLimitError(err) {
+		t.Errorf("expected the budget to be exceeded, got %v", err)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no decoding, got %d", decodes)
+	}
+	if bytes, parses := b.Used(); bytes != 3*int64(len(data)) || parses != 3 {
+		t.Errorf("unexpected usage %d bytes, %d parses", bytes, parses)
+	}
+
+	b = &jitjson.Budget{MaxBytes: 20}
+	jit := jitjson.NewFromBytes[Person](data, jitjson.WithBudget(b))
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Errorf("expected cached values not to be charged, got %v", err)
+	}
+	if _, err := jitjson.NewFromBytes[Person](data, jitjson.WithBudget(b)).Unmarshal(); !errors.Is(err, jitjson.ErrBudgetExceeded) {
+		t.Errorf("expected the budget to be exceeded, got %v", err)
+	}
+}
+
+func TestBudgetFromContext(t *testing.T) {
+	b := &jitjson.Budget{MaxParses: 1}
+	r := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(`{"Name":"John"}`)))
+	r = r.WithContext(jitjson.ContextWithBudget(r.Context(), b))
+	if jitjson.BudgetFromContext(r.Context()) != b {
+		t.Fatal("expected the budget in the context")
+	}
+
+	jit, err := jitjson.BindRequest[Person](r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+	if _, parses := b.Used(); parses != 1 {
+		t.Errorf("expected the decode to be charged, got %d", parses)
+	}
+}
+
+type budgetRecord struct {
+	Version int
+	Name    string
+}
+
+func TestBudgetPaths(t *testing.T) {
+	t.Run("UnmarshalPartial", func(t *testing.T) {
+		b := &jitjson.Budget{MaxParses: 1}
+		jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":"thirty"}`), jitjson.WithBudget(b))
+		_, errs := jit.UnmarshalPartial()
+		if len(errs) != 1 || !errors.Is(errs[0].Err, jitjson.ErrBudgetExceeded) {
+			t.Errorf("expected the member decodes to be charged, got %v", errs)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		b := &jitjson.Budget{MaxParses: 1}
+		items := []*jitjson.JitJSON[Person]{
+			jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), jitjson.WithBudget(b)),
+			jitjson.NewFromBytes[Person]([]byte(`{"Name":"Jane"}`), jitjson.WithBudget(b)),
+		}
+		if _, _, err := jitjson.Find(items, "Name", "Jane"); !errors.Is(err, jitjson.ErrBudgetExceeded) {
+			t.Errorf("expected the member decodes to be charged, got %v", err)
+		}
+	})
+
+	t.Run("RegisterVersion", func(t *testing.T) {
+		var decodes int
+		jitjson.RegisterVersion("2", func(data []byte) (budgetRecord, error) {
+			decodes++
+			return budgetRecord{Version: 2}, nil
+		})
+		b := &jitjson.Budget{MaxParses: 1}
+		data := []byte(`{"version":2,"Name":"John"}`)
+		if _, err := jitjson.NewVersioned[budgetRecord](data, jitjson.WithBudget(b)).Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+		_, err := jitjson.NewVersioned[budgetRecord](data, jitjson.WithBudget(b)).Unmarshal()
+		if !errors.Is(err, jitjson.ErrBudgetExceeded) {
+			t.Errorf("expected the version decoder to be charged, got %v", err)
+		}
+		if decodes != 1 {
+			t.Errorf("expected one decode, got %d", decodes)
+		}
+	})
+}
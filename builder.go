@@ -0,0 +1,113 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ObjectBuilder assembles a JSON object from members added in order, splicing the
+// stored encodings of JitJSON[T] and AnyJitJSON values directly into the output. It
+// suits response composition layers stitching together fragments from several
+// backends:
+//
+//	var b jitjson.ObjectBuilder
+//	b.Add("user", userJit).Add("orders", ordersJit).AddRaw("flags", flagsJSON)
+//	data, err := b.Bytes() // {"user":...,"orders":...,"flags":...}
+//
+// Members are written in the order they are added; duplicate keys are not detected.
+// The first error encountered is reported by Bytes, MarshalJSON and WriteTo, and later
+// members are ignored. The zero value is an empty object ready to use.
+type ObjectBuilder struct {
+	buf []byte
+	err error
+}
+
+// Add appends the member key with the MarshalJSON result of v, or null if v is nil.
+func (b *ObjectBuilder) Add(key string, v json.Marshaler) *ObjectBuilder {
+	if b.err != nil {
+		return b
+	}
+	data, err := marshalItem(v)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	return b.append(key, data)
+}
+
+// AddRaw appends the member key with the JSON encoding raw, which is checked with
+// ScanValid and copied.
+func (b *ObjectBuilder) AddRaw(key string, raw []byte) *ObjectBuilder {
+	if b.err != nil {
+		return b
+	}
+	if !ScanValid(raw) {
+		b.err = errors.New("jitjson: invalid json for member " + key)
+		return b
+	}
+	return b.append(key, raw)
+}
+
+// AddValue appends the member key with v encoded by Marshal, so lazy values are
+// spliced in as-is and any other value is encoded by encoding/json.
+func (b *ObjectBuilder) AddValue(key string, v any) *ObjectBuilder {
+	if b.err != nil {
+		return b
+	}
+	data, err := Marshal(v)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	return b.append(key, data)
+}
+
+// append writes the member key with the encoding data.
+func (b *ObjectBuilder) append(key string, data []byte) *ObjectBuilder {
+	if len(b.buf) == 0 {
+		b.buf = append(b.buf, '{')
+	} else {
+		b.buf = append(b.buf, ',')
+	}
+	b.buf, b.err = appendString(b.buf, key)
+	b.buf = append(b.buf, ':')
+	b.buf = append(b.buf, data...)
+	return b
+}
+
+// Bytes returns the encoding of the object. The builder can be added to afterwards.
+func (b *ObjectBuilder) Bytes() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.buf) == 0 {
+		return []byte("{}"), nil
+	}
+	data := make([]byte, len(b.buf)+1)
+	copy(data, b.buf)
+	data[len(b.buf)] = '}'
+	return data, nil
+}
+
+// MarshalJSON implements json.Marshaler, so builders can be nested with Add.
+func (b *ObjectBuilder) MarshalJSON() ([]byte, error) {
+	return b.Bytes()
+}
+
+// WriteTo writes the encoding of the object to w without copying it.
+func (b *ObjectBuilder) WriteTo(w io.Writer) (int64, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(b.buf) == 0 {
+		n, err := io.WriteString(w, "{}")
+		return int64(n), err
+	}
+	n, err := w.Write(b.buf)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write([]byte{'}'})
+	return int64(n + m), err
+}
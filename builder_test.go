@@ -0,0 +1,59 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestObjectBuilder(t *testing.T) {
+	user := jitjson.NewFromBytes[Person]([]byte(`{"Name": "John"}`))
+	tags, err := jitjson.NewAny([]byte(`["a", "b"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing *jitjson.JitJSON[Person]
+
+	var inner jitjson.ObjectBuilder
+	inner.AddValue("count", 2)
+
+	var b jitjson.ObjectBuilder
+	b.Add("user", user).
+		Add("tags", tags).
+		Add("missing", missing).
+		AddRaw("flags", []byte(`{"beta":true}`)).
+		AddValue("total", 12.5).
+		Add("inner", &inner).
+		AddValue("quote\"d", "x")
+
+	data, err := b.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"user":{"Name": "John"},"tags":["a", "b"],"missing":null,"flags":{"beta":true},"total":12.5,"inner":{"count":2},"quote\"d":"x"}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	if !json.Valid(data) {
+		t.Error("expected valid json")
+	}
+
+	var buf bytes.Buffer
+	if n, err := b.WriteTo(&buf); err != nil || buf.String() != want || n != int64(len(want)) {
+		t.Errorf("expected %s, got %s (%d), %v", want, buf.String(), n, err)
+	}
+
+	var empty jitjson.ObjectBuilder
+	if data, _ := empty.Bytes(); string(data) != "{}" {
+		t.Errorf("expected {}, got %s", data)
+	}
+
+	var bad jitjson.ObjectBuilder
+	bad.AddRaw("broken", []byte(`{"a":`)).AddValue("ok", 1)
+	if _, err := bad.Bytes(); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected error naming the member, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// WithCanonical makes Marshal always return the canonical encoding of the value: object
+// members sorted by key, no insignificant whitespace, strings escaped as by
+// encoding/json, and numbers written in their shortest form, so 1.0, 1e0 and 1 are all
+// written as 1. Without it, Marshal returns stored bytes as they were received, so the
+// same logical value can produce different bytes depending on whether it was decoded
+// from input or freshly encoded. The option applies to the encoding/json parser and is
+// ignored with others, such as those of jityaml and jitcbor, whose encodings are
+// returned as they are.
+func WithCanonical() Option {
+	return func(o *options) {
+		o.canonical = true
+	}
+}
+
+// Canonicalize returns the canonical encoding of the JSON value in data, as produced by
+// Marshal with WithCanonical. The data is rewritten without being decoded.
+func Canonicalize(data []byte) ([]byte, error) {
+	return canonicalize(data, true)
+}
+
+// canonicalize returns the canonical encoding of the JSON value in data, with the
+// numbers in their shortest form if numbers is set and as written otherwise.
+func canonicalize(data []byte, numbers bool) ([]byte, error) {
+	i := skipSpace(data, 0)
+	end := valueEnd(data, i)
+	if end < 0 || skipSpace(data, end) != len(data) {
+		return nil, errors.New("jitjson: invalid json")
+	}
+	return appendCanonical(make([]byte, 0, len(data)), data[i:end], numbers)
+}
+
+// member is an object member collected for sorting.
+type member struct {
+	key string
+	val []byte
+}
+
+// appendCanonical appends the canonical encoding of the JSON value v to dst, with the
+// numbers in their shortest form if numbers is set.
+func appendCanonical(dst, v []byte, numbers bool) ([]byte, error) {
+	var err error
+	switch v[0] {
+	case '{':
+		var members []member
+		ok := splitObject(v, func(key, val []byte) bool {
+			k, kerr := unquote(key)
+			if kerr != nil {
+				return false
+			}
+			members = append(members, member{k, val})
+			return true
+		})
+		if !ok {
+			return nil, errors.New("jitjson: invalid json object")
+		}
+		sort.SliceStable(members, func(i, j int) bool { return members[i].key < members[j].key })
+
+		dst = append(dst, '{')
+		for i, m := range members {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = appendString(dst, m.key); err != nil {
+				return nil, err
+			}
+			dst = append(dst, ':')
+			if dst, err = appendCanonical(dst, m.val, numbers); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, '}'), nil
+	case '[':
+		elems, ok := splitArray(v)
+		if !ok {
+			return nil, errors.New("jitjson: invalid json array")
+		}
+		dst = append(dst, '[')
+		for i, elem := range elems {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = appendCanonical(dst, elem, numbers); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, ']'), nil
+	case '"':
+		s, err := unquote(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendString(dst, s)
+	default:
+		if scanScalar(v, 0) != len(v) {
+			return nil, errors.New("jitjson: invalid json")
+		}
+		if d, ok := decimalOf(v); ok && numbers {
+			return appendDecimal(dst, d), nil
+		}
+		return append(dst, v...), nil
+	}
+}
+
+// appendDecimal appends the shortest encoding of the number d to dst, written as
+// ECMAScript writes numbers: in plain notation when the decimal point is within 21
+// digits of the first and 6 of the last, and in exponential notation otherwise. Unlike
+// ECMAScript, every digit is kept, so no precision is lost.
+func appendDecimal(dst []byte, d decimal) []byte {
+	if d.digits == "" {
+		return append(dst, '0')
+	}
+	if d.neg {
+		dst = append(dst, '-')
+	}
+	k := len(d.digits)
+	n := k + d.exp // the position of the decimal point relative to the first digit
+	switch {
+	case k <= n && n <= 21:
+		dst = append(dst, d.digits...)
+		for i := k; i < n; i++ {
+			dst = append(dst, '0')
+		}
+	case 0 < n && n <= 21:
+		dst = append(dst, d.digits[:n]...)
+		dst = append(dst, '.')
+		dst = append(dst, d.digits[n:]...)
+	case -6 < n && n <= 0:
+		dst = append(dst, "0."...)
+		for i := n; i < 0; i++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, d.digits...)
+	default:
+		dst = append(dst, d.digits[0])
+		if k > 1 {
+			dst = append(dst, '.')
+			dst = append(dst, d.digits[1:]...)
+		}
+		dst = append(dst, 'e')
+		if n > 0 {
+			dst = append(dst, '+')
+		}
+		dst = strconv.AppendInt(dst, int64(n-1), 10)
+	}
+	return dst
+}
+
+// appendString appends s to dst as a JSON string escaped by encoding/json.
+func appendString(dst []byte, s string) ([]byte, error) {
+	enc, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, enc...), nil
+}
@@ -0,0 +1,65 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWithCanonical(t *testing.T) {
+	type Doc struct {
+		Name string         `json:"name"`
+		Tags []string       `json:"tags"`
+		Meta map[string]int `json:"meta"`
+	}
+	want := `{"meta":{"a":1,"b":2},"name":"A\u003cB","tags":["x","y"]}`
+
+	input := []byte(" {\"tags\": [\"x\", \"\\u0079\"],\n \"name\": \"A<B\", \"meta\": {\"b\": 2, \"a\": 1}} ")
+	fromBytes := jitjson.NewFromBytes[Doc](input, jitjson.WithCanonical())
+	fromValue := jitjson.New(Doc{Name: "A<B", Tags: []string{"x", "y"}, Meta: map[string]int{"b": 2, "a": 1}}, jitjson.WithCanonical())
+
+	for name, jit := range map[string]*jitjson.JitJSON[Doc]{"NewFromBytes": fromBytes, "New": fromValue} {
+		for i := 0; i < 2; i++ {
+			data, err := jit.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != want {
+				t.Errorf("%s: expected %s, got %s", name, want, data)
+			}
+		}
+	}
+
+	plain, _ := jitjson.NewFromBytes[Doc](input).Marshal()
+	if string(plain) != string(input) {
+		t.Error("expected input bytes to be returned as-is without WithCanonical")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{` null `, `null`},
+		{`[ 1.50 , true,{ } ,[]]`, `[1.5,true,{},[]]`},
+		{`[1.0, 1e0, 10E-1, 100, 1e2, 0.5e2, -0.0, 0e5]`, `[1,1,1,100,100,50,0,0]`},
+		{`[1e21, 1e20, 1.5e-7, 0.000001, 12345678901234567890123]`, `[1e+21,100000000000000000000,1.5e-7,0.000001,1.2345678901234567890123e+22]`},
+		{`[-2.50e-1, 1234567890.12345678901234567890]`, `[-0.25,1234567890.1234567890123456789]`},
+		{`{"b":{"d":1,"c":[{"z":0,"y":0}]},"a":"\u00e9"}`, `{"a":"é","b":{"c":[{"y":0,"z":0}],"d":1}}`},
+	}
+	for _, tc := range tests {
+		got, err := jitjson.Canonicalize([]byte(tc.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("Canonicalize(%s): expected %s, got %s", tc.in, tc.want, got)
+		}
+	}
+
+	for _, in := range []string{``, `{"a":1`, `[1,]`, `{} {}`} {
+		if _, err := jitjson.Canonicalize([]byte(in)); err == nil {
+			t.Errorf("Canonicalize(%q): expected error", in)
+		}
+	}
+}
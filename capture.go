@@ -0,0 +1,151 @@
+package jitjson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// CaptureTransport is an http.RoundTripper that tees the bodies of responses to requests
+// carrying a ResponseCapture in their context, so observability layers can inspect
+// selected fields of a response after the call without it having been decoded:
+//
+//	client := &http.Client{Transport: &jitjson.CaptureTransport{MaxBytes: 1 << 20}}
+//	ctx, capture := jitjson.WithResponseCapture(ctx)
+//	resp, err := client.Do(req.WithContext(ctx))
+//	// ... the caller reads and closes resp.Body as usual
+//	if doc, err := capture.Any(); err == nil {
+//		obj, _ := doc.AsObject()
+//		code, _ := obj["error_code"].AsString()
+//	}
+//
+// Bodies are captured as they are read by the caller, so the response is not delayed.
+// Requests without a ResponseCapture are passed through untouched.
+type CaptureTransport struct {
+	// Base is the RoundTripper performing requests. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// MaxBytes limits the size of captured bodies; larger bodies are read by the caller
+	// as usual but not captured. Zero means the limit of BindRequest set by Configure,
+	// or DefaultMaxRequestBytes.
+	MaxBytes int64
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CaptureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	c, ok := req.Context().Value(captureKey{}).(*ResponseCapture)
+	if err != nil || !ok || resp.Body == nil {
+		return resp, err
+	}
+	limit := t.MaxBytes
+	if limit <= 0 {
+		limit = (*options)(nil).requestLimit()
+	}
+	c.reset(resp.StatusCode, limit)
+	resp.Body = &captureBody{ReadCloser: resp.Body, c: c}
+	return resp, nil
+}
+
+// captureKey is the context key of a ResponseCapture.
+type captureKey struct{}
+
+// ResponseCapture holds the body of a response captured by CaptureTransport. If a
+// request is redirected, it holds the last response. It is safe for concurrent use.
+type ResponseCapture struct {
+	mu        sync.Mutex
+	status    int
+	data      []byte
+	limit     int64
+	truncated bool
+	complete  bool
+}
+
+// WithResponseCapture returns a copy of ctx carrying a new ResponseCapture, which
+// receives the body of the response to a request made with the context through a
+// CaptureTransport.
+func WithResponseCapture(ctx context.Context) (context.Context, *ResponseCapture) {
+	c := &ResponseCapture{}
+	return context.WithValue(ctx, captureKey{}, c), c
+}
+
+// reset prepares c to capture a new response.
+func (c *ResponseCapture) reset(status int, limit int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status, c.data, c.limit = status, []byte{}, limit
+	c.truncated, c.complete = false, false
+}
+
+// StatusCode returns the status code of the captured response, or zero if there is none.
+func (c *ResponseCapture) StatusCode() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// Bytes returns the captured body. It returns an error if no response was captured, the
+// body has not been read to the end, or it exceeded the size limit, wrapping ErrTooLarge.
+func (c *ResponseCapture) Bytes() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case c.data == nil && !c.truncated:
+		return nil, errors.New("jitjson: no response captured")
+	case c.truncated:
+		return nil, &LimitError{Err: fmt.Errorf("jitjson: captured response: %w: limit %d", ErrTooLarge, c.limit)}
+	case !c.complete:
+		return nil, errors.New("jitjson: captured response was not read to the end")
+	}
+	return c.data, nil
+}
+
+// Any returns the captured body as an AnyJitJSON, parsed only as far as it is accessed.
+func (c *ResponseCapture) Any() (*AnyJitJSON, error) {
+	data, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return NewAny(data)
+}
+
+// Captured returns the captured body of c as a JitJSON[T] configured by opts, without
+// decoding it.
+func Captured[T any](c *ResponseCapture, opts ...Option) (*JitJSON[T], error) {
+	data, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return NewFromBytes[T](data, opts...), nil
+}
+
+// captureBody copies a response body into its ResponseCapture as it is read.
+type captureBody struct {
+	io.ReadCloser
+	c *ResponseCapture
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	c := b.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.truncated {
+		if int64(len(c.data)+n) > c.limit {
+			c.data = nil
+			c.truncated = true
+		} else {
+			c.data = append(c.data, p[:n]...)
+		}
+	}
+	if err == io.EOF {
+		c.complete = true
+	}
+	return n, err
+}
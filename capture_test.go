@@ -0,0 +1,82 @@
+package jitjson_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestCaptureTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			w.Write([]byte(`{"data":"` + strings.Repeat("x", 100) + `"}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"Name":"John","Age":30}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &jitjson.CaptureTransport{Base: srv.Client().Transport, MaxBytes: 64}}
+	get := func(ctx context.Context, path string, read bool) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if read {
+			body, _ := io.ReadAll(resp.Body)
+			if len(body) == 0 {
+				t.Error("expected the caller to read the body")
+			}
+		}
+	}
+
+	ctx, capture := jitjson.WithResponseCapture(context.Background())
+	if _, err := capture.Bytes(); err == nil {
+		t.Error("expected an error before a response is captured")
+	}
+	get(ctx, "/", true)
+	if capture.StatusCode() != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", capture.StatusCode())
+	}
+	doc, err := capture.Any()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, _ := doc.AsObject()
+	if name, _ := obj["Name"].AsString(); name != "John" {
+		t.Errorf("expected John, got %q", name)
+	}
+	jit, err := jitjson.Captured[Person](capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := jit.Unmarshal(); err != nil || p.Age != 30 {
+		t.Errorf("expected age 30, got %+v, %v", p, err)
+	}
+
+	ctx, capture = jitjson.WithResponseCapture(context.Background())
+	get(ctx, "/", false)
+	if _, err := capture.Bytes(); err == nil {
+		t.Error("expected an error for an unread body")
+	}
+
+	ctx, capture = jitjson.WithResponseCapture(context.Background())
+	get(ctx, "/large", true)
+	if _, err := capture.Bytes(); !errors.Is(err, jitjson.ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+
+	get(context.Background(), "/", true)
+}
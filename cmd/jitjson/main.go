@@ -0,0 +1,369 @@
+// Command jitjson inspects large JSON files from the shell without loading them into
+// memory. Top-level values are located with a structural index and only the slices a
+// command needs are read from the file.
+//
+// Usage:
+//
+//	jitjson [flags] get <path> <file>   print the value at path, e.g. users.3.name
+//	jitjson [flags] keys [path] <file>  print the keys of an object, one per line
+//	jitjson [flags] len [path] <file>   print the length of an array or object
+//	jitjson validate <file>             check that the file is a single valid JSON value
+//	jitjson [flags] stats <file>        print size, type and entry statistics
+//
+// Path segments are separated by dots; array elements are selected by index. The
+// -index flag persists the structural index next to the file, so later commands skip
+// scanning it again. The saved index records the size and modification time of the
+// file, and is rebuilt when either has changed.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jitjson", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	indexFile := fs.String("index", "", "load the structural index from `file`, building and saving it if missing")
+	raw := fs.Bool("r", false, "print strings without JSON quoting")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jitjson [flags] get|keys|len|validate|stats [path] <file>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return 2
+	}
+
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	var path string
+	switch {
+	case (cmd == "get" || cmd == "keys" || cmd == "len") && len(args) == 2:
+		path, args = args[0], args[1:]
+	case len(args) != 1:
+		fs.Usage()
+		return 2
+	}
+
+	var err error
+	switch cmd {
+	case "validate":
+		err = validate(args[0], stdout)
+	case "get", "keys", "len", "stats":
+		var doc *document
+		if doc, err = open(args[0], *indexFile); err != nil {
+			break
+		}
+		defer doc.Close()
+		switch cmd {
+		case "get":
+			err = doc.get(path, *raw, stdout)
+		case "keys":
+			err = doc.keys(path, stdout)
+		case "len":
+			err = doc.len(path, stdout)
+		case "stats":
+			err = doc.stats(stdout)
+		}
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "jitjson:", err)
+		return 1
+	}
+	return 0
+}
+
+// document is a JSON file opened as a jitjson.RemoteDocument.
+type document struct {
+	*jitjson.RemoteDocument
+	f    *os.File
+	size int64
+}
+
+// indexCache is the content of an -index file: the structural index of a file, and the
+// size and modification time the file had when it was indexed.
+type indexCache struct {
+	Size    int64                  `json:"size"`
+	ModTime int64                  `json:"mtime"`
+	Index   *jitjson.DocumentIndex `json:"index"`
+}
+
+// open opens name and loads or builds its structural index. If indexFile is set and
+// holds an index of the file as it is now, it is loaded; otherwise the built index is
+// saved to it.
+func open(name, indexFile string) (*document, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	var index *jitjson.DocumentIndex
+	if indexFile != "" {
+		if data, err := os.ReadFile(indexFile); err == nil {
+			var cache indexCache
+			if err := json.Unmarshal(data, &cache); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("reading index %s: %w", indexFile, err)
+			}
+			if cache.Size == fi.Size() && cache.ModTime == fi.ModTime().UnixNano() {
+				index = cache.Index
+			}
+		}
+	}
+
+	doc := &document{
+		RemoteDocument: jitjson.NewRemoteDocument(f, fi.Size(), index),
+		f:              f,
+		size:           fi.Size(),
+	}
+	if indexFile != "" && index == nil {
+		index, err := doc.Index()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		data, err := json.Marshal(indexCache{
+			Size:    fi.Size(),
+			ModTime: fi.ModTime().UnixNano(),
+			Index:   index,
+		})
+		if err == nil {
+			err = os.WriteFile(indexFile, data, 0o644)
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("writing index %s: %w", indexFile, err)
+		}
+	}
+	return doc, nil
+}
+
+// Close closes the underlying file.
+func (d *document) Close() error {
+	return d.f.Close()
+}
+
+// lookup returns the value at the dot separated path. The first segment is fetched
+// through the index; the rest are resolved lazily within that value.
+func (d *document) lookup(path string) (*jitjson.AnyJitJSON, error) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil, nil
+	}
+	segments := strings.Split(path, ".")
+
+	index, err := d.Index()
+	if err != nil {
+		return nil, err
+	}
+	var v *jitjson.AnyJitJSON
+	if index.Type == jitjson.TypeArray {
+		i, err := strconv.Atoi(segments[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid array index %q", segments[0])
+		}
+		v, err = d.Element(i)
+		if err != nil {
+			return nil, err
+		}
+	} else if v, err = d.Member(segments[0]); err != nil {
+		return nil, err
+	}
+
+	for _, seg := range segments[1:] {
+		switch v.Type() {
+		case jitjson.TypeArray:
+			arr, _ := v.AsArray()
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(arr) {
+				return nil, fmt.Errorf("invalid array index %q", seg)
+			}
+			v = arr[i]
+		case jitjson.TypeObject:
+			obj, _ := v.AsObject()
+			child, ok := obj[seg]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", seg)
+			}
+			v = child
+		default:
+			return nil, fmt.Errorf("cannot select %q from %s", seg, typeName(v.Type()))
+		}
+	}
+	return v, nil
+}
+
+func (d *document) get(path string, raw bool, w io.Writer) error {
+	v, err := d.lookup(path)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return errors.New("get requires a path")
+	}
+	if s, ok := v.AsString(); ok && raw {
+		_, err = fmt.Fprintln(w, s)
+		return err
+	}
+	data, _ := v.MarshalJSON()
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+func (d *document) keys(path string, w io.Writer) error {
+	v, err := d.lookup(path)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	if v == nil {
+		index, err := d.Index()
+		if err != nil {
+			return err
+		}
+		if index.Type != jitjson.TypeObject {
+			return errors.New("document is not an object")
+		}
+		for k := range index.Members {
+			keys = append(keys, k)
+		}
+	} else {
+		obj, ok := v.AsObject()
+		if !ok {
+			return fmt.Errorf("%s is not an object", path)
+		}
+		for k := range obj {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, err := fmt.Fprintln(w, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *document) len(path string, w io.Writer) error {
+	v, err := d.lookup(path)
+	if err != nil {
+		return err
+	}
+
+	var n int
+	if v == nil {
+		if n, err = d.Len(); err != nil {
+			return err
+		}
+	} else if arr, ok := v.AsArray(); ok {
+		n = len(arr)
+	} else if obj, ok := v.AsObject(); ok {
+		n = len(obj)
+	} else {
+		return fmt.Errorf("%s is not an array or object", path)
+	}
+	_, err = fmt.Fprintln(w, n)
+	return err
+}
+
+func (d *document) stats(w io.Writer) error {
+	index, err := d.Index()
+	if err != nil {
+		return err
+	}
+
+	var largest jitjson.Span
+	var largestName string
+	if index.Type == jitjson.TypeObject {
+		for k, span := range index.Members {
+			if span.Length > largest.Length || (span.Length == largest.Length && k < largestName) {
+				largest, largestName = span, strconv.Quote(k)
+			}
+		}
+	} else {
+		for i, span := range index.Elements {
+			if span.Length > largest.Length {
+				largest, largestName = span, strconv.Itoa(i)
+			}
+		}
+	}
+
+	n, _ := d.Len()
+	fmt.Fprintf(w, "size:    %d bytes\n", d.size)
+	fmt.Fprintf(w, "type:    %s\n", typeName(index.Type))
+	fmt.Fprintf(w, "entries: %d\n", n)
+	if largestName != "" {
+		fmt.Fprintf(w, "largest: %s (%d bytes at offset %d)\n", largestName, largest.Length, largest.Offset)
+	}
+	return nil
+}
+
+// typeName returns the JSON name of t, such as "object".
+func typeName(t jitjson.ValueType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "Type"))
+}
+
+// validate checks that name holds exactly one valid JSON value. The file is streamed
+// token by token, so memory use does not grow with its size.
+func validate(name string, w io.Writer) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	depth, values := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid json at offset %d: %w", dec.InputOffset(), err)
+		}
+		switch tok {
+		case json.Delim('['), json.Delim('{'):
+			depth++
+		case json.Delim(']'), json.Delim('}'):
+			depth--
+		}
+		if depth == 0 {
+			values++
+		}
+	}
+	if values != 1 {
+		return fmt.Errorf("expected a single JSON value, found %d", values)
+	}
+	_, err = fmt.Fprintln(w, "valid")
+	return err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDocument = `{
+	"users": [{"name": "John", "tags": ["a", "b"]}, {"name": "Jane"}],
+	"count": 2,
+	"meta": {"source": "export"}
+}`
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestRun(t *testing.T) {
+	name := writeFile(t, testDocument)
+	indexFile := filepath.Join(t.TempDir(), "doc.idx")
+
+	tests := []struct {
+		args []string
+		want string
+		code int
+	}{
+		{[]string{"get", "users.0.name", name}, "\"John\"\n", 0},
+		{[]string{"-r", "get", "users.0.name", name}, "John\n", 0},
+		{[]string{"-index", indexFile, "get", "meta", name}, "{\"source\": \"export\"}\n", 0},
+		{[]string{"-index", indexFile, "get", "count", name}, "2\n", 0},
+		{[]string{"keys", name}, "count\nmeta\nusers\n", 0},
+		{[]string{"keys", "users.0", name}, "name\ntags\n", 0},
+		{[]string{"len", name}, "3\n", 0},
+		{[]string{"len", "users.0.tags", name}, "2\n", 0},
+		{[]string{"validate", name}, "valid\n", 0},
+		{[]string{"get", "users.5", name}, "", 1},
+		{[]string{"get", "missing", name}, "", 1},
+		{[]string{"len", "count", name}, "", 1},
+		{[]string{"unknown", name}, "", 2},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tc.args, &stdout, &stderr)
+			if code != tc.code {
+				t.Fatalf("expected exit code %d, got %d: %s", tc.code, code, stderr.String())
+			}
+			if stdout.String() != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, stdout.String())
+			}
+		})
+	}
+
+	if _, err := os.Stat(indexFile); err != nil {
+		t.Errorf("expected index to be saved: %v", err)
+	}
+}
+
+func TestStaleIndex(t *testing.T) {
+	name := writeFile(t, `{"count": 2, "total": 5}`)
+	indexFile := filepath.Join(t.TempDir(), "doc.idx")
+
+	get := func(path, want string) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"-index", indexFile, "get", path, name}, &stdout, &stderr); code != 0 {
+			t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+		}
+		if stdout.String() != want {
+			t.Errorf("expected %q, got %q", want, stdout.String())
+		}
+	}
+	get("total", "5\n")
+
+	// a file of a different size
+	if err := os.WriteFile(name, []byte(`{"count": 10, "total": 5}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	get("total", "5\n")
+
+	// a file of the same size modified later
+	if err := os.WriteFile(name, []byte(`{"total": 7, "count": 10}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(name, later, later); err != nil {
+		t.Fatal(err)
+	}
+	get("total", "7\n")
+}
+
+func TestStats(t *testing.T) {
+	name := writeFile(t, testDocument)
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"stats", name}, &stdout, &stderr); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	for _, want := range []string{"type:    object", "entries: 3", `largest: "users"`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	for _, content := range []string{`{"a": [1, 2}`, `{} {}`, `[1, 2`} {
+		name := writeFile(t, content)
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"validate", name}, &stdout, &stderr); code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", content, code)
+		}
+	}
+}
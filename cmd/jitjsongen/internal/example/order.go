@@ -0,0 +1,31 @@
+package example
+
+import "time"
+
+//go:generate go run github.com/mcwalrus/go-jitjson/cmd/jitjsongen -mode fields -type Order
+
+// Order is a JSON document whose bulky fields are only needed by some consumers.
+type Order struct {
+	Metadata
+	ID       string    `json:"id"`
+	Customer *User     `json:"customer" jit:"lazy"`
+	Items    []Item    `json:"items" jit:"lazy"`
+	PlacedAt time.Time `json:"placed_at"`
+	Tags     []string  `json:"tags,omitempty" jit:"lazy"`
+	Coupon   Coupon    `json:"coupon,omitempty" jit:"lazy"`
+	notes    string
+}
+
+// Coupon is a discount code applied to an Order.
+type Coupon string
+
+// Item is a line of an Order.
+type Item struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// Metadata is embedded in Order.
+type Metadata struct {
+	Source string `json:"source"`
+}
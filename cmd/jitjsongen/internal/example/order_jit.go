@@ -0,0 +1,73 @@
+// Code generated by "jitjsongen -mode fields -type Order"; DO NOT EDIT.
+
+package example
+
+import (
+	"time"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// OrderJit is Order with the fields tagged `jit:"lazy"` held as JitJSON values,
+// so they are only decoded or encoded when needed.
+type OrderJit struct {
+	Metadata
+	ID       string                     `json:"id"`
+	Customer *jitjson.JitJSON[*User]    `json:"customer" jit:"lazy"`
+	Items    *jitjson.JitJSON[[]Item]   `json:"items" jit:"lazy"`
+	PlacedAt time.Time                  `json:"placed_at"`
+	Tags     *jitjson.JitJSON[[]string] `json:"tags,omitempty" jit:"lazy"`
+	Coupon   *jitjson.JitJSON[Coupon]   `json:"coupon,omitempty" jit:"lazy"`
+	notes    string
+}
+
+// NewOrderJit converts v to OrderJit. The lazy fields are encoded on demand, and
+// left nil if tagged omitempty and empty, so they are omitted as in Order.
+func NewOrderJit(v Order) *OrderJit {
+	j := &OrderJit{
+		Metadata: v.Metadata,
+		ID:       v.ID,
+		Customer: jitjson.New(v.Customer),
+		Items:    jitjson.New(v.Items),
+		PlacedAt: v.PlacedAt,
+		notes:    v.notes,
+	}
+	if len(v.Tags) != 0 {
+		j.Tags = jitjson.New(v.Tags)
+	}
+	if v.Coupon != "" {
+		j.Coupon = jitjson.New(v.Coupon)
+	}
+	return j
+}
+
+// Eager converts j back to Order, decoding its lazy fields.
+func (j *OrderJit) Eager() (Order, error) {
+	var v Order
+	var err error
+	v.Metadata = j.Metadata
+	v.ID = j.ID
+	if j.Customer != nil {
+		if v.Customer, err = j.Customer.Unmarshal(); err != nil {
+			return v, err
+		}
+	}
+	if j.Items != nil {
+		if v.Items, err = j.Items.Unmarshal(); err != nil {
+			return v, err
+		}
+	}
+	v.PlacedAt = j.PlacedAt
+	if j.Tags != nil {
+		if v.Tags, err = j.Tags.Unmarshal(); err != nil {
+			return v, err
+		}
+	}
+	if j.Coupon != nil {
+		if v.Coupon, err = j.Coupon.Unmarshal(); err != nil {
+			return v, err
+		}
+	}
+	v.notes = j.notes
+	return v, nil
+}
@@ -0,0 +1,56 @@
+package example
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderJit(t *testing.T) {
+	order := Order{
+		Metadata: Metadata{Source: "web"},
+		ID:       "o-1",
+		Customer: &User{Name: "John"},
+		Items:    []Item{{SKU: "a", Quantity: 2}},
+		notes:    "gift",
+	}
+
+	data, err := json.Marshal(NewOrderJit(order))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(order)
+	if string(data) != string(want) {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	tagged := order
+	tagged.Tags = []string{"gift"}
+	tagged.Coupon = "SAVE10"
+	taggedData, err := json.Marshal(NewOrderJit(tagged))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := json.Marshal(tagged); string(taggedData) != string(want) {
+		t.Errorf("expected %s, got %s", want, taggedData)
+	}
+
+	var j OrderJit
+	if err := json.Unmarshal(data, &j); err != nil {
+		t.Fatal(err)
+	}
+	if j.ID != "o-1" || j.Source != "web" {
+		t.Errorf("unexpected eager fields %+v", j)
+	}
+	items, err := j.Items.Unmarshal()
+	if err != nil || len(items) != 1 || items[0].Quantity != 2 {
+		t.Errorf("unexpected items %+v %v", items, err)
+	}
+
+	back, err := NewOrderJit(order).Eager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Customer.Name != "John" || back.notes != "gift" || len(back.Items) != 1 {
+		t.Errorf("unexpected round trip %+v", back)
+	}
+}
@@ -0,0 +1,24 @@
+// Package example demonstrates the wrapper types generated by jitjsongen.
+package example
+
+import "time"
+
+//go:generate go run github.com/mcwalrus/go-jitjson/cmd/jitjsongen -type User,Address
+
+// User is a JSON document with fields of varying decoding cost.
+type User struct {
+	Name      string         `json:"name"`
+	Email     string         `json:"email,omitempty"`
+	Address   *Address       `json:"address"`
+	Tags      []string       `json:"tags"`
+	Settings  map[string]any `json:"settings"`
+	CreatedAt time.Time      `json:"created_at"`
+	Password  string         `json:"-"`
+	Score     float64
+	internal  int
+}
+
+// Address is nested within User.
+type Address struct {
+	Street, City string
+}
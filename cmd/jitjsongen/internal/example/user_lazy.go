@@ -0,0 +1,134 @@
+// Code generated by "jitjsongen -type User,Address"; DO NOT EDIT.
+
+package example
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// UserLazy decodes the fields of User lazily. Each field is decoded from the
+// JSON encoding on first access, so fields that are never accessed are never parsed.
+type UserLazy struct {
+	data           []byte
+	fieldName      jitjson.Field[string]
+	fieldEmail     jitjson.Field[string]
+	fieldAddress   jitjson.Field[*Address]
+	fieldTags      jitjson.Field[[]string]
+	fieldSettings  jitjson.Field[map[string]any]
+	fieldCreatedAt jitjson.Field[time.Time]
+	fieldScore     jitjson.Field[float64]
+}
+
+// NewUserLazy returns a wrapper for data, the JSON encoding of the value.
+func NewUserLazy(data []byte) *UserLazy {
+	return &UserLazy{data: data}
+}
+
+// Name decodes the Name field on first access.
+func (w *UserLazy) Name() (string, error) {
+	return w.fieldName.Get(w.data, "name")
+}
+
+// Email decodes the Email field on first access.
+func (w *UserLazy) Email() (string, error) {
+	return w.fieldEmail.Get(w.data, "email")
+}
+
+// Address decodes the Address field on first access.
+func (w *UserLazy) Address() (*Address, error) {
+	return w.fieldAddress.Get(w.data, "address")
+}
+
+// Tags decodes the Tags field on first access.
+func (w *UserLazy) Tags() ([]string, error) {
+	return w.fieldTags.Get(w.data, "tags")
+}
+
+// Settings decodes the Settings field on first access.
+func (w *UserLazy) Settings() (map[string]any, error) {
+	return w.fieldSettings.Get(w.data, "settings")
+}
+
+// CreatedAt decodes the CreatedAt field on first access.
+func (w *UserLazy) CreatedAt() (time.Time, error) {
+	return w.fieldCreatedAt.Get(w.data, "created_at")
+}
+
+// Score decodes the Score field on first access.
+func (w *UserLazy) Score() (float64, error) {
+	return w.fieldScore.Get(w.data, "Score")
+}
+
+// Eager decodes the complete User.
+func (w *UserLazy) Eager() (User, error) {
+	var v User
+	if w.data == nil {
+		return v, nil
+	}
+	err := json.Unmarshal(w.data, &v)
+	return v, err
+}
+
+// MarshalJSON returns the encoding the UserLazy was created from.
+func (w *UserLazy) MarshalJSON() ([]byte, error) {
+	if w.data == nil {
+		return []byte("null"), nil
+	}
+	return w.data, nil
+}
+
+// UnmarshalJSON stores a copy of data, discarding any decoded fields.
+func (w *UserLazy) UnmarshalJSON(data []byte) error {
+	*w = UserLazy{data: append([]byte(nil), data...)}
+	return nil
+}
+
+// AddressLazy decodes the fields of Address lazily. Each field is decoded from the
+// JSON encoding on first access, so fields that are never accessed are never parsed.
+type AddressLazy struct {
+	data        []byte
+	fieldStreet jitjson.Field[string]
+	fieldCity   jitjson.Field[string]
+}
+
+// NewAddressLazy returns a wrapper for data, the JSON encoding of the value.
+func NewAddressLazy(data []byte) *AddressLazy {
+	return &AddressLazy{data: data}
+}
+
+// Street decodes the Street field on first access.
+func (w *AddressLazy) Street() (string, error) {
+	return w.fieldStreet.Get(w.data, "Street")
+}
+
+// City decodes the City field on first access.
+func (w *AddressLazy) City() (string, error) {
+	return w.fieldCity.Get(w.data, "City")
+}
+
+// Eager decodes the complete Address.
+func (w *AddressLazy) Eager() (Address, error) {
+	var v Address
+	if w.data == nil {
+		return v, nil
+	}
+	err := json.Unmarshal(w.data, &v)
+	return v, err
+}
+
+// MarshalJSON returns the encoding the AddressLazy was created from.
+func (w *AddressLazy) MarshalJSON() ([]byte, error) {
+	if w.data == nil {
+		return []byte("null"), nil
+	}
+	return w.data, nil
+}
+
+// UnmarshalJSON stores a copy of data, discarding any decoded fields.
+func (w *AddressLazy) UnmarshalJSON(data []byte) error {
+	*w = AddressLazy{data: append([]byte(nil), data...)}
+	return nil
+}
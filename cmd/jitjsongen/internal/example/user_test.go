@@ -0,0 +1,55 @@
+package example
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestUserLazy(t *testing.T) {
+	data := []byte(`{
+		"name": "John",
+		"address": {"Street": "1 Main St", "City": "New York"},
+		"tags": ["a", "b"],
+		"created_at": "2024-01-02T03:04:05Z",
+		"Score": "invalid"
+	}`)
+	u := NewUserLazy(data)
+
+	name, err := u.Name()
+	if err != nil || name != "John" {
+		t.Errorf("expected John, got %q %v", name, err)
+	}
+	email, err := u.Email()
+	if err != nil || email != "" {
+		t.Errorf("expected empty email, got %q %v", email, err)
+	}
+	addr, err := u.Address()
+	if err != nil || addr.City != "New York" {
+		t.Errorf("unexpected address %+v %v", addr, err)
+	}
+	at, err := u.CreatedAt()
+	if err != nil || !at.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected created_at %v %v", at, err)
+	}
+
+	// decoding errors are isolated to the accessed field
+	if _, err := u.Score(); err == nil {
+		t.Error("expected error for Score")
+	}
+	if _, err := u.Eager(); err == nil {
+		t.Error("expected error for Eager")
+	}
+
+	out, err := json.Marshal(struct{ User *UserLazy }{u})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct{ User *UserLazy }
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if tags, _ := decoded.User.Tags(); len(tags) != 2 {
+		t.Errorf("expected tags to survive a round trip, got %v", tags)
+	}
+}
@@ -0,0 +1,586 @@
+// Command jitjsongen generates wrapper types with lazily decoded per-field accessors
+// for JSON structs, giving field-granular laziness without hand-written plumbing.
+// Given a struct User, it generates a UserLazy type whose accessors decode only the
+// requested member of the JSON object, caching the result:
+//
+//	//go:generate go run github.com/mcwalrus/go-jitjson/cmd/jitjsongen -type User
+//
+//	u := NewUserLazy(data)
+//	email, err := u.Email() // only the "email" member is decoded
+//
+// Accessors are generated for exported fields that are not embedded and not ignored
+// with a `json:"-"` tag. The wrapper also has an Eager method decoding the complete
+// struct, and MarshalJSON and UnmarshalJSON methods passing the encoding through.
+//
+// With -mode fields, jitjsongen instead generates a copy of each struct in which the
+// fields tagged `jit:"lazy"` have type *jitjson.JitJSON[T], along with conversions to
+// and from the eager form. Given
+//
+//	type Order struct {
+//		ID    string `json:"id"`
+//		Items []Item `json:"items" jit:"lazy"`
+//	}
+//
+// it generates an OrderJit type with an Items field of type *jitjson.JitJSON[[]Item],
+// a NewOrderJit function converting an Order, and an Eager method converting back.
+// NewOrderJit leaves lazy fields tagged omitempty nil when they are empty, so they are
+// omitted from the encoding as they would be from Order's.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run executes the command line args and returns the process exit code.
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jitjsongen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typeNames := fs.String("type", "", "comma-separated list of struct `types`; must be set")
+	output := fs.String("output", "", "output file name; default <type>_<suffix>.go")
+	mode := fs.String("mode", modeAccessors, "generate per-field `mode`: accessors or fields")
+	suffix := fs.String("suffix", "", "`suffix` appended to type names to name the generated types; default Lazy, or Jit with -mode fields")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jitjsongen -type T [flags] [directory]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *typeNames == "" || fs.NArg() > 1 || (*mode != modeAccessors && *mode != modeFields) {
+		fs.Usage()
+		return 2
+	}
+
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	if *suffix == "" {
+		*suffix = "Lazy"
+		if *mode == modeFields {
+			*suffix = "Jit"
+		}
+	}
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_" + strings.ToLower(*suffix) + ".go"
+	}
+	if !filepath.IsAbs(*output) {
+		*output = filepath.Join(dir, *output)
+	}
+
+	src, err := generate(dir, types, *mode, *suffix, "jitjsongen "+strings.Join(args, " "))
+	if err == nil {
+		err = os.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "jitjsongen:", err)
+		return 1
+	}
+	return 0
+}
+
+// Generation modes selected by the -mode flag.
+const (
+	modeAccessors = "accessors"
+	modeFields    = "fields"
+)
+
+// wrapperType describes a generated wrapper type.
+type wrapperType struct {
+	Type    string
+	Wrapper string
+	Fields  []field
+}
+
+// field describes an accessor or field of a wrapper type.
+type field struct {
+	Name     string // Go field and accessor name
+	Key      string // JSON object key
+	Type     string // Go type expression
+	Cache    string // name of the jitjson.Field holding the decoded value
+	Tag      string // struct tag literal, in fields mode
+	Embedded bool   // whether the field is embedded, in fields mode
+	Lazy     bool   // whether the field is tagged `jit:"lazy"`, in fields mode
+	NonEmpty string // condition under which a lazy omitempty field is set, in fields mode
+}
+
+// reserved holds the method names of wrapper types, which fields cannot shadow.
+var reserved = map[string]bool{"Eager": true, "MarshalJSON": true, "UnmarshalJSON": true}
+
+// generate returns the formatted source of the types generated in mode for the named
+// structs of the package in dir. command is recorded in the generated file header.
+func generate(dir string, typeNames []string, mode, suffix, command string) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	imports := map[string]string{}
+	var types []wrapperType
+	for _, name := range typeNames {
+		file, st := findStruct(files, name)
+		if st == nil {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+		w := wrapperType{Type: name, Wrapper: name + suffix}
+		for _, f := range st.Fields.List {
+			var fields []field
+			if mode == modeFields {
+				fields, err = structFields(fset, files, f)
+			} else {
+				fields, err = accessorFields(fset, f)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			if err := addImports(imports, file, f.Type); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			w.Fields = append(w.Fields, fields...)
+		}
+		if mode == modeFields && !hasLazy(w.Fields) {
+			return nil, fmt.Errorf("%s: no fields tagged `jit:\"lazy\"`", name)
+		}
+		types = append(types, w)
+	}
+
+	var std, other []string
+	for path, name := range imports {
+		spec := strconv.Quote(path)
+		if name != "" {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	var buf bytes.Buffer
+	err = fileTemplate.Execute(&buf, map[string]any{
+		"Command":    command,
+		"Package":    files[0].Name.Name,
+		"Mode":       mode,
+		"StdImports": std,
+		"Imports":    other,
+		"Types":      types,
+	})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// accessorFields returns the accessors generated for the struct field f.
+func accessorFields(fset *token.FileSet, f *ast.Field) ([]field, error) {
+	if len(f.Names) == 0 {
+		return nil, nil // embedded fields are decoded by Eager only
+	}
+	key, skip, err := jsonKey(f)
+	if err != nil || skip {
+		return nil, err
+	}
+	var fields []field
+	for _, ident := range f.Names {
+		if !ident.IsExported() {
+			continue
+		}
+		if reserved[ident.Name] {
+			return nil, fmt.Errorf("field %s conflicts with a wrapper method", ident.Name)
+		}
+		k := key
+		if k == "" {
+			k = ident.Name
+		}
+		fields = append(fields, field{
+			Name:  ident.Name,
+			Key:   k,
+			Type:  exprString(fset, f.Type),
+			Cache: "field" + ident.Name,
+		})
+	}
+	return fields, nil
+}
+
+// structFields returns the fields generated for the struct field f of a package parsed
+// as files in fields mode.
+func structFields(fset *token.FileSet, files []*ast.File, f *ast.Field) ([]field, error) {
+	var tag string
+	var lazy, omitEmpty bool
+	if f.Tag != nil {
+		tag = f.Tag.Value
+		value, err := strconv.Unquote(tag)
+		if err != nil {
+			return nil, err
+		}
+		lazy = reflect.StructTag(value).Get("jit") == "lazy"
+		_, opts, _ := strings.Cut(reflect.StructTag(value).Get("json"), ",")
+		omitEmpty = slices.Contains(strings.Split(opts, ","), "omitempty")
+	}
+
+	typ := exprString(fset, f.Type)
+	if len(f.Names) == 0 {
+		if lazy {
+			return nil, fmt.Errorf("embedded field %s cannot be lazy", typ)
+		}
+		return []field{{Name: embeddedName(f.Type), Type: typ, Tag: tag, Embedded: true}}, nil
+	}
+	var fields []field
+	for _, ident := range f.Names {
+		fd := field{Name: ident.Name, Type: typ, Tag: tag, Lazy: lazy}
+		if lazy && omitEmpty {
+			// A lazy field is a pointer, which omitempty only omits if it is nil.
+			fd.NonEmpty = nonEmpty(files, f.Type, "v."+ident.Name)
+		}
+		fields = append(fields, fd)
+	}
+	return fields, nil
+}
+
+// nonEmpty returns the condition under which encoding/json encodes the value x of type
+// typ in a field tagged omitempty, resolving the types declared by files. It returns ""
+// for types whose values are never omitted, such as structs, and for types declared in
+// other packages, which are assumed to be structs.
+func nonEmpty(files []*ast.File, typ ast.Expr, x string) string {
+	switch t := typ.(type) {
+	case *ast.ParenExpr:
+		return nonEmpty(files, t.X, x)
+	case *ast.ArrayType, *ast.MapType:
+		return "len(" + x + ") != 0"
+	case *ast.StarExpr, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return x + " != nil"
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return x + ` != ""`
+		case "bool":
+			return x
+		case "any", "error":
+			return x + " != nil"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32",
+			"uint64", "uintptr", "byte", "rune", "float32", "float64":
+			return x + " != 0"
+		}
+		if _, spec := findType(files, t.Name); spec != nil && spec.TypeParams == nil {
+			return nonEmpty(files, spec.Type, x)
+		}
+	}
+	return ""
+}
+
+// OmitsEmpty reports whether any lazy field of w is left nil when empty.
+func (w wrapperType) OmitsEmpty() bool {
+	for _, f := range w.Fields {
+		if f.NonEmpty != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLazy reports whether any of fields is tagged `jit:"lazy"`.
+func hasLazy(fields []field) bool {
+	for _, f := range fields {
+		if f.Lazy {
+			return true
+		}
+	}
+	return false
+}
+
+// exprString returns the source of the expression expr.
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+// embeddedName returns the field name of the embedded type expr.
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// parseDir parses the non-test, non-generated Go files of the package in dir.
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if ast.IsGenerated(f) {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// findStruct returns the declaration of the struct type name and its file.
+func findStruct(files []*ast.File, name string) (*ast.File, *ast.StructType) {
+	file, spec := findType(files, name)
+	if spec == nil || spec.TypeParams != nil {
+		return nil, nil
+	}
+	if st, ok := spec.Type.(*ast.StructType); ok {
+		return file, st
+	}
+	return nil, nil
+}
+
+// findType returns the declaration of the type name and its file.
+func findType(files []*ast.File, name string) (*ast.File, *ast.TypeSpec) {
+	for _, file := range files {
+		obj := file.Scope.Lookup(name)
+		if obj == nil || obj.Kind != ast.Typ {
+			continue
+		}
+		if spec, ok := obj.Decl.(*ast.TypeSpec); ok {
+			return file, spec
+		}
+	}
+	return nil, nil
+}
+
+// jsonKey returns the key set by the json tag of f, and whether f is ignored.
+func jsonKey(f *ast.Field) (key string, skip bool, err error) {
+	if f.Tag == nil {
+		return "", false, nil
+	}
+	tag, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		return "", false, nil
+	}
+	if value == "-" {
+		return "", true, nil
+	}
+	key, opts, _ := strings.Cut(value, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "string" {
+			return "", false, errors.New("the json ,string option is not supported")
+		}
+	}
+	return key, false, nil
+}
+
+// versionSuffix matches major version path elements such as "v2".
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// addImports records the imports of file referenced by the type expression expr,
+// mapping import paths to the names they are referenced by.
+func addImports(imports map[string]string, file *ast.File, expr ast.Expr) error {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, spec := range file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			name := importName(path)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			if name == pkg.Name {
+				if name == importName(path) {
+					name = ""
+				}
+				imports[path] = name
+				return false
+			}
+		}
+		err = fmt.Errorf("no import found for package %s", pkg.Name)
+		return false
+	})
+	return err
+}
+
+// importName returns the conventional package name of an import path.
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if versionSuffix.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by "{{.Command}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if ne .Mode "fields"}}
+	"encoding/json"
+{{- end}}
+{{- range .StdImports}}
+	{{.}}
+{{- end}}
+{{range .Imports}}
+	{{.}}
+{{- end}}
+	"github.com/mcwalrus/go-jitjson"
+)
+{{range .Types}}
+{{- if eq $.Mode "fields"}}{{template "fields" .}}{{else}}{{template "accessors" .}}{{end}}
+{{- end}}`))
+
+func init() {
+	template.Must(fileTemplate.New("accessors").Parse(`
+// {{.Wrapper}} decodes the fields of {{.Type}} lazily. Each field is decoded from the
+// JSON encoding on first access, so fields that are never accessed are never parsed.
+type {{.Wrapper}} struct {
+	data []byte
+{{- range .Fields}}
+	{{.Cache}} jitjson.Field[{{.Type}}]
+{{- end}}
+}
+
+// New{{.Wrapper}} returns a wrapper for data, the JSON encoding of the value.
+func New{{.Wrapper}}(data []byte) *{{.Wrapper}} {
+	return &{{.Wrapper}}{data: data}
+}
+{{$w := .}}{{range .Fields}}
+// {{.Name}} decodes the {{.Name}} field on first access.
+func (w *{{$w.Wrapper}}) {{.Name}}() ({{.Type}}, error) {
+	return w.{{.Cache}}.Get(w.data, {{printf "%q" .Key}})
+}
+{{end}}
+// Eager decodes the complete {{.Type}}.
+func (w *{{.Wrapper}}) Eager() ({{.Type}}, error) {
+	var v {{.Type}}
+	if w.data == nil {
+		return v, nil
+	}
+	err := json.Unmarshal(w.data, &v)
+	return v, err
+}
+
+// MarshalJSON returns the encoding the {{.Wrapper}} was created from.
+func (w *{{.Wrapper}}) MarshalJSON() ([]byte, error) {
+	if w.data == nil {
+		return []byte("null"), nil
+	}
+	return w.data, nil
+}
+
+// UnmarshalJSON stores a copy of data, discarding any decoded fields.
+func (w *{{.Wrapper}}) UnmarshalJSON(data []byte) error {
+	*w = {{.Wrapper}}{data: append([]byte(nil), data...)}
+	return nil
+}
+`))
+	template.Must(fileTemplate.New("fields").Parse(`
+// {{.Wrapper}} is {{.Type}} with the fields tagged ` + "`jit:\"lazy\"`" + ` held as JitJSON values,
+// so they are only decoded or encoded when needed.
+type {{.Wrapper}} struct {
+{{- range .Fields}}
+	{{if not .Embedded}}{{.Name}} {{end}}{{if .Lazy}}*jitjson.JitJSON[{{.Type}}]{{else}}{{.Type}}{{end}} {{.Tag}}
+{{- end}}
+}
+
+// New{{.Wrapper}} converts v to {{.Wrapper}}. The lazy fields are encoded on demand
+{{- if .OmitsEmpty}}, and
+// left nil if tagged omitempty and empty, so they are omitted as in {{.Type}}
+{{- end}}.
+func New{{.Wrapper}}(v {{.Type}}) *{{.Wrapper}} {
+	j := &{{.Wrapper}}{
+{{- range .Fields}}
+{{- if not .NonEmpty}}
+		{{.Name}}: {{if .Lazy}}jitjson.New(v.{{.Name}}){{else}}v.{{.Name}}{{end}},
+{{- end}}
+{{- end}}
+	}
+{{- range .Fields}}
+{{- if .NonEmpty}}
+	if {{.NonEmpty}} {
+		j.{{.Name}} = jitjson.New(v.{{.Name}})
+	}
+{{- end}}
+{{- end}}
+	return j
+}
+
+// Eager converts j back to {{.Type}}, decoding its lazy fields.
+func (j *{{.Wrapper}}) Eager() ({{.Type}}, error) {
+	var v {{.Type}}
+	var err error
+{{- range .Fields}}
+{{- if .Lazy}}
+	if j.{{.Name}} != nil {
+		if v.{{.Name}}, err = j.{{.Name}}.Unmarshal(); err != nil {
+			return v, err
+		}
+	}
+{{- else}}
+	v.{{.Name}} = j.{{.Name}}
+{{- end}}
+{{- end}}
+	return v, nil
+}
+`))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateExample(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	tests := []struct {
+		file, mode, suffix, command string
+		types                       []string
+	}{
+		{"user_lazy.go", modeAccessors, "Lazy", "jitjsongen -type User,Address", []string{"User", "Address"}},
+		{"order_jit.go", modeFields, "Jit", "jitjsongen -mode fields -type Order", []string{"Order"}},
+	}
+	for _, tc := range tests {
+		src, err := generate(dir, tc.types, tc.mode, tc.suffix, tc.command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := os.ReadFile(filepath.Join(dir, tc.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(src, want) {
+			t.Errorf("internal/example/%s is out of date; run go generate", tc.file)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name, mode, src, want string
+	}{
+		{"Missing type", modeAccessors, `type Other struct{}`, "not found"},
+		{"Not a struct", modeAccessors, `type T int`, "not found"},
+		{"String option", modeAccessors, "type T struct {\n\tN int `json:\"n,string\"`\n}", "not supported"},
+		{"Reserved name", modeAccessors, `type T struct{ Eager bool }`, "conflicts"},
+		{"Unknown import", modeAccessors, `type T struct{ At time.Time }`, "no import"},
+		{"No lazy fields", modeFields, `type T struct{ N int }`, "no fields tagged"},
+		{"Lazy embedded", modeFields, "type E struct{}\ntype T struct {\n\tE `jit:\"lazy\"`\n}", "cannot be lazy"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package p\n\n" + tc.src + "\n"
+			if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := generate(dir, []string{"T"}, tc.mode, "Lazy", "jitjsongen")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nimport t \"time\"\n\ntype Event struct {\n\tAt t.Time `json:\"at\"`\n\tskip int\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	if code := run([]string{"-type", "Event", "-suffix", "View", dir}, &stderr); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+	}
+	out, err := os.ReadFile(filepath.Join(dir, "event_view.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`t "time"`, "type EventView struct", "func (w *EventView) At() (t.Time, error)"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	if code := run([]string{"-type", "Event", "-mode", "eager", dir}, &stderr); code != 2 {
+		t.Errorf("expected usage exit code 2 for unknown mode, got %d", code)
+	}
+	if code := run(nil, &stderr); code != 2 {
+		t.Errorf("expected usage exit code 2, got %d", code)
+	}
+}
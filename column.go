@@ -0,0 +1,37 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExtractColumn returns the member at path of every one of items, decoded as F, without
+// decoding the rest of each item, producing a column for aggregation:
+//
+//	totals, err := jitjson.ExtractColumn[Order, float64](orders, "payment.total")
+//
+// Path is matched as by Find. Items where path is missing, or runs through a value that
+// is not an object, give the zero value of F, as encoding/json leaves missing fields.
+// Items holding only a value are marshaled first. An error is returned, naming the item,
+// if an item cannot be marshaled, is not valid JSON along path, or its member cannot be
+// decoded as F.
+func ExtractColumn[T, F any](items []*JitJSON[T], path string) ([]F, error) {
+	col := make([]F, len(items))
+	for i, item := range items {
+		data, err := item.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		raw, ok, err := pathBytes(data, path)
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, &col[i]); err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %s: %w", i, path, err)
+		}
+	}
+	return col, nil
+}
@@ -0,0 +1,41 @@
+package jitjson_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestExtractColumn(t *testing.T) {
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	items := []*jitjson.JitJSON[Person]{
+		jitjson.NewFromBytes[Person]([]byte(`{"Name": "John", "Age": 30, "Address": {"City": "Oslo"}}`), count),
+		jitjson.NewFromBytes[Person]([]byte(`{"Name": "Jane"}`), count),
+		jitjson.New(Person{Name: "Joe", Age: 41}, count),
+	}
+
+	ages, err := jitjson.ExtractColumn[Person, int](items, "Age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ages, []int{30, 0, 41}) {
+		t.Errorf("expected [30 0 41], got %v", ages)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no items to be decoded, got %d", decodes)
+	}
+
+	cities, err := jitjson.ExtractColumn[Person, string](items[:2], "address.city")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cities, []string{"Oslo", ""}) {
+		t.Errorf("expected [Oslo ], got %q", cities)
+	}
+
+	if _, err := jitjson.ExtractColumn[Person, int](items, "Name"); err == nil {
+		t.Error("expected an error decoding a string as int")
+	}
+}
@@ -0,0 +1,78 @@
+package jitjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ConcatArrays merges the JSON arrays in parts into a single array by splicing their
+// elements' bytes, so no element is decoded or re-encoded. Each part is checked to be
+// an array with balanced brackets and separating commas, but the elements themselves
+// are not validated.
+func ConcatArrays(parts ...[]byte) ([]byte, error) {
+	size := 2
+	for _, part := range parts {
+		size += len(part) + 1
+	}
+	var buf bytes.Buffer
+	buf.Grow(size)
+	if err := WriteConcatArrays(&buf, parts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteConcatArrays writes the concatenation of the JSON arrays in parts to w, as
+// ConcatArrays does, without assembling it in memory first. All parts are checked
+// before anything is written.
+func WriteConcatArrays(w io.Writer, parts ...[]byte) error {
+	bodies := make([][]byte, len(parts))
+	for i, part := range parts {
+		body, err := arrayBody(part)
+		if err != nil {
+			return fmt.Errorf("jitjson: part %d: %w", i, err)
+		}
+		bodies[i] = body
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+	first := true
+	for _, body := range bodies {
+		if len(body) == 0 {
+			continue
+		}
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		bw.Write(body)
+	}
+	bw.WriteByte(']')
+	return bw.Flush()
+}
+
+// arrayBody returns the bytes of the JSON array in data from the start of its first
+// element to the end of its last, or nil if it is empty.
+func arrayBody(data []byte) ([]byte, error) {
+	elems, ok := splitArray(data)
+	if !ok {
+		return nil, errors.New("invalid json array")
+	}
+	if len(elems) == 0 {
+		return nil, nil
+	}
+	start := skipSpace(data, skipSpace(data, 0)+1)
+	end := len(data)
+	for isSpace(data[end-1]) {
+		end--
+	}
+	end-- // the closing bracket
+	for isSpace(data[end-1]) {
+		end--
+	}
+	return data[start:end], nil
+}
@@ -0,0 +1,49 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestConcatArrays(t *testing.T) {
+	data, err := jitjson.ConcatArrays(
+		[]byte(` [1, {"a": [2]}] `),
+		[]byte(`[]`),
+		[]byte("[\n\t\"x\"\n]"),
+		[]byte(`[ [], null ]`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[1, {"a": [2]},"x",[], null]`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	if data, err := jitjson.ConcatArrays(); err != nil || string(data) != "[]" {
+		t.Errorf("expected [], got %s, %v", data, err)
+	}
+
+	for _, part := range []string{`{}`, `[1,]`, `[1 2]`, `[1`, `[1] x`, ``} {
+		if _, err := jitjson.ConcatArrays([]byte(`[0]`), []byte(part)); err == nil {
+			t.Errorf("expected error for %q", part)
+		}
+	}
+}
+
+func TestWriteConcatArrays(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jitjson.WriteConcatArrays(&buf, []byte(`[1]`), []byte(`[2,3]`)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `[1,2,3]` {
+		t.Errorf("expected [1,2,3], got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := jitjson.WriteConcatArrays(&buf, []byte(`[1]`), []byte(`[`)); err == nil || buf.Len() != 0 {
+		t.Errorf("expected error before writing, got %q, %v", buf.String(), err)
+	}
+}
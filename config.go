@@ -0,0 +1,109 @@
+package jitjson
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// Config holds package-wide defaults set by Configure. The zero Config holds the
+// library defaults.
+type Config struct {
+	// DefaultRetention is the Retention of values without WithRetention.
+	DefaultRetention Retention
+	// CopyOnUnmarshal makes UnmarshalJSON and SetBytes copy the data they store, as
+	// WithCopyOnUnmarshal does, so callers may reuse their buffers.
+	CopyOnUnmarshal bool
+	// MaxBytes and MaxDepth limit the data accepted by values without WithMaxBytes and
+	// WithMaxDepth, and by AnyJitJSON, Page, MultiDecoder and SplitObject. Zero means
+	// no limit.
+	MaxBytes int64
+	MaxDepth int
+	// MaxRequestBytes limits the request bodies read by BindRequest without
+	// WithMaxRequestBytes. Zero means DefaultMaxRequestBytes.
+	MaxRequestBytes int64
+	// DefaultParser names the registered parser used by values without WithParser.
+	// Empty names mean DefaultParser; unregistered names make Marshal and Unmarshal
+	// fail, as with WithParser.
+	DefaultParser string
+	// EagerThreshold decodes payloads smaller than it immediately, as WithEagerThreshold
+	// does. Zero disables eager decoding.
+	EagerThreshold int
+}
+
+// defaults holds the options built by Configure, or nil for the library defaults.
+var defaults atomic.Pointer[options]
+
+// Configure sets package-wide defaults for the JitJSON[T] values created afterwards by
+// New, NewFromBytes or UnmarshalJSON into a zero value, so platform teams can set
+// organization-wide safe defaults in one call during initialization:
+//
+//	func init() {
+//		jitjson.Configure(jitjson.Config{
+//			DefaultRetention: jitjson.KeepBytes,
+//			CopyOnUnmarshal:  true,
+//			MaxDepth:         64,
+//		})
+//	}
+//
+// Options passed to a value override the defaults. Each call replaces the previous
+// configuration; Configure(Config{}) restores the library defaults. Existing values keep
+// the defaults they were created with, and values without options, such as the zero
+// values decoded into by json.Unmarshal, take the defaults current when they store data.
+func Configure(c Config) {
+	var o options
+	configured := false
+	if c.DefaultRetention != KeepBoth {
+		WithRetention(c.DefaultRetention)(&o)
+		configured = true
+	}
+	if c.CopyOnUnmarshal {
+		WithCopyOnUnmarshal()(&o)
+		configured = true
+	}
+	if c.DefaultParser != "" && c.DefaultParser != DefaultParser {
+		WithParser(c.DefaultParser)(&o)
+		configured = true
+	}
+	if c.MaxBytes > 0 {
+		WithMaxBytes(c.MaxBytes)(&o)
+		configured = true
+	}
+	if c.MaxDepth > 0 {
+		WithMaxDepth(c.MaxDepth)(&o)
+		configured = true
+	}
+	if c.MaxRequestBytes > 0 {
+		WithMaxRequestBytes(c.MaxRequestBytes)(&o)
+		configured = true
+	}
+	if c.EagerThreshold > 0 {
+		WithEagerThreshold(c.EagerThreshold)(&o)
+		configured = true
+	}
+	if !configured {
+		defaults.Store(nil)
+		return
+	}
+	defaults.Store(&o)
+}
+
+// WithCopyOnUnmarshal makes UnmarshalJSON and SetBytes store a copy of the data they are
+// given, so callers may reuse their buffers afterwards. Without it, the data is retained
+// as passed, which avoids a copy when decoding with encoding/json from a buffer that is
+// not reused.
+func WithCopyOnUnmarshal() Option {
+	return func(o *options) {
+		o.copyData = true
+	}
+}
+
+// clone returns a copy of o that can be modified without affecting values sharing o.
+func (o *options) clone() *options {
+	c := &options{}
+	if o != nil {
+		*c = *o
+		c.onMarshal = slices.Clip(c.onMarshal)
+		c.onUnmarshal = slices.Clip(c.onUnmarshal)
+	}
+	return c
+}
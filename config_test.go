@@ -0,0 +1,90 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestConfigure(t *testing.T) {
+	jitjson.Configure(jitjson.Config{
+		DefaultRetention: jitjson.KeepBytes,
+		CopyOnUnmarshal:  true,
+		MaxBytes:         64,
+		MaxDepth:         2,
+	})
+	defer jitjson.Configure(jitjson.Config{})
+
+	t.Run("CopyOnUnmarshal", func(t *testing.T) {
+		buf := []byte(`{"Name":"John","Age":30}`)
+		var jit jitjson.JitJSON[Person]
+		if err := json.Unmarshal(buf, &jit); err != nil {
+			t.Fatal(err)
+		}
+		copy(buf, `{"Name":"Jane","Age":31}`)
+		if p, err := jit.Unmarshal(); err != nil || p.Name != "John" {
+			t.Errorf("expected John, got %+v, %v", p, err)
+		}
+	})
+
+	t.Run("DefaultRetention", func(t *testing.T) {
+		var decodes int
+		count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+		jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), count)
+		jit.Unmarshal()
+		jit.Unmarshal()
+		if decodes != 2 {
+			t.Errorf("expected every Unmarshal to decode, got %d", decodes)
+		}
+
+		decodes = 0
+		both := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), jitjson.WithRetention(jitjson.KeepBoth), count)
+		both.Unmarshal()
+		both.Unmarshal()
+		if decodes != 1 {
+			t.Errorf("expected options to override the defaults, got %d", decodes)
+		}
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		var jit jitjson.JitJSON[any]
+		if err := jit.SetBytes([]byte(`[[[1]]]`)); !errors.Is(err, jitjson.ErrTooDeep) {
+			t.Errorf("expected ErrTooDeep, got %v", err)
+		}
+		if _, err := jitjson.NewAny([]byte(`"` + strings.Repeat("x", 63) + `"`)); !errors.Is(err, jitjson.ErrTooLarge) {
+			t.Errorf("expected ErrTooLarge, got %v", err)
+		}
+		own := jitjson.NewFromBytes[any](nil, jitjson.WithMaxDepth(4))
+		if err := own.SetBytes([]byte(`[[[1]]]`)); err != nil {
+			t.Errorf("expected options to override the configured limit, got %v", err)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		jitjson.Configure(jitjson.Config{})
+		var jit jitjson.JitJSON[any]
+		if err := jit.SetBytes([]byte(`[[[1]]]`)); err != nil {
+			t.Errorf("expected defaults to be restored, got %v", err)
+		}
+	})
+}
+
+func TestConfigureDefaultParser(t *testing.T) {
+	parser := &countingParser{}
+	registerParser(t, "configured", parser)
+	jitjson.Configure(jitjson.Config{DefaultParser: "configured"})
+	defer jitjson.Configure(jitjson.Config{})
+
+	if _, err := jitjson.New(Person{Name: "John"}).Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jitjson.New(Person{Name: "John"}, jitjson.WithParser(jitjson.DefaultParser)).Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	if parser.marshals != 1 {
+		t.Errorf("expected the default parser to be used once, got %d marshals", parser.marshals)
+	}
+}
@@ -0,0 +1,41 @@
+package jitjson
+
+// ToAny returns an AnyJitJSON holding the same encoding as JitJSON[T], for switching
+// from a typed to a dynamic lazy view of a payload. The encoding is shared rather than
+// copied. If JitJSON[T] holds only a value, it is marshaled first and the encoding
+// cached. Like AnyJitJSON.UnmarshalJSON, scalars are validated, while arrays and objects
+// are only checked when accessed.
+func (jit *JitJSON[T]) ToAny() (*AnyJitJSON, error) {
+	data, err := jit.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	a := &AnyJitJSON{}
+	if data == nil {
+		return a, nil
+	}
+	if err := a.set(data); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ToJit returns a JitJSON[T] holding the same encoding as a, for switching from a
+// dynamic to a typed lazy view of a payload. The encoding is shared rather than copied,
+// and decoded when Unmarshal is called on the result, configured by opts. A tree built
+// by NewAnyFromValue is encoded first; if that fails, the error is returned by the
+// result's Unmarshal.
+func ToJit[T any](a *AnyJitJSON, opts ...Option) *JitJSON[T] {
+	jit := &JitJSON[T]{opts: newOptions(opts)}
+	data, err := a.encoded()
+	if err != nil {
+		jit.val = new(T)
+		jit.verr = err
+		return jit
+	}
+	if data != nil {
+		recordDeferredUnmarshal(len(data))
+		jit.setData(data)
+	}
+	return jit
+}
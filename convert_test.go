@@ -0,0 +1,64 @@
+package jitjson_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestToAny(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30}`)
+	jit := jitjson.NewFromBytes[Person](jsonData)
+
+	a, err := jit.ToAny()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, ok := a.AsObject()
+	if !ok {
+		t.Fatalf("expected object, got %v", a.Type())
+	}
+	if name, _ := obj["Name"].AsString(); name != "John" {
+		t.Errorf("expected John, got %s", name)
+	}
+	data, _ := a.MarshalJSON()
+	if unsafe.SliceData(data) != unsafe.SliceData(jsonData) {
+		t.Error("expected the encoding to be shared")
+	}
+
+	a, err = jitjson.New(Person{Name: "Jane"}).ToAny()
+	if err != nil || a.Type() != jitjson.TypeObject {
+		t.Errorf("expected object, got %v, %v", a, err)
+	}
+	if a, err := (&jitjson.JitJSON[Person]{}).ToAny(); err != nil || !a.IsNull() {
+		t.Errorf("expected null, got %v, %v", a, err)
+	}
+	if _, err := jitjson.NewFromBytes[int]([]byte(`1x`)).ToAny(); err == nil {
+		t.Error("expected error for invalid scalar")
+	}
+}
+
+func TestToJit(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30}`)
+	a, err := jitjson.NewAny(jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jit := jitjson.ToJit[Person](a)
+	p, err := jit.Unmarshal()
+	if err != nil || p.Name != "John" || p.Age != 30 {
+		t.Errorf("expected John, got %+v, %v", p, err)
+	}
+
+	built, err := jitjson.NewAnyFromValue(map[string]interface{}{"Name": "Jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := jitjson.ToJit[Person](built).Unmarshal(); err != nil || p.Name != "Jane" {
+		t.Errorf("expected Jane, got %+v, %v", p, err)
+	}
+	if p, err := jitjson.ToJit[Person](&jitjson.AnyJitJSON{}).Unmarshal(); err != nil || p.Name != "" {
+		t.Errorf("expected zero value, got %+v, %v", p, err)
+	}
+}
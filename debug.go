@@ -0,0 +1,70 @@
+package jitjson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// State describes the lazy state of a JitJSON[T], as returned by DebugState.
+type State struct {
+	// HasBytes reports whether an encoding is stored, and Bytes its length as stored,
+	// which is after any WithBytesTransform.
+	HasBytes bool
+	Bytes    int
+	// HasValue reports whether a decoded or set value is stored.
+	HasValue bool
+	// Err is the stored error of decoding or validating the value, if any.
+	Err error
+	// Dirty reports whether the value was set and has not been encoded since.
+	Dirty bool
+	// Pending reports whether the value is yet to be produced, as by Migrate.
+	Pending bool
+	// Parser is the name of the configured parser.
+	Parser string
+}
+
+// DebugState returns the lazy state of JitJSON[T] without decoding or encoding it, so the
+// state of a field can be understood from logs, core dumps and debugger sessions.
+func (jit *JitJSON[T]) DebugState() State {
+	return State{
+		HasBytes: jit.data != nil,
+		Bytes:    len(jit.data),
+		HasValue: jit.val != nil,
+		Err:      jit.verr,
+		Dirty:    jit.val != nil && jit.data == nil,
+		Pending:  jit.val == nil && jit.data == nil && jit.pending() != nil,
+		Parser:   jit.opts.codecName(),
+	}
+}
+
+// String renders the state compactly, such as
+//
+//	bytes=42 value=cached parser=encoding/json
+//	bytes=none value=dirty parser=encoding/json
+func (s State) String() string {
+	var b strings.Builder
+	b.WriteString("bytes=")
+	if s.HasBytes {
+		b.WriteString(strconv.Itoa(s.Bytes))
+	} else {
+		b.WriteString("none")
+	}
+	b.WriteString(" value=")
+	switch {
+	case s.Pending:
+		b.WriteString("pending")
+	case s.Dirty:
+		b.WriteString("dirty")
+	case s.HasValue:
+		b.WriteString("cached")
+	default:
+		b.WriteString("none")
+	}
+	b.WriteString(" parser=")
+	b.WriteString(s.Parser)
+	if s.Err != nil {
+		b.WriteString(" err=")
+		b.WriteString(strconv.Quote(s.Err.Error()))
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestDebugState(t *testing.T) {
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`))
+	if s := jit.DebugState().String(); s != "bytes=15 value=none parser=encoding/json" {
+		t.Errorf("unexpected state %s", s)
+	}
+
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+	if s := jit.DebugState().String(); s != "bytes=15 value=cached parser=encoding/json" {
+		t.Errorf("unexpected state %s", s)
+	}
+
+	jit.Set(Person{Name: "Jane"})
+	s := jit.DebugState()
+	if !s.Dirty || s.HasBytes || s.String() != "bytes=none value=dirty parser=encoding/json" {
+		t.Errorf("unexpected state %s", s)
+	}
+
+	invalid := jitjson.NewFromBytes[Person]([]byte(`{"Age":0}`), jitjson.WithValidator(&ageValidator{}))
+	if _, err := invalid.Unmarshal(); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if s := invalid.DebugState().String(); s != `bytes=9 value=cached parser=encoding/json err="Age must be greater than 0"` {
+		t.Errorf("unexpected state %s", s)
+	}
+
+	migrated := jitjson.Migrate(jit, func(p Person) (string, error) { return p.Name, nil })
+	if s := migrated.DebugState(); !s.Pending || s.String() != "bytes=none value=pending parser=encoding/json" {
+		t.Errorf("unexpected state %s", s)
+	}
+}
@@ -0,0 +1,108 @@
+package jitjson
+
+import (
+	"bytes"
+	"hash/maphash"
+	"sync"
+)
+
+// DefaultDedupEntries is the number of distinct encodings a Dedup holds when NewDedup is
+// given no positive bound.
+const DefaultDedupEntries = 4096
+
+// Dedup interns byte-identical JSON encodings so that many JitJSON[T] values holding the
+// same payload share one underlying buffer. This is useful for event streams where the
+// same snapshot is received repeatedly. Dedup is safe for concurrent use.
+//
+// Every call to NewFromBytes returns a distinct *JitJSON[T], which the caller owns: Set
+// or Unmarshal on one does not affect the others. When shareValue is enabled, each
+// distinct encoding is also decoded once, when it is first seen, and the values returned
+// for it start out holding a copy of that decoded value. The copies are shallow, so any
+// maps, slices or pointers within T are shared and must be treated as read-only.
+type Dedup[T any] struct {
+	mu         sync.Mutex
+	seed       maphash.Seed
+	entries    map[uint64][]*dedupEntry[T]
+	n, max     int
+	shareValue bool
+}
+
+// dedupEntry is an interned encoding and, if values are shared, its decoded value.
+type dedupEntry[T any] struct {
+	data []byte
+	val  *T
+}
+
+// NewDedup creates a Dedup[T] holding at most maxEntries distinct encodings, or
+// DefaultDedupEntries if maxEntries is not positive. The store is reset when it is full.
+// If shareValue is true, identical encodings also share their decoded value.
+func NewDedup[T any](shareValue bool, maxEntries int) *Dedup[T] {
+	if maxEntries <= 0 {
+		maxEntries = DefaultDedupEntries
+	}
+	return &Dedup[T]{
+		seed:       maphash.MakeSeed(),
+		entries:    map[uint64][]*dedupEntry[T]{},
+		max:        maxEntries,
+		shareValue: shareValue,
+	}
+}
+
+// NewFromBytes creates a JitJSON[T] from JSON byte data, reusing the buffer of any
+// previously seen identical encoding. The data is copied the first time it is seen,
+// so the caller may reuse it afterwards.
+func (d *Dedup[T]) NewFromBytes(data []byte) *JitJSON[T] {
+	h := maphash.Bytes(d.seed, data)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range d.entries[h] {
+		if bytes.Equal(e.data, data) {
+			return e.value()
+		}
+	}
+
+	if d.n >= d.max {
+		clear(d.entries)
+		d.n = 0
+	}
+	e := &dedupEntry[T]{data: bytes.Clone(data)}
+	if d.shareValue {
+		recordDeferredUnmarshal(len(e.data))
+		if val, err := (&JitJSON[T]{data: e.data}).Unmarshal(); err == nil {
+			e.val = &val
+		}
+	}
+	d.entries[h] = append(d.entries[h], e)
+	d.n++
+	return e.value()
+}
+
+// value returns a new JitJSON[T] holding the entry. Its encoding is recorded as deferred
+// unless it holds the shared value, whose decoding was recorded when it was first seen.
+func (e *dedupEntry[T]) value() *JitJSON[T] {
+	jit := &JitJSON[T]{data: e.data}
+	if e.val == nil {
+		recordDeferredUnmarshal(len(e.data))
+		return jit
+	}
+	val := *e.val
+	jit.val = &val
+	return jit
+}
+
+// Len returns the number of distinct encodings held by the store.
+func (d *Dedup[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.n
+}
+
+// Reset releases all interned encodings. Values previously returned remain valid.
+func (d *Dedup[T]) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	clear(d.entries)
+	d.n = 0
+}
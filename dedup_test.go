@@ -0,0 +1,98 @@
+package jitjson_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestDedup(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+
+	t.Run("Share buffer", func(t *testing.T) {
+		store := jitjson.NewDedup[Person](false, 0)
+
+		jit1 := store.NewFromBytes(jsonData)
+		jit2 := store.NewFromBytes([]byte(string(jsonData)))
+		if jit1 == jit2 {
+			t.Error("expected distinct values")
+		}
+
+		data1, _ := jit1.Marshal()
+		data2, _ := jit2.Marshal()
+		if &data1[0] != &data2[0] {
+			t.Error("expected values to share a buffer")
+		}
+		if &data1[0] == &jsonData[0] {
+			t.Error("expected input to be copied")
+		}
+
+		if store.Len() != 1 {
+			t.Errorf("expected 1 entry, got %d", store.Len())
+		}
+
+		store.NewFromBytes([]byte(`{"Name":"Jane"}`))
+		if store.Len() != 2 {
+			t.Errorf("expected 2 entries, got %d", store.Len())
+		}
+
+		store.Reset()
+		if store.Len() != 0 {
+			t.Errorf("expected 0 entries, got %d", store.Len())
+		}
+	})
+
+	t.Run("Share value", func(t *testing.T) {
+		store := jitjson.NewDedup[Person](true, 0)
+
+		jit1 := store.NewFromBytes(jsonData)
+		jit2 := store.NewFromBytes(jsonData)
+		if jit1 == jit2 {
+			t.Error("expected distinct values")
+		}
+
+		p, err := jit2.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" {
+			t.Error("values do not match")
+		}
+
+		jit1.Set(Person{Name: "Jane"})
+		if p, _ := jit2.Unmarshal(); p.Name != "John" {
+			t.Errorf("expected Set to affect one holder only, got %s", p.Name)
+		}
+		if store.NewFromBytes(jsonData); store.Len() != 1 {
+			t.Errorf("expected 1 entry, got %d", store.Len())
+		}
+	})
+
+	t.Run("Concurrent holders", func(t *testing.T) {
+		store := jitjson.NewDedup[Person](true, 0)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				jit := store.NewFromBytes(jsonData)
+				if p, err := jit.Unmarshal(); err != nil || p.Name != "John" {
+					t.Errorf("unexpected value %+v, %v", p, err)
+				}
+				jit.Set(Person{Name: "Jane"})
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("Bound", func(t *testing.T) {
+		store := jitjson.NewDedup[Person](false, 2)
+		for _, data := range []string{`{"Age":1}`, `{"Age":2}`, `{"Age":3}`} {
+			store.NewFromBytes([]byte(data))
+		}
+		if store.Len() != 1 {
+			t.Errorf("expected the full store to be reset, got %d entries", store.Len())
+		}
+	})
+}
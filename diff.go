@@ -0,0 +1,112 @@
+package jitjson
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+// DiffAgainst returns the sorted names of the top-level members that differ between the
+// encoding of JitJSON[T] and the JSON object other, including members present in only
+// one of them. Values are compared token by token in canonical form, so whitespace,
+// string escapes and the order of nested members do not count as changes, while numbers
+// are compared as written. Neither encoding is decoded, which lets change-data-capture
+// pipelines cheaply skip unchanged records. A value without an encoding is marshaled
+// first.
+func (jit *JitJSON[T]) DiffAgainst(other []byte) ([]string, error) {
+	data, err := jit.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = []byte("null")
+	}
+	mine, err := objectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := objectMembers(other)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for k, v := range mine {
+		if w, ok := theirs[k]; !ok || !equalTokens(v, w) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range theirs {
+		if _, ok := mine[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// MarshalIfChanged returns the encoding of JitJSON[T] and true if it differs from
+// previous, or nil and false if the two are semantically the same, so sync and
+// replication loops can skip redundant writes:
+//
+//	data, changed, err := jit.MarshalIfChanged(lastWritten)
+//	if changed {
+//		err = store.Put(ctx, key, data)
+//	}
+//
+// Encodings are compared in canonical form as by DiffAgainst, so whitespace, string
+// escapes and member order do not count as changes. A JitJSON[T] holding nothing
+// matches a nil previous. A value without an encoding is marshaled first.
+func (jit *JitJSON[T]) MarshalIfChanged(previous []byte) ([]byte, bool, error) {
+	data, err := jit.Marshal()
+	if err != nil {
+		return nil, false, err
+	}
+	if data == nil || previous == nil {
+		if data == nil && previous == nil {
+			return nil, false, nil
+		}
+		return data, true, nil
+	}
+	if equalTokens(data, previous) {
+		return nil, false, nil
+	}
+	return data, true, nil
+}
+
+// objectMembers returns the raw values of the members of the JSON object in data by
+// unescaped key. Like encoding/json, the last of duplicate members wins.
+func objectMembers(data []byte) (map[string][]byte, error) {
+	members := map[string][]byte{}
+	var kerr error
+	ok := splitObject(data, func(key, val []byte) bool {
+		k, err := unquote(key)
+		if err != nil {
+			kerr = err
+			return false
+		}
+		members[k] = val
+		return true
+	})
+	if kerr != nil {
+		return nil, kerr
+	}
+	if !ok {
+		return nil, errors.New("jitjson: invalid json object")
+	}
+	return members, nil
+}
+
+// equalTokens reports whether the JSON values a and b have the same canonical encoding,
+// with numbers as written. Malformed values are compared byte for byte.
+func equalTokens(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	ca, err := canonicalize(a, false)
+	if err != nil {
+		return false
+	}
+	cb, err := canonicalize(b, false)
+	return err == nil && bytes.Equal(ca, cb)
+}
@@ -0,0 +1,62 @@
+package jitjson_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestDiffAgainst(t *testing.T) {
+	jit := jitjson.NewFromBytes[map[string]interface{}]([]byte(
+		`{"id": 1, "name": "a", "tags": {"x": 1, "y": 2}, "price": 1.0, "gone": true}`))
+
+	changed, err := jit.DiffAgainst([]byte(
+		`{"id":1,"name":"a","tags":{"y":2,"x":1},"price":1,"added":null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"added", "gone", "price"}
+	if !reflect.DeepEqual(changed, want) {
+		t.Errorf("expected %v, got %v", want, changed)
+	}
+
+	changed, err = jit.DiffAgainst([]byte(`{"id":1,"name":"a","tags":{"x":1,"y":2},"price":1.0,"gone":true}`))
+	if err != nil || len(changed) != 0 {
+		t.Errorf("expected no changes, got %v, %v", changed, err)
+	}
+
+	person := jitjson.New(Person{Name: "John", Age: 30})
+	changed, err = person.DiffAgainst([]byte(`{"Name":"John","Age":31,"City":""}`))
+	if err != nil || !reflect.DeepEqual(changed, []string{"Age"}) {
+		t.Errorf("expected [Age], got %v, %v", changed, err)
+	}
+
+	if _, err := jit.DiffAgainst([]byte(`[1]`)); err == nil {
+		t.Error("expected error for a non-object")
+	}
+}
+
+func TestMarshalIfChanged(t *testing.T) {
+	jit := jitjson.New(Person{Name: "John", Age: 30})
+	previous := []byte(`{ "Age": 30, "City": "", "Name": "John" }`)
+
+	data, changed, err := jit.MarshalIfChanged(previous)
+	if err != nil || changed || data != nil {
+		t.Errorf("expected no change, got %s, %v, %v", data, changed, err)
+	}
+
+	jit.Set(Person{Name: "John", Age: 31})
+	data, changed, err = jit.MarshalIfChanged(previous)
+	if err != nil || !changed || string(data) != `{"Name":"John","Age":31,"City":""}` {
+		t.Errorf("expected the new encoding, got %s, %v, %v", data, changed, err)
+	}
+
+	if _, changed, _ := jit.MarshalIfChanged(nil); !changed {
+		t.Error("expected a change against nothing")
+	}
+	var empty jitjson.JitJSON[Person]
+	if _, changed, _ := empty.MarshalIfChanged(nil); changed {
+		t.Error("expected nothing to match nothing")
+	}
+}
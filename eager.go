@@ -0,0 +1,64 @@
+package jitjson
+
+import (
+	"context"
+	"time"
+)
+
+// WithEagerThreshold makes UnmarshalJSON, SetBytes and NewFromBytes decode data smaller
+// than n bytes immediately rather than deferring it. For tiny objects the bookkeeping
+// of deferring the decode costs more than the decode itself, so payloads under the
+// threshold are decoded inline, as encoding/json would, while larger ones stay lazy.
+// NewFromBytes allocates the JitJSON[T] and its value together for such payloads.
+// Errors decoding eagerly are returned by Unmarshal, as if the decode had been deferred,
+// and the data is kept so Marshal returns it without encoding. The threshold does not
+// apply with WithBytesTransform or a Retention dropping the value. Zero, the default,
+// disables eager decoding; see BenchmarkEagerThreshold for tuning it.
+func WithEagerThreshold(n int) Option {
+	return func(o *options) {
+		o.eagerBelow = n
+	}
+}
+
+// eager reports whether data is below the configured eager threshold, or the configured
+// Adaptive decodes eagerly.
+func (o *options) eager(data []byte) bool {
+	if o == nil || o.transform != nil || !o.keeps(KeepValue) {
+		return false
+	}
+	return len(data) < o.eagerBelow || (o.adaptive != nil && o.adaptive.Eager())
+}
+
+// newEager creates JitJSON[T] from data decoded eagerly. Unless the value is pooled, the
+// JitJSON[T] and its value are allocated together.
+func newEager[T any](data []byte, o *options) *JitJSON[T] {
+	if poolFor[T](o) != nil {
+		jit := &JitJSON[T]{opts: o}
+		jit.decodeEager(data, jit.newValue())
+		return jit
+	}
+	e := &struct {
+		jit JitJSON[T]
+		val T
+	}{jit: JitJSON[T]{opts: o}}
+	e.jit.decodeEager(data, &e.val)
+	return &e.jit
+}
+
+// decodeEager sets JitJSON[T] to data, decoding it into val.
+func (jit *JitJSON[T]) decodeEager(data []byte, val *T) {
+	jit.val = val
+	stats.unmarshals.Add(1)
+	jit.verr = jit.opts.decode(context.Background(), data, jit.val)
+	if jit.verr == nil {
+		jit.verr = afterDecode(jit.opts, jit.val)
+	}
+	if jit.opts.ttl > 0 {
+		jit.extend().decodedAt = time.Now().UnixNano()
+	}
+	jit.data = nil
+	if jit.opts.keeps(KeepBytes) {
+		jit.data = data
+	}
+	jit.dropEncoding()
+}
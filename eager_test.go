@@ -0,0 +1,57 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWithEagerThreshold(t *testing.T) {
+	small := []byte(`{"Name": "John", "Age": 30}`)
+	large := []byte(`{"Name": "John", "Age": 30, "City": "` + string(make([]byte, 64)) + `"}`)
+
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	jit := jitjson.NewFromBytes[Person](small, jitjson.WithEagerThreshold(64), count)
+	if decodes != 1 {
+		t.Errorf("expected an eager decode, got %d", decodes)
+	}
+	p, err := jit.Unmarshal()
+	if err != nil || p.Name != "John" {
+		t.Errorf("unexpected value %v, %v", p, err)
+	}
+	out, err := jit.Marshal()
+	if err != nil || string(out) != string(small) {
+		t.Errorf("expected the data to be kept, got %s, %v", out, err)
+	}
+
+	decodes = 0
+	jitjson.NewFromBytes[Person](large, jitjson.WithEagerThreshold(64), count)
+	if decodes != 0 {
+		t.Errorf("expected a deferred decode, got %d", decodes)
+	}
+
+	jit = jitjson.NewFromBytes[Person](nil, jitjson.WithEagerThreshold(64))
+	if err := jit.UnmarshalJSON([]byte(`{"Age": "thirty"}`)); err != nil {
+		t.Errorf("expected the decode error to be deferred, got %v", err)
+	}
+	if _, err := jit.Unmarshal(); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+}
+
+func TestConfigureEagerThreshold(t *testing.T) {
+	jitjson.Configure(jitjson.Config{EagerThreshold: 64})
+	defer jitjson.Configure(jitjson.Config{})
+
+	var v struct {
+		Person *jitjson.JitJSON[Person] `json:"person"`
+	}
+	if err := json.Unmarshal([]byte(`{"person": {"Name": "Jane"}}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Person.DebugState().HasValue {
+		t.Error("expected an eager decode")
+	}
+}
@@ -0,0 +1,226 @@
+// Package envelope provides the CloudEvents, SNS notification and SQS message envelopes
+// whose payloads are held lazily, so event routers can inspect attributes and leave
+// decoding the payload to the handler that receives it.
+package envelope
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// ErrNoData is returned for envelopes without a payload.
+var ErrNoData = errors.New("envelope: no data")
+
+// CloudEvent is a CloudEvents 1.0 event in the structured JSON format. Data is kept as
+// raw bytes until accessed with Data. Extension attributes are kept in Extensions.
+type CloudEvent struct {
+	SpecVersion     string              `json:"specversion"`
+	ID              string              `json:"id"`
+	Source          string              `json:"source"`
+	Type            string              `json:"type"`
+	Subject         string              `json:"subject,omitempty"`
+	Time            *time.Time          `json:"time,omitempty"`
+	DataContentType string              `json:"datacontenttype,omitempty"`
+	DataSchema      string              `json:"dataschema,omitempty"`
+	Data            *jitjson.AnyJitJSON `json:"data,omitempty"`
+	DataBase64      []byte              `json:"data_base64,omitempty"`
+
+	// Extensions holds the members that are not attributes defined by the specification.
+	Extensions map[string]*jitjson.AnyJitJSON `json:"-"`
+}
+
+// cloudEvent has the fields of CloudEvent without its methods.
+type cloudEvent CloudEvent
+
+// attributes are the members of a CloudEvent that are not extensions.
+var attributes = map[string]bool{
+	"specversion": true, "id": true, "source": true, "type": true, "subject": true, "time": true,
+	"datacontenttype": true, "dataschema": true, "data": true, "data_base64": true,
+}
+
+// MarshalJSON encodes the event with its extension attributes.
+func (e *CloudEvent) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*cloudEvent)(e))
+	if err != nil || len(e.Extensions) == 0 {
+		return data, err
+	}
+	ext, err := json.Marshal(e.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 2 {
+		return ext, nil
+	}
+	data = append(data[:len(data)-1], ',')
+	return append(data, ext[1:]...), nil
+}
+
+// UnmarshalJSON decodes the attributes of the event, keeping data and extension
+// attributes as raw bytes.
+func (e *CloudEvent) UnmarshalJSON(data []byte) error {
+	var members map[string]*jitjson.AnyJitJSON
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, (*cloudEvent)(e)); err != nil {
+		return err
+	}
+	e.Extensions = nil
+	for key, val := range members {
+		if attributes[key] {
+			continue
+		}
+		if e.Extensions == nil {
+			e.Extensions = map[string]*jitjson.AnyJitJSON{}
+		}
+		e.Extensions[key] = val
+	}
+	return nil
+}
+
+// Data returns the data of the event as a JitJSON[T] without decoding it, from
+// data_base64 if the event carries binary data. ErrNoData is returned if the event
+// has neither.
+func Data[T any](e *CloudEvent, opts ...jitjson.Option) (*jitjson.JitJSON[T], error) {
+	switch {
+	case e.Data != nil:
+		data, err := e.Data.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return jitjson.NewFromBytes[T](data, opts...), nil
+	case e.DataBase64 != nil:
+		return jitjson.NewFromBytes[T](e.DataBase64, opts...), nil
+	}
+	return nil, ErrNoData
+}
+
+// SNSNotification is an Amazon SNS notification, as delivered to HTTP endpoints and to
+// SQS queues without raw message delivery. Message holds the published payload, which
+// is decoded with Message.
+type SNSNotification struct {
+	Type              string                  `json:"Type"`
+	MessageID         string                  `json:"MessageId"`
+	TopicARN          string                  `json:"TopicArn"`
+	Subject           string                  `json:"Subject,omitempty"`
+	Message           string                  `json:"Message"`
+	Timestamp         time.Time               `json:"Timestamp"`
+	SignatureVersion  string                  `json:"SignatureVersion,omitempty"`
+	Signature         string                  `json:"Signature,omitempty"`
+	SigningCertURL    string                  `json:"SigningCertURL,omitempty"`
+	UnsubscribeURL    string                  `json:"UnsubscribeURL,omitempty"`
+	MessageAttributes map[string]SNSAttribute `json:"MessageAttributes,omitempty"`
+}
+
+// SNSAttribute is a message attribute of an SNSNotification.
+type SNSAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// Message returns the message of the notification as a JitJSON[T] without decoding it.
+func Message[T any](n *SNSNotification, opts ...jitjson.Option) *jitjson.JitJSON[T] {
+	return jitjson.NewFromBytes[T]([]byte(n.Message), opts...)
+}
+
+// SQSBody is the body of an Amazon SQS message, such as the body member of the records
+// of a Lambda event. It holds the payload itself, or an SNSNotification wrapping it for
+// queues subscribed to a topic without raw message delivery.
+type SQSBody string
+
+// Notification decodes the SNS notification wrapping the payload, reporting false if
+// the body is not one.
+func (b SQSBody) Notification() (*SNSNotification, bool, error) {
+	if !isNotification([]byte(b)) {
+		return nil, false, nil
+	}
+	var n SNSNotification
+	if err := json.Unmarshal([]byte(b), &n); err != nil {
+		return nil, true, err
+	}
+	return &n, true, nil
+}
+
+// Payload returns the payload of the body as a JitJSON[T] without decoding it,
+// unwrapping it first from an SNS notification and a CloudEvent as with Unwrap.
+func Payload[T any](b SQSBody, opts ...jitjson.Option) (*jitjson.JitJSON[T], error) {
+	return Unwrap[T]([]byte(b), opts...)
+}
+
+// maxLayers bounds the envelopes removed by Unwrap.
+const maxLayers = 8
+
+// Unwrap returns the payload of data as a JitJSON[T] without decoding it, removing the
+// SNS notifications and CloudEvents wrapping it in any order, such as a CloudEvent
+// published to an SNS topic and delivered to an SQS queue. Only the members locating
+// the payload are read from each envelope. Data that is not an envelope is returned
+// as the payload.
+func Unwrap[T any](data []byte, opts ...jitjson.Option) (*jitjson.JitJSON[T], error) {
+	for range maxLayers {
+		inner, ok, err := unwrapLayer(data)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return jitjson.NewFromBytes[T](data, opts...), nil
+		}
+		data = inner
+	}
+	return nil, fmt.Errorf("envelope: more than %d nested envelopes", maxLayers)
+}
+
+// unwrapLayer returns the payload of the SNS notification or CloudEvent data, reporting
+// false if data is neither.
+func unwrapLayer(data []byte) ([]byte, bool, error) {
+	if isNotification(data) {
+		raw, ok, err := jitjson.MemberBytes(data, "Message")
+		if err != nil || !ok {
+			return nil, true, notFound(err, "SNS notification has no Message")
+		}
+		var msg string
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil, true, err
+		}
+		return []byte(msg), true, nil
+	}
+
+	if _, ok, _ := jitjson.MemberBytes(data, "specversion"); !ok {
+		return nil, false, nil
+	}
+	if raw, ok, err := jitjson.MemberBytes(data, "data"); err != nil || ok {
+		return raw, true, err
+	}
+	raw, ok, err := jitjson.MemberBytes(data, "data_base64")
+	if err != nil || !ok {
+		return nil, true, notFound(err, "CloudEvent has no data")
+	}
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, true, err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	return decoded, true, err
+}
+
+// isNotification reports whether data is an SNS notification.
+func isNotification(data []byte) bool {
+	typ, ok, err := jitjson.MemberBytes(data, "Type")
+	if err != nil || !ok || string(typ) != `"Notification"` {
+		return false
+	}
+	_, ok, err = jitjson.MemberBytes(data, "TopicArn")
+	return err == nil && ok
+}
+
+// notFound returns err, or ErrNoData described by msg if err is nil.
+func notFound(err error, msg string) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrNoData, msg)
+}
@@ -0,0 +1,108 @@
+package envelope_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson/envelope"
+)
+
+type order struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+const event = `{"specversion": "1.0", "id": "e1", "source": "/orders", "type": "order.created", "tenant": "acme", "data": {"id": "o1", "total": 42}}`
+
+func TestCloudEvent(t *testing.T) {
+	var e envelope.CloudEvent
+	if err := json.Unmarshal([]byte(event), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != "order.created" || e.Extensions["tenant"].String() != `"acme"` {
+		t.Errorf("unexpected attributes %+v", e)
+	}
+
+	jit, err := envelope.Data[order](&e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := jit.Unmarshal()
+	if err != nil || o.Total != 42 {
+		t.Errorf("unexpected data %v, %v", o, err)
+	}
+
+	out, err := json.Marshal(&e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var round envelope.CloudEvent
+	if err := json.Unmarshal(out, &round); err != nil {
+		t.Fatal(err)
+	}
+	if round.ID != "e1" || round.Extensions["tenant"] == nil || round.Data.String() != e.Data.String() {
+		t.Errorf("unexpected round trip %s", out)
+	}
+
+	if _, err := envelope.Data[order](&envelope.CloudEvent{}); !errors.Is(err, envelope.ErrNoData) {
+		t.Errorf("expected ErrNoData, got %v", err)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	notification := `{"Type": "Notification", "MessageId": "m1", "TopicArn": "arn:aws:sns:eu-west-1:1:orders", "Message": ` +
+		strconv.Quote(event) + `, "Timestamp": "2024-03-01T00:00:00Z"}`
+	body := envelope.SQSBody(notification)
+
+	n, ok, err := body.Notification()
+	if err != nil || !ok || n.MessageID != "m1" {
+		t.Fatalf("unexpected notification %v, %v, %v", n, ok, err)
+	}
+	if _, err := envelope.Message[envelope.CloudEvent](n).Unmarshal(); err != nil {
+		t.Error(err)
+	}
+
+	jit, err := envelope.Payload[order](body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := jit.Unmarshal()
+	if err != nil || o.ID != "o1" {
+		t.Errorf("unexpected payload %v, %v", o, err)
+	}
+
+	plain := `{"specVersion":"2","Data":{"x":1},"name":"n"}`
+	raw, err := envelope.Unwrap[json.RawMessage]([]byte(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := raw.Marshal(); string(out) != plain {
+		t.Errorf("expected a payload with differently cased names as is, got %s", out)
+	}
+
+	binary := `{"specversion": "1.0", "id": "e2", "data_base64": "eyJpZCI6Im8yIn0="}`
+	jit, err = envelope.Unwrap[order]([]byte(binary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, err := jit.Unmarshal(); err != nil || o.ID != "o2" {
+		t.Errorf("unexpected payload %v, %v", o, err)
+	}
+
+	if _, ok, _ := envelope.SQSBody(`{"id": "o3"}`).Notification(); ok {
+		t.Error("expected a raw body not to be a notification")
+	}
+	jit, err = envelope.Unwrap[order]([]byte(`{"id": "o3"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, err := jit.Unmarshal(); err != nil || o.ID != "o3" {
+		t.Errorf("unexpected payload %v, %v", o, err)
+	}
+
+	if _, err := envelope.Unwrap[order]([]byte(`{"specversion": "1.0"}`)); !errors.Is(err, envelope.ErrNoData) {
+		t.Errorf("expected ErrNoData, got %v", err)
+	}
+}
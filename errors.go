@@ -0,0 +1,180 @@
+package jitjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError is returned by Unmarshal when decoding fails at a known position in the
+// JSON data. Because decoding is deferred, the failure may surface long after the data
+// was received; the position and path locate it within the original payload.
+type ParseError struct {
+	// Offset is the byte offset in the data at which decoding failed.
+	Offset int64
+	// Line and Column are the 1-based position corresponding to Offset.
+	Line, Column int
+	// Path locates the failing value within the document, such as $.users[3].name.
+	Path string
+	// Err is the error reported by the parser.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("jitjson: %v at line %d, column %d (path %s)", e.Err, e.Line, e.Column, e.Path)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError wraps err in a *ParseError if it reports an offset within data. If the
+// data decoded was the output of convertTimes for data, spans locates the converted
+// times so the offset is mapped back to data.
+func newParseError(data []byte, err error, spans []timeSpan) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+	offset = sourceOffset(spans, offset)
+	if offset < 0 || offset > int64(len(data)) {
+		return err
+	}
+
+	prefix := data[:offset]
+	line := bytes.Count(prefix, []byte{'\n'}) + 1
+	column := len(prefix) - bytes.LastIndexByte(prefix, '\n')
+	return &ParseError{
+		Offset: offset,
+		Line:   line,
+		Column: column,
+		Path:   pathAt(data, int(offset)),
+		Err:    err,
+	}
+}
+
+// pathFrame is an open array or object on the path to an offset.
+type pathFrame struct {
+	object    bool
+	expectKey bool
+	key       string
+	index     int
+}
+
+// pathAt returns the path of the value enclosing data[offset], scanning the data up to
+// offset without decoding it.
+func pathAt(data []byte, offset int) string {
+	var stack []pathFrame
+	for i := 0; i < offset && i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			end := stringEnd(data, i)
+			if end < 0 || end > offset {
+				i = offset
+				break
+			}
+			if n := len(stack); n > 0 && stack[n-1].expectKey {
+				stack[n-1].key, _ = unquote(data[i:end])
+			}
+			i = end - 1
+		case '{':
+			stack = append(stack, pathFrame{object: true, expectKey: true})
+		case '[':
+			stack = append(stack, pathFrame{})
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ':':
+			if n := len(stack); n > 0 {
+				stack[n-1].expectKey = false
+			}
+		case ',':
+			if n := len(stack); n > 0 {
+				if stack[n-1].object {
+					stack[n-1].expectKey = true
+				} else {
+					stack[n-1].index++
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("$")
+	for _, f := range stack {
+		switch {
+		case !f.object:
+			b.WriteString("[" + strconv.Itoa(f.index) + "]")
+		case f.key != "":
+			b.WriteString("." + f.key)
+		}
+	}
+	return b.String()
+}
+
+// SyntaxError, TypeError and LimitError classify the errors returned when decoding or
+// accepting data, whichever parser reported them, so callers can branch on the class of
+// failure with errors.As or IsSyntaxError, IsTypeMismatch and IsLimitError. Each wraps
+// the original error, which remains available to errors.Is and errors.As, and has the
+// same message. Decoding errors are classified from the encoding/json error types;
+// custom parsers classify their own errors by returning them wrapped in these types.
+type (
+	// SyntaxError reports data that is not well-formed.
+	SyntaxError struct{ Err error }
+	// TypeError reports well-formed data that does not fit the type decoded into.
+	TypeError struct{ Err error }
+	// LimitError reports data rejected by a size or depth limit.
+	LimitError struct{ Err error }
+)
+
+func (e *SyntaxError) Error() string { return e.Err.Error() }
+func (e *SyntaxError) Unwrap() error { return e.Err }
+func (e *TypeError) Error() string   { return e.Err.Error() }
+func (e *TypeError) Unwrap() error   { return e.Err }
+func (e *LimitError) Error() string  { return e.Err.Error() }
+func (e *LimitError) Unwrap() error  { return e.Err }
+
+// IsSyntaxError reports whether err is or wraps a *SyntaxError.
+func IsSyntaxError(err error) bool {
+	var e *SyntaxError
+	return errors.As(err, &e)
+}
+
+// IsTypeMismatch reports whether err is or wraps a *TypeError.
+func IsTypeMismatch(err error) bool {
+	var e *TypeError
+	return errors.As(err, &e)
+}
+
+// IsLimitError reports whether err is or wraps a *LimitError.
+func IsLimitError(err error) bool {
+	var e *LimitError
+	return errors.As(err, &e)
+}
+
+// classify wraps a parser error in its class, leaving errors that are already
+// classified, or that cannot be, unchanged.
+func classify(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case IsSyntaxError(err) || IsTypeMismatch(err) || IsLimitError(err):
+		return err
+	case errors.As(err, &syntaxErr):
+		return &SyntaxError{Err: err}
+	case errors.As(err, &typeErr):
+		return &TypeError{Err: err}
+	}
+	return err
+}
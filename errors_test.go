@@ -0,0 +1,90 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestParseError(t *testing.T) {
+	type Doc struct {
+		Users []struct {
+			Name string `json:"name"`
+			Age  int    `json:"age"`
+		} `json:"users"`
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		line   int
+		path   string
+		syntax bool
+	}{
+		{"Type error", "{\n  \"users\": [\n    {\"name\": \"John\"},\n    {\"name\": \"Jane\", \"age\": \"old\"}\n  ]\n}", 4, "$.users[1].age", false},
+		{"Syntax error", "{\"users\": [{\"name\": \"John\"}, {\"name\" \"Jane\"}]}", 1, "$.users[1].name", true},
+		{"Escaped key", `{"users": [{"na\"me": 1, "age": true}]}`, 1, "$.users[0].age", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jit := jitjson.NewFromBytes[Doc]([]byte(tc.data))
+			_, err := jit.Unmarshal()
+
+			var parseErr *jitjson.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected ParseError, got %T %v", err, err)
+			}
+			if parseErr.Line != tc.line || parseErr.Path != tc.path {
+				t.Errorf("expected line %d path %s, got line %d path %s", tc.line, tc.path, parseErr.Line, parseErr.Path)
+			}
+			if parseErr.Column < 1 || parseErr.Offset <= 0 {
+				t.Errorf("unexpected position %+v", parseErr)
+			}
+
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) != tc.syntax {
+				t.Errorf("expected underlying error to be preserved, got %v", parseErr.Err)
+			}
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	_, err := jitjson.NewFromBytes[Person]([]byte(`{"Name": "John",`)).Unmarshal()
+	if !jitjson.IsSyntaxError(err) || jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+	var parseErr *jitjson.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expected the ParseError to be kept, got %T", err)
+	}
+
+	_, err = jitjson.NewFromBytes[Person]([]byte(`{"Age": "old"}`)).Unmarshal()
+	var typeErr *jitjson.TypeError
+	if !errors.As(err, &typeErr) || jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a type error, got %v", err)
+	}
+	var jsonErr *json.UnmarshalTypeError
+	if !errors.As(err, &jsonErr) {
+		t.Error("expected the parser's error to be wrapped")
+	}
+
+	_, err = jitjson.NewFromBytes[any]([]byte(`1 2`), jitjson.WithUseNumber()).Unmarshal()
+	if !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected trailing data to be a syntax error, got %v", err)
+	}
+
+	var jit jitjson.JitJSON[Person]
+	jit.SetOptions(jitjson.WithMaxBytes(4))
+	err = jit.SetBytes([]byte(`{"Name": "John"}`))
+	if !jitjson.IsLimitError(err) || !errors.Is(err, jitjson.ErrTooLarge) {
+		t.Errorf("expected a limit error, got %v", err)
+	}
+
+	custom := errors.New("custom")
+	if err := (&jitjson.SyntaxError{Err: custom}); err.Error() != "custom" || !errors.Is(err, custom) {
+		t.Errorf("expected the wrapped error to show through, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package jitjson
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// ETag returns a strong HTTP entity tag for the encoding of JitJSON[T]: the quoted,
+// base64url encoded SHA-256 of the bytes returned by Marshal, which are the canonical
+// encoding if WithCanonical is set and the raw bytes otherwise. The value is not
+// decoded, only hashed, so HTTP layers can answer conditional requests for lazily held
+// resources cheaply:
+//
+//	etag, err := jit.ETag()
+//	if r.Header.Get("If-None-Match") == etag {
+//		w.WriteHeader(http.StatusNotModified)
+//		return
+//	}
+//	w.Header().Set("ETag", etag)
+//
+// A value without bytes is marshaled first. A JitJSON[T] holding nothing has the tag
+// of null.
+func (jit *JitJSON[T]) ETag() (string, error) {
+	data, err := jit.Marshal()
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		data = []byte("null")
+	}
+	sum := sha256.Sum256(data)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`, nil
+}
@@ -0,0 +1,54 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestETag(t *testing.T) {
+	var decodes int
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`),
+		jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+	etag, err := jit.ETag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(etag) != 45 || etag[0] != '"' || etag[44] != '"' {
+		t.Errorf("expected a quoted strong ETag, got %s", etag)
+	}
+
+	again, _ := jit.ETag()
+	if again != etag {
+		t.Errorf("expected a stable ETag, got %s and %s", etag, again)
+	}
+	if decodes != 0 {
+		t.Errorf("expected the ETag to be computed without decoding, got %d decodes", decodes)
+	}
+
+	jit.Set(Person{Name: "Jane"})
+	changed, err := jit.ETag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == etag {
+		t.Error("expected the ETag to change with the value")
+	}
+
+	other := jitjson.NewFromBytes[Person]([]byte(`{"Age":30,"Name":"John"}`))
+	if tag, _ := other.ETag(); tag == etag {
+		t.Error("expected different raw bytes to give different ETags")
+	}
+	canonical := jitjson.NewFromBytes[Person]([]byte(`{"Age":30, "Name":"John"}`), jitjson.WithCanonical())
+	sorted := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`), jitjson.WithCanonical())
+	a, _ := canonical.ETag()
+	b, _ := sorted.ETag()
+	if a != b {
+		t.Error("expected equal canonical encodings to give equal ETags")
+	}
+
+	var empty jitjson.JitJSON[Person]
+	if _, err := empty.ETag(); err != nil {
+		t.Errorf("expected an empty value to have an ETag, got %v", err)
+	}
+}
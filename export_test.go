@@ -0,0 +1,4 @@
+package jitjson
+
+// UnregisterParser removes a parser registered by a test.
+var UnregisterParser = unregisterParser
@@ -0,0 +1,86 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// FieldBytes returns the raw encoding of the member key of the JSON object in data,
+// scanning the object at token level without decoding it. Keys are matched
+// case-insensitively and, as encoding/json decodes every matching member into the same
+// field, the last match is returned, so {"Age":30,"age":31} gives 31 for "Age". The
+// result is a sub-slice of data. If the object has no such member, FieldBytes returns
+// nil, false.
+func FieldBytes(data []byte, key string) ([]byte, bool, error) {
+	var raw []byte
+	ok := splitObject(data, func(k, val []byte) bool {
+		name, err := unquote(k)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(name, key) {
+			raw = val
+		}
+		return true
+	})
+	if !ok {
+		return nil, false, errors.New("jitjson: invalid json object")
+	}
+	return raw, raw != nil, nil
+}
+
+// MemberBytes returns the raw encoding of the member key of the JSON object in data, like
+// FieldBytes, but matching key exactly, as protocols with case-sensitive member names
+// require. If the object has duplicate members named key, the last one is returned, as
+// AnyJitJSON.AsObject keeps. If the object has no such member, MemberBytes returns nil,
+// false.
+func MemberBytes(data []byte, key string) ([]byte, bool, error) {
+	var raw []byte
+	ok := splitObject(data, func(k, val []byte) bool {
+		name, err := unquote(k)
+		if err != nil {
+			return false
+		}
+		if name == key {
+			raw = val
+		}
+		return true
+	})
+	if !ok {
+		return nil, false, errors.New("jitjson: invalid json object")
+	}
+	return raw, raw != nil, nil
+}
+
+// Field is a member of a JSON object that is decoded on first access, giving laziness
+// at the granularity of individual struct fields. It is used by the accessor types
+// generated by cmd/jitjsongen. The zero value is ready to use.
+type Field[T any] struct {
+	val  T
+	err  error
+	done bool
+}
+
+// Get decodes the member key of the JSON object in data on the first call and returns
+// the cached result on later calls. A missing member decodes to the zero value of T.
+func (f *Field[T]) Get(data []byte, key string) (T, error) {
+	if f.done {
+		stats.unmarshalCacheHits.Add(1)
+		return f.val, f.err
+	}
+	f.done = true
+	raw, ok, err := FieldBytes(data, key)
+	if err != nil || !ok {
+		f.err = err
+		return f.val, f.err
+	}
+	stats.unmarshals.Add(1)
+	f.err = json.Unmarshal(raw, &f.val)
+	return f.val, f.err
+}
+
+// Reset discards the decoded value, so the next Get decodes the member again.
+func (f *Field[T]) Reset() {
+	*f = Field[T]{}
+}
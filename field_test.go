@@ -0,0 +1,78 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestFieldBytes(t *testing.T) {
+	data := []byte(`{"name": "John", "Age": 30, "age": 31, "tags": ["a", "b"], "key": {"x": 1}}`)
+
+	tests := []struct {
+		key   string
+		want  string
+		found bool
+	}{
+		{"name", `"John"`, true},
+		{"NAME", `"John"`, true},
+		{"age", `31`, true},
+		{"Age", `31`, true},
+		{"tags", `["a", "b"]`, true},
+		{"key", `{"x": 1}`, true},
+		{"missing", ``, false},
+	}
+	for _, tc := range tests {
+		raw, ok, err := jitjson.FieldBytes(data, tc.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tc.found || string(raw) != tc.want {
+			t.Errorf("%s: expected %q %v, got %q %v", tc.key, tc.want, tc.found, raw, ok)
+		}
+	}
+
+	if _, _, err := jitjson.FieldBytes([]byte(`[1, 2]`), "name"); err == nil {
+		t.Error("expected error for non-object")
+	}
+}
+
+func TestField(t *testing.T) {
+	data := []byte(`{"Name": "John", "Age": "thirty"}`)
+
+	var name jitjson.Field[string]
+	if v, err := name.Get(data, "Name"); err != nil || v != "John" {
+		t.Fatalf("expected John, got %q %v", v, err)
+	}
+	if v, err := name.Get([]byte(`{"Name": "Jane"}`), "Name"); err != nil || v != "John" {
+		t.Errorf("expected the cached John, got %q %v", v, err)
+	}
+
+	var age jitjson.Field[int]
+	if _, err := age.Get(data, "Age"); err == nil {
+		t.Error("expected type error")
+	}
+
+	var city jitjson.Field[string]
+	if v, err := city.Get(data, "City"); err != nil || v != "" {
+		t.Errorf("expected zero value for missing member, got %q %v", v, err)
+	}
+
+	name.Reset()
+	if v, _ := name.Get([]byte(`{"Name": "Jane"}`), "Name"); v != "Jane" {
+		t.Errorf("expected Reset to decode again, got %q", v)
+	}
+}
+
+func TestMemberBytes(t *testing.T) {
+	data := []byte(`{"Data": 1, "data": 2, "data": 3}`)
+	if raw, ok, err := jitjson.MemberBytes(data, "data"); err != nil || !ok || string(raw) != "3" {
+		t.Errorf("expected the last exact match, got %s, %v, %v", raw, ok, err)
+	}
+	if _, ok, err := jitjson.MemberBytes(data, "DATA"); err != nil || ok {
+		t.Errorf("expected no match, got %v, %v", ok, err)
+	}
+	if _, _, err := jitjson.MemberBytes([]byte(`[1]`), "data"); err == nil {
+		t.Error("expected an error for an array")
+	}
+}
@@ -0,0 +1,55 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Find returns the index of the first of items whose member at path equals want, and
+// the item itself, scanning the raw encoding of each item and stopping at the first
+// match, which suits looking a record up by id in a large export:
+//
+//	i, user, err := jitjson.Find(users, "id", 42)
+//
+// Path is a dot-separated list of member names, such as "owner.id", matched as by
+// FieldBytes, with numeric segments indexing arrays, such as "tags.0". Only the member at path is decoded, into a value of the type of want, and
+// compared with reflect.DeepEqual; a nil want matches a null member. Items where path is
+// missing, or holds a value of another type, do not match. Items holding only a value
+// are marshaled first. If no item matches, Find returns -1, nil, nil. An error is
+// returned if an item cannot be marshaled, is not valid JSON along path, or decoding its
+// member exceeds the Budget the item was created with.
+func Find[T any](items []*JitJSON[T], path string, want any) (int, *JitJSON[T], error) {
+	for i, item := range items {
+		data, err := item.Marshal()
+		if err != nil {
+			return -1, nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		raw, ok, err := pathBytes(data, path)
+		if err != nil {
+			return -1, nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := item.opts.charge(raw); err != nil {
+			return -1, nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		if matches(raw, want) {
+			return i, item, nil
+		}
+	}
+	return -1, nil, nil
+}
+
+// matches reports whether the raw JSON value decodes to a value equal to want.
+func matches(raw []byte, want any) bool {
+	if want == nil {
+		return string(raw) == "null"
+	}
+	got := reflect.New(reflect.TypeOf(want))
+	if err := json.Unmarshal(raw, got.Interface()); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(got.Elem().Interface(), want)
+}
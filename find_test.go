@@ -0,0 +1,60 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestFind(t *testing.T) {
+	type user struct {
+		ID    int
+		Owner struct{ Name string }
+	}
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	items := []*jitjson.JitJSON[user]{
+		jitjson.NewFromBytes[user]([]byte(`{"id": 1, "owner": {"name": "John"}}`), count),
+		jitjson.NewFromBytes[user]([]byte(`{"id": "2", "owner": null}`), count),
+		jitjson.New(user{ID: 3}, count),
+		jitjson.NewFromBytes[user]([]byte(`{"id": 4, "owner": {"name": "Jane"}}`), count),
+		jitjson.NewFromBytes[user]([]byte(`{"id": 5, "owner": {`), count),
+	}
+
+	i, item, err := jitjson.Find(items, "owner.name", "Jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 3 || item != items[3] {
+		t.Errorf("expected item 3, got %d", i)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no items to be decoded, got %d", decodes)
+	}
+
+	if i, _, err := jitjson.Find(items, "id", 3); err != nil || i != 2 {
+		t.Errorf("expected a value-only item to match, got %d, %v", i, err)
+	}
+	if i, _, err := jitjson.Find(items, "id", "2"); err != nil || i != 1 {
+		t.Errorf("expected the string id to match, got %d, %v", i, err)
+	}
+	if i, _, err := jitjson.Find(items, "owner", nil); err != nil || i != 1 {
+		t.Errorf("expected a null member to match nil, got %d, %v", i, err)
+	}
+	if i, _, err := jitjson.Find(items[:4], "id", 6); err != nil || i != -1 {
+		t.Errorf("expected no match, got %d, %v", i, err)
+	}
+	if _, _, err := jitjson.Find(items, "owner.name", "Nobody"); err == nil {
+		t.Error("expected an error for the malformed item")
+	}
+}
+
+func TestFindArrayPath(t *testing.T) {
+	items := []*jitjson.JitJSON[map[string]any]{
+		jitjson.NewFromBytes[map[string]any]([]byte(`{"tags": ["a", "b"]}`)),
+		jitjson.NewFromBytes[map[string]any]([]byte(`{"tags": ["b"]}`)),
+	}
+	if i, _, err := jitjson.Find(items, "tags.0", "b"); err != nil || i != 1 {
+		t.Errorf("expected item 1, got %d, %v", i, err)
+	}
+}
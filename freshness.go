@@ -0,0 +1,67 @@
+package jitjson
+
+import "time"
+
+// ExpireAfter makes Unmarshal discard a decoded value once d has passed since it was
+// decoded, and decode the stored encoding again, so a JitJSON[T] used as a cache entry
+// does not serve a value forever. Values stored by New or Set have no encoding to fall
+// back on and do not expire.
+func ExpireAfter(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+// expired reports whether the decoded value has outlived the ExpireAfter duration and
+// can be decoded again from the stored encoding.
+func (jit *JitJSON[T]) expired() bool {
+	if jit.opts == nil || jit.opts.ttl <= 0 || jit.data == nil {
+		return false
+	}
+	var decodedAt int64
+	if jit.ext != nil {
+		decodedAt = jit.ext.decodedAt
+	}
+	return time.Since(time.Unix(0, decodedAt)) > jit.opts.ttl
+}
+
+// SetBytesWithVersion sets JitJSON[T] to the JSON data like SetBytes, but only if version
+// is newer than the version of the data it holds, which is 0 until the first call. This
+// lets cache refreshers keep the newest payload, and keeps the decoded value when the
+// same generation is stored again. It reports whether data was stored. Refreshers
+// running concurrently must share a SyncJitJSON[T], on which the comparison and the
+// store are atomic.
+func (jit *JitJSON[T]) SetBytesWithVersion(data []byte, version uint64) (bool, error) {
+	if version <= jit.Version() && (jit.data != nil || jit.val != nil) {
+		return false, nil
+	}
+	if err := jit.SetBytes(data); err != nil {
+		return false, err
+	}
+	jit.extend().version = version
+	return true, nil
+}
+
+// Version returns the version of the data stored by the last SetBytesWithVersion.
+func (jit *JitJSON[T]) Version() uint64 {
+	if jit.ext == nil {
+		return 0
+	}
+	return jit.ext.version
+}
+
+// SetBytesWithVersion atomically sets the encoding if version is newer than the version
+// of the data held, as by JitJSON[T].SetBytesWithVersion, so concurrent refreshers keep
+// the newest payload.
+func (s *SyncJitJSON[T]) SetBytesWithVersion(data []byte, version uint64) (bool, error) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.SetBytesWithVersion(data, version)
+}
+
+// Version returns the version of the data stored by the last SetBytesWithVersion.
+func (s *SyncJitJSON[T]) Version() uint64 {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.Version()
+}
@@ -0,0 +1,82 @@
+package jitjson_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestExpireAfter(t *testing.T) {
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), jitjson.ExpireAfter(time.Millisecond), count)
+
+	jit.Unmarshal()
+	jit.Unmarshal()
+	if decodes != 1 {
+		t.Errorf("expected the cached value before expiry, got %d decodings", decodes)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if p, _ := jit.Unmarshal(); p.Name != "John" {
+		t.Errorf("expected John, got %s", p.Name)
+	}
+	if decodes != 2 {
+		t.Errorf("expected the value to be decoded again, got %d decodings", decodes)
+	}
+
+	set := jitjson.New(Person{Name: "Jane"}, jitjson.ExpireAfter(time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	if p, _ := set.Unmarshal(); p.Name != "Jane" {
+		t.Errorf("expected values without an encoding to be kept, got %q", p.Name)
+	}
+}
+
+func TestSetBytesWithVersion(t *testing.T) {
+	var jit jitjson.JitJSON[Person]
+	if ok, err := jit.SetBytesWithVersion([]byte(`{"Name":"John"}`), 2); !ok || err != nil {
+		t.Fatalf("expected first version to be stored, got %v, %v", ok, err)
+	}
+	if ok, _ := jit.SetBytesWithVersion([]byte(`{"Name":"Old"}`), 1); ok {
+		t.Error("expected older version to be ignored")
+	}
+	if ok, _ := jit.SetBytesWithVersion([]byte(`{"Name":"John"}`), 2); ok {
+		t.Error("expected same version to be ignored")
+	}
+	if ok, _ := jit.SetBytesWithVersion([]byte(`{"Name":"Jane"}`), 3); !ok {
+		t.Error("expected newer version to be stored")
+	}
+	if p, _ := jit.Unmarshal(); p.Name != "Jane" || jit.Version() != 3 {
+		t.Errorf("expected Jane at version 3, got %s at %d", p.Name, jit.Version())
+	}
+
+	limited := jitjson.NewFromBytes[Person](nil, jitjson.WithMaxBytes(4))
+	if ok, err := limited.SetBytesWithVersion([]byte(`{"Name":"John"}`), 1); ok || err == nil {
+		t.Error("expected data over the limit to be rejected")
+	}
+	if limited.Version() != 0 {
+		t.Errorf("expected version to be unchanged, got %d", limited.Version())
+	}
+}
+
+func TestSyncSetBytesWithVersion(t *testing.T) {
+	var shared jitjson.SyncJitJSON[Person]
+	var wg sync.WaitGroup
+	for v := uint64(1); v <= 20; v++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := []byte(`{"Age":` + strconv.FormatUint(v, 10) + `}`)
+			if _, err := shared.SetBytesWithVersion(data, v); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if p, _ := shared.Unmarshal(); p.Age != 20 || shared.Version() != 20 {
+		t.Errorf("expected the newest version to win, got %d at %d", p.Age, shared.Version())
+	}
+}
@@ -0,0 +1,55 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitjsontest"
+)
+
+func FuzzAnyJitJSON(f *testing.F) {
+	for _, seed := range jitjsontest.FuzzCorpus() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := jitjsontest.CheckAnyJitJSON(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzJitJSON(f *testing.F) {
+	for _, seed := range jitjsontest.FuzzCorpus() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := jitjsontest.CheckJitJSON[interface{}](data); err != nil {
+			t.Fatal(err)
+		}
+		if err := jitjsontest.CheckJitJSON[map[string]interface{}](data, jitjson.WithPreserveNumbers(), jitjson.WithCanonical()); err != nil {
+			t.Fatal(err)
+		}
+		if jitjson.ScanValid(data) != json.Valid(data) {
+			t.Fatalf("ScanValid(%q) disagrees with json.Valid", data)
+		}
+		if _, err := jitjson.Canonicalize(data); (err == nil) != json.Valid(data) {
+			t.Fatalf("Canonicalize(%q): %v", data, err)
+		}
+	})
+}
+
+func TestFuzzCorpusRegressions(t *testing.T) {
+	for _, input := range []string{"\"\x11\"", "1\f", `"\x"`, "A", `tru`} {
+		var a jitjson.AnyJitJSON
+		if err := a.UnmarshalJSON([]byte(input)); err == nil {
+			t.Errorf("expected %q to be rejected, got %v", input, a.Type())
+		}
+		if _, err := jitjson.Canonicalize([]byte(input)); err == nil {
+			t.Errorf("expected Canonicalize to reject %q", input)
+		}
+	}
+	if s := jitjson.ValueType(42).String(); s != "ValueType(42)" {
+		t.Errorf("expected ValueType(42), got %s", s)
+	}
+}
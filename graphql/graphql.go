@@ -0,0 +1,126 @@
+// Package graphql provides a GraphQL response wrapper whose data and errors are held
+// lazily, so gateways can inspect errors before forwarding data verbatim.
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Response is a GraphQL response. Data is decoded into T only when requested, and
+// Errors and Extensions are dynamic values parsed only as far as they are accessed.
+// A nil Data is encoded as "data": null, which the GraphQL specification requires when
+// execution fails.
+type Response[T any] struct {
+	Data       *jitjson.JitJSON[T] `json:"data"`
+	Errors     *jitjson.AnyJitJSON `json:"errors,omitempty"`
+	Extensions *jitjson.AnyJitJSON `json:"extensions,omitempty"`
+}
+
+// Error is a GraphQL error as described by the GraphQL specification.
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// Location is a position in the GraphQL request document.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Parse reads a GraphQL response without decoding its data.
+func Parse[T any](data []byte) (*Response[T], error) {
+	var resp Response[T]
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// HasErrors reports whether the response carries a non-empty errors list. Only the
+// top level of the list is scanned.
+func (r *Response[T]) HasErrors() bool {
+	if r.Errors == nil {
+		return false
+	}
+	errs, ok := r.Errors.AsArray()
+	return ok && len(errs) > 0
+}
+
+// ErrorList decodes the errors of the response.
+func (r *Response[T]) ErrorList() ([]Error, error) {
+	if r.Errors == nil || r.Errors.IsNull() {
+		return nil, nil
+	}
+	data, err := r.Errors.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var errs []Error
+	if err := json.Unmarshal(data, &errs); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// DataAt returns the part of the response data at path, such as the path of an error,
+// without decoding the rest of the data. Path elements are field names (string) or
+// list indexes (int, or float64 as decoded from an error path).
+func (r *Response[T]) DataAt(path ...any) (*jitjson.AnyJitJSON, error) {
+	if r.Data == nil {
+		return nil, fmt.Errorf("graphql: response has no data")
+	}
+	data, err := r.Data.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	node, err := jitjson.NewAny(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, elem := range path {
+		switch key := elem.(type) {
+		case string:
+			obj, ok := node.AsObject()
+			if !ok {
+				return nil, fmt.Errorf("graphql: path element %d: %q is not in an object", i, key)
+			}
+			if node, ok = obj[key]; !ok {
+				return nil, fmt.Errorf("graphql: path element %d: field %q not found", i, key)
+			}
+		case int:
+			if node, err = index(node, key, i); err != nil {
+				return nil, err
+			}
+		case float64:
+			if node, err = index(node, int(key), i); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("graphql: path element %d: unsupported type %T", i, elem)
+		}
+	}
+	return node, nil
+}
+
+// index returns element idx of the list node, where i is the position in the path.
+func index(node *jitjson.AnyJitJSON, idx, i int) (*jitjson.AnyJitJSON, error) {
+	arr, ok := node.AsArray()
+	if !ok {
+		return nil, fmt.Errorf("graphql: path element %d: index %d is not in a list", i, idx)
+	}
+	if idx < 0 || idx >= len(arr) {
+		return nil, fmt.Errorf("graphql: path element %d: index %d out of range", i, idx)
+	}
+	return arr[idx], nil
+}
@@ -0,0 +1,96 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson/graphql"
+)
+
+type query struct {
+	User struct {
+		Name    string
+		Friends []struct {
+			Name *string
+		}
+	}
+}
+
+func TestResponse(t *testing.T) {
+	data := []byte(`{
+		"data": {"User": {"Name": "John", "Friends": [{"Name": "Jane"}, {"Name": null}]}},
+		"errors": [{"message": "not allowed", "path": ["User", "Friends", 1, "Name"], "locations": [{"line": 3, "column": 5}]}]
+	}`)
+
+	resp, err := graphql.Parse[query](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.HasErrors() {
+		t.Fatal("expected errors")
+	}
+
+	errs, err := resp.ErrorList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Message != "not allowed" || errs[0].Locations[0].Line != 3 {
+		t.Fatalf("unexpected errors %+v", errs)
+	}
+
+	// inspect the partial data around the failing field
+	node, err := resp.DataAt(errs[0].Path[:len(errs[0].Path)-1]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, ok := node.AsObject()
+	if !ok || !obj["Name"].IsNull() {
+		t.Error("expected failing field to be null")
+	}
+
+	if _, err := resp.DataAt("User", "Missing"); err == nil {
+		t.Error("expected error for missing field")
+	}
+	if _, err := resp.DataAt("User", "Friends", 5); err == nil {
+		t.Error("expected error for out of range index")
+	}
+
+	q, err := resp.Data.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.User.Name != "John" || len(q.User.Friends) != 2 {
+		t.Error("values do not match")
+	}
+}
+
+func TestResponse_NoErrors(t *testing.T) {
+	resp, err := graphql.Parse[query]([]byte(`{"data": {"User": {"Name": "John"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.HasErrors() {
+		t.Error("expected no errors")
+	}
+	if errs, err := resp.ErrorList(); err != nil || errs != nil {
+		t.Errorf("expected no errors, got %v, %v", errs, err)
+	}
+}
+
+func TestResponse_NullData(t *testing.T) {
+	input := `{"data":null,"errors":[{"message":"boom"}]}`
+	resp, err := graphql.Parse[query]([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data != nil {
+		t.Error("expected no data")
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != input {
+		t.Errorf("expected %s, got %s", input, out)
+	}
+}
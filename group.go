@@ -0,0 +1,146 @@
+package jitjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// KeyKind is the kind of JSON value a Key was extracted from.
+type KeyKind uint8
+
+// Kinds of Key. KeyMissing, the zero KeyKind, is the kind of items without the key.
+const (
+	KeyMissing KeyKind = iota
+	KeyNull
+	KeyBool
+	KeyNumber
+	KeyString
+)
+
+// Key is the value of the key member of an item, as extracted by GroupBy and NewIndex.
+// Keys of different kinds are distinct, so 1 and "1", or null and "null", do not
+// share a group, and the zero Key, of items missing the member, is distinct from every
+// value, including "".
+type Key struct {
+	// Kind is the kind of the value.
+	Kind KeyKind
+	// Text is the unquoted string, the number in the shortest form written by
+	// WithCanonical, so 1.0 and 1 are the same key, "true" or "false", or empty for
+	// null and missing keys.
+	Text string
+}
+
+// StringKey returns the Key of the JSON string s.
+func StringKey(s string) Key {
+	return Key{Kind: KeyString, Text: s}
+}
+
+// KeyOf returns the Key of the JSON encoding of v, which must be a string, number,
+// boolean or nil. A Key is returned as is.
+func KeyOf(v any) (Key, error) {
+	if k, ok := v.(Key); ok {
+		return k, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Key{}, err
+	}
+	return scalarKey(data)
+}
+
+// String returns the key as JSON, or <missing> for the zero Key.
+func (k Key) String() string {
+	switch k.Kind {
+	case KeyMissing:
+		return "<missing>"
+	case KeyNull:
+		return "null"
+	case KeyString:
+		return strconv.Quote(k.Text)
+	}
+	return k.Text
+}
+
+// GroupBy partitions items by the value of the member at keyPath, extracting only the
+// key from each item's raw encoding, so the items themselves stay undecoded:
+//
+//	byTenant, err := jitjson.GroupBy(events, "tenant.id")
+//	acme := byTenant[jitjson.StringKey("acme")]
+//
+// KeyPath is matched as by Find. Items are keyed by the kind and value of the member, as
+// described by Key, and items where keyPath is missing are grouped under the zero Key.
+// Within a group, items keep their order. Items holding only a value are marshaled
+// first. An error is returned, naming the item, if an item cannot be marshaled, is not
+// valid JSON along keyPath, or holds an array or object at keyPath.
+func GroupBy[T any](items []*JitJSON[T], keyPath string) (map[Key][]*JitJSON[T], error) {
+	groups := map[Key][]*JitJSON[T]{}
+	for i, item := range items {
+		key, err := itemKey(item, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	return groups, nil
+}
+
+// itemKey returns the Key of the member at path of the encoding of item.
+func itemKey[T any](item *JitJSON[T], path string) (Key, error) {
+	data, err := item.Marshal()
+	if err != nil {
+		return Key{}, err
+	}
+	raw, ok, err := pathBytes(data, path)
+	if err != nil || !ok {
+		return Key{}, err
+	}
+	key, err := scalarKey(raw)
+	if err != nil {
+		return Key{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// scalarKey returns the Key of the scalar JSON value raw.
+func scalarKey(raw []byte) (Key, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return Key{}, errors.New("key is not valid json")
+	}
+	switch string(raw) {
+	case "null":
+		return Key{Kind: KeyNull}, nil
+	case "true", "false":
+		return Key{Kind: KeyBool, Text: string(raw)}, nil
+	}
+	switch raw[0] {
+	case '"':
+		s, err := unquote(raw)
+		return StringKey(s), err
+	case '[', '{':
+		return Key{}, errors.New("key is not a scalar")
+	}
+	d, ok := decimalOf(raw)
+	if !ok {
+		return Key{}, errors.New("key is not valid json")
+	}
+	return Key{Kind: KeyNumber, Text: string(appendDecimal(nil, d))}, nil
+}
+
+// keyString returns the scalar JSON value raw as a key, unquoting strings and using the
+// literal text of other scalars.
+func keyString(raw []byte, path string) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 {
+		switch raw[0] {
+		case '"':
+			return unquote(raw)
+		case '[', '{':
+			return "", fmt.Errorf("%s: key is not a scalar", path)
+		}
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,96 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestGroupBy(t *testing.T) {
+	type event struct {
+		Tenant any
+		Seq    int
+	}
+	var decodes int
+	hook := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	items := []*jitjson.JitJSON[event]{
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": "a", "seq": 1}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": "b", "seq": 2}`), hook),
+		jitjson.New(event{Tenant: "a", Seq: 3}, hook),
+		jitjson.NewFromBytes[event]([]byte(`{"seq": 4}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": 7, "seq": 5}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": "", "seq": 6}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": "7", "seq": 7}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": 7.0, "seq": 8}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": null, "seq": 9}`), hook),
+		jitjson.NewFromBytes[event]([]byte(`{"tenant": "null", "seq": 10}`), hook),
+	}
+
+	groups, err := jitjson.GroupBy(items, "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no items to be decoded, got %d decodes", decodes)
+	}
+
+	seven, _ := jitjson.KeyOf(7)
+	tests := []struct {
+		key  jitjson.Key
+		want []int
+	}{
+		{jitjson.StringKey("a"), []int{0, 2}},
+		{jitjson.StringKey("b"), []int{1}},
+		{jitjson.Key{}, []int{3}},
+		{jitjson.StringKey(""), []int{5}},
+		{seven, []int{4, 7}},
+		{jitjson.StringKey("7"), []int{6}},
+		{jitjson.Key{Kind: jitjson.KeyNull}, []int{8}},
+		{jitjson.StringKey("null"), []int{9}},
+	}
+	if len(groups) != len(tests) {
+		t.Errorf("expected %d groups, got %d", len(tests), len(groups))
+	}
+	for _, tc := range tests {
+		group := groups[tc.key]
+		if len(group) != len(tc.want) {
+			t.Errorf("%v: expected items %v, got %d items", tc.key, tc.want, len(group))
+			continue
+		}
+		for i, item := range group {
+			if item != items[tc.want[i]] {
+				t.Errorf("%v: expected items %v in order", tc.key, tc.want)
+			}
+		}
+	}
+
+	items = append(items, jitjson.NewFromBytes[event]([]byte(`{"tenant": {"id": "a"}}`)))
+	if _, err := jitjson.GroupBy(items, "tenant"); err == nil {
+		t.Error("expected an error for an object key")
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	tests := []struct {
+		v    any
+		want jitjson.Key
+	}{
+		{"x", jitjson.StringKey("x")},
+		{42, jitjson.Key{Kind: jitjson.KeyNumber, Text: "42"}},
+		{2.5, jitjson.Key{Kind: jitjson.KeyNumber, Text: "2.5"}},
+		{true, jitjson.Key{Kind: jitjson.KeyBool, Text: "true"}},
+		{nil, jitjson.Key{Kind: jitjson.KeyNull}},
+		{jitjson.Key{}, jitjson.Key{}},
+	}
+	for _, tc := range tests {
+		if k, err := jitjson.KeyOf(tc.v); err != nil || k != tc.want {
+			t.Errorf("KeyOf(%v): expected %v, got %v, %v", tc.v, tc.want, k, err)
+		}
+	}
+	if _, err := jitjson.KeyOf([]int{1}); err == nil {
+		t.Error("expected an error for a non-scalar")
+	}
+	if s := jitjson.StringKey("a").String(); s != `"a"` {
+		t.Errorf("expected a quoted string, got %s", s)
+	}
+}
@@ -0,0 +1,141 @@
+package jitjson
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// benchTime is the minimum duration each harness case is run for.
+const benchTime = 200 * time.Millisecond
+
+// BenchmarkResult holds the measurements of a single Benchmark case.
+type BenchmarkResult struct {
+	Parser          string  `json:"parser"`
+	Lazy            bool    `json:"lazy"`
+	ParsePercentage float64 `json:"parse_percentage"`
+	Iterations      int     `json:"iterations"`
+	NsPerOp         int64   `json:"ns_per_op"`
+	BytesPerOp      uint64  `json:"bytes_per_op"`
+	AllocsPerOp     uint64  `json:"allocs_per_op"`
+}
+
+func (r BenchmarkResult) String() string {
+	mode := "eager"
+	if r.Lazy {
+		mode = "lazy"
+	}
+	return fmt.Sprintf("%s/%s/%.2f\t%d\t%d ns/op\t%d B/op\t%d allocs/op",
+		r.Parser, mode, r.ParsePercentage, r.Iterations, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+}
+
+// Benchmark measures decoding data, a JSON array of T, with each of the named parsers.
+// For every parser it reports one eager case, decoding the whole array into []T, and
+// one lazy case per parse percentage, where the array is split into []*JitJSON[T] and
+// only that fraction of elements is decoded. This lets applications pick the best
+// backend for their real payloads at startup or in CI. If parsers is empty, all
+// registered parsers are measured. If no percentages are given, 0, 0.3 and 1 are used.
+func Benchmark[T any](data []byte, parsers []string, percentages ...float64) ([]BenchmarkResult, error) {
+	if len(parsers) == 0 {
+		parsers = Parsers()
+	}
+	if len(percentages) == 0 {
+		percentages = []float64{0, 0.3, 1}
+	}
+	for _, p := range percentages {
+		if p < 0 || p > 1 {
+			return nil, fmt.Errorf("parse percentage %v must be between 0 and 1", p)
+		}
+	}
+	if _, ok := splitArray(data); !ok {
+		return nil, errors.New("benchmark data must be a JSON array")
+	}
+
+	var results []BenchmarkResult
+	for _, name := range parsers {
+		parser, ok := LookupParser(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown parser %q", name)
+		}
+
+		result, err := measure(func() error {
+			var arr []T
+			return parser.Unmarshal(data, &arr)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result.Parser = name
+		result.ParsePercentage = 1
+		results = append(results, result)
+
+		for _, p := range percentages {
+			result, err := measure(func() error {
+				elems, _ := splitArray(data)
+				shouldParse := parseIterator(p)
+				for _, elem := range elems {
+					jit := NewFromBytes[T](elem, WithParser(name))
+					if shouldParse() {
+						if _, err := jit.Unmarshal(); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			result.Parser = name
+			result.Lazy = true
+			result.ParsePercentage = p
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// measure runs op repeatedly for at least benchTime and reports per-op costs.
+func measure(op func() error) (BenchmarkResult, error) {
+	if err := op(); err != nil {
+		return BenchmarkResult{}, err
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var n int
+	start := time.Now()
+	for n == 0 || time.Since(start) < benchTime {
+		if err := op(); err != nil {
+			return BenchmarkResult{}, err
+		}
+		n++
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return BenchmarkResult{
+		Iterations:  n,
+		NsPerOp:     elapsed.Nanoseconds() / int64(n),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(n),
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(n),
+	}, nil
+}
+
+// parseIterator returns a function reporting whether the next element should be
+// parsed, so that the given fraction of elements is parsed at an even spacing.
+func parseIterator(parsePercent float64) func() bool {
+	var record float64
+	return func() bool {
+		record += parsePercent
+		if record >= 1 {
+			record = record - 1
+			return true
+		}
+		return false
+	}
+}
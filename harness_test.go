@@ -0,0 +1,47 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestBenchmark(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness in short mode")
+	}
+	registerParser(t, "harness", &countingParser{})
+
+	parsers := []string{jitjson.DefaultParser, "harness"}
+	results, err := jitjson.Benchmark[Object](smallData, parsers, 0, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one eager case and one lazy case per percentage for each parser
+	if len(results) != 3*len(parsers) {
+		t.Fatalf("expected %d results, got %d", 3*len(parsers), len(results))
+	}
+	for i, r := range results {
+		if r.Parser != parsers[i/3] || r.Iterations == 0 || r.NsPerOp <= 0 {
+			t.Errorf("unexpected result %v", r)
+		}
+		switch i % 3 {
+		case 0:
+			if r.Lazy {
+				t.Error("expected an eager case first")
+			}
+		case 1, 2:
+			if want := float64(i%3-1) / 2; !r.Lazy || r.ParsePercentage != want {
+				t.Errorf("expected a lazy case parsing %v, got %v", want, r)
+			}
+		}
+	}
+
+	if _, err := jitjson.Benchmark[Object](smallData, []string{"missing"}); err == nil {
+		t.Error("expected error for unknown parser")
+	}
+	if _, err := jitjson.Benchmark[Object]([]byte(`{}`), nil); err == nil {
+		t.Error("expected error for non-array data")
+	}
+}
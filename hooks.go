@@ -0,0 +1,231 @@
+package jitjson
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Operation names reported in ParseEvent.Op.
+const (
+	OpMarshal   = "marshal"
+	OpUnmarshal = "unmarshal"
+)
+
+// ParseEvent describes an encoding or decoding performed by Marshal or Unmarshal.
+type ParseEvent struct {
+	// Context is the context given to MarshalContext or UnmarshalContext, or
+	// context.Background for Marshal, Unmarshal and other calls that take none, so hooks
+	// can attach the parse to the trace or request it belongs to.
+	Context context.Context
+	// Op is OpMarshal or OpUnmarshal.
+	Op string
+	// Type is the name of the type parameter T, such as "main.Order".
+	Type string
+	// Parser is the name of the parser that ran, as registered with RegisterParser.
+	Parser string
+	// Size is the length of the encoded payload in bytes.
+	Size int
+	// Duration is the time spent in the parser.
+	Duration time.Duration
+	// Err is the error returned by the parser, if any.
+	Err error
+	// Callers holds the program counters of the call stack of a decoding, as returned by
+	// runtime.Callers, while any hook is registered by OnParse. It is nil otherwise.
+	Callers []uintptr
+}
+
+// CallSite returns the innermost frame of Callers outside package jitjson, which is
+// where the decoding was triggered, reporting false if Callers was not captured.
+func (e ParseEvent) CallSite() (runtime.Frame, bool) {
+	frames := runtime.CallersFrames(e.Callers)
+	for {
+		f, more := frames.Next()
+		if f.PC != 0 && !strings.HasPrefix(f.Function, pkgPath+".") {
+			return f, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// pkgPath is the import path of package jitjson.
+var pkgPath = reflect.TypeFor[options]().PkgPath()
+
+// maxCallers is the maximum depth of the call stack captured for OnParse.
+const maxCallers = 32
+
+// Hook is called with a ParseEvent each time a deferred parse actually executes. Cache
+// hits do not call hooks. Hooks run synchronously on the calling goroutine, so they
+// should return quickly.
+type Hook func(ParseEvent)
+
+// hookEntry wraps a registered Hook so it can be removed by identity.
+type hookEntry struct {
+	hook Hook
+}
+
+// globalHooks holds the hooks registered by OnMarshal and OnUnmarshal. The slices are
+// replaced rather than modified, so parses read them without locking.
+var globalHooks struct {
+	mu        sync.Mutex
+	marshal   atomic.Pointer[[]*hookEntry]
+	unmarshal atomic.Pointer[[]*hookEntry]
+	parse     atomic.Pointer[[]*hookEntry]
+}
+
+// OnMarshal registers h to be called for every encoding performed by Marshal in any
+// JitJSON[T]. The returned function unregisters h.
+func OnMarshal(h Hook) (remove func()) {
+	return addHook(&globalHooks.marshal, h)
+}
+
+// OnUnmarshal registers h to be called for every decoding performed by Unmarshal in
+// any JitJSON[T], such as to log when cold parses fire on latency-sensitive paths:
+//
+//	jitjson.OnUnmarshal(func(e jitjson.ParseEvent) {
+//		if e.Duration > time.Millisecond {
+//			log.Printf("slow lazy parse of %s (%d bytes): %v", e.Type, e.Size, e.Duration)
+//		}
+//	})
+//
+// The returned function unregisters h.
+func OnUnmarshal(h Hook) (remove func()) {
+	return addHook(&globalHooks.unmarshal, h)
+}
+
+// OnParse registers h to be called for every decoding performed by Unmarshal in any
+// JitJSON[T], like OnUnmarshal, with the call stack of the decoding captured in
+// ParseEvent.Callers, so audits can record which payload types are decoded where:
+//
+//	jitjson.OnParse(func(e jitjson.ParseEvent) {
+//		if f, ok := e.CallSite(); ok {
+//			audit.Record(e.Type, e.Size, f.Function, f.File, f.Line)
+//		}
+//	})
+//
+// Capturing the call stack adds to the cost of every decoding while any hook is
+// registered by OnParse, so hooks that do not need it should use OnUnmarshal. The
+// returned function unregisters h.
+func OnParse(h Hook) (remove func()) {
+	return addHook(&globalHooks.parse, h)
+}
+
+// addHook registers h in hooks, returning a function that unregisters it.
+func addHook(hooks *atomic.Pointer[[]*hookEntry], h Hook) func() {
+	e := &hookEntry{h}
+	globalHooks.mu.Lock()
+	defer globalHooks.mu.Unlock()
+
+	var list []*hookEntry
+	if old := hooks.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, e)
+	hooks.Store(&list)
+
+	return func() {
+		globalHooks.mu.Lock()
+		defer globalHooks.mu.Unlock()
+
+		var kept []*hookEntry
+		for _, other := range *hooks.Load() {
+			if other != e {
+				kept = append(kept, other)
+			}
+		}
+		if len(kept) == 0 {
+			hooks.Store(nil)
+			return
+		}
+		hooks.Store(&kept)
+	}
+}
+
+// WithOnMarshal makes JitJSON[T] call h for each encoding performed by its Marshal, in
+// addition to any hooks registered with OnMarshal.
+func WithOnMarshal(h Hook) Option {
+	return func(o *options) {
+		o.onMarshal = append(o.onMarshal, h)
+	}
+}
+
+// WithOnUnmarshal makes JitJSON[T] call h for each decoding performed by its Unmarshal,
+// in addition to any hooks registered with OnUnmarshal.
+func WithOnUnmarshal(h Hook) Option {
+	return func(o *options) {
+		o.onUnmarshal = append(o.onUnmarshal, h)
+	}
+}
+
+// hooks returns the global, OnParse and per-instance hooks for op.
+func (o *options) hooks(op string) (global, parse *[]*hookEntry, local []Hook) {
+	global, parse = globalHooks.unmarshal.Load(), globalHooks.parse.Load()
+	if op == OpMarshal {
+		global, parse = globalHooks.marshal.Load(), nil
+	}
+	if o != nil {
+		local = o.onUnmarshal
+		if op == OpMarshal {
+			local = o.onMarshal
+		}
+	}
+	return global, parse, local
+}
+
+// observed reports whether any hook is called for op.
+func (o *options) observed(op string) bool {
+	global, parse, local := o.hooks(op)
+	return global != nil || parse != nil || len(local) > 0
+}
+
+// notify calls the per-instance and global hooks for op on the value v, if there are
+// any. The event is only built when a hook will receive it.
+func (o *options) notify(ctx context.Context, op string, v any, size int, d time.Duration, err error) {
+	global, parse, local := o.hooks(op)
+	if global == nil && parse == nil && len(local) == 0 {
+		return
+	}
+
+	e := ParseEvent{
+		Context:  ctx,
+		Op:       op,
+		Type:     typeName(v),
+		Parser:   o.codecName(),
+		Size:     size,
+		Duration: d,
+		Err:      err,
+	}
+	if parse != nil {
+		// Skip runtime.Callers and notify.
+		e.Callers = make([]uintptr, maxCallers)
+		e.Callers = e.Callers[:runtime.Callers(2, e.Callers)]
+	}
+	for _, h := range local {
+		h(e)
+	}
+	for _, hooks := range []*[]*hookEntry{global, parse} {
+		if hooks != nil {
+			for _, entry := range *hooks {
+				entry.hook(e)
+			}
+		}
+	}
+}
+
+// typeName returns the name of the type v points to.
+func typeName(v any) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return "<nil>"
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.String()
+}
@@ -0,0 +1,106 @@
+package jitjson_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestHooks(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+
+	var global, local []jitjson.ParseEvent
+	remove := jitjson.OnUnmarshal(func(e jitjson.ParseEvent) { global = append(global, e) })
+	jit := jitjson.NewFromBytes[Person](jsonData,
+		jitjson.WithOnUnmarshal(func(e jitjson.ParseEvent) { local = append(local, e) }))
+	for i := 0; i < 2; i++ {
+		if _, err := jit.Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(global) != 1 || len(local) != 1 {
+		t.Fatalf("expected one event per hook, got %d global and %d local", len(global), len(local))
+	}
+	e := local[0]
+	if e.Op != jitjson.OpUnmarshal || e.Type != "jitjson_test.Person" || e.Parser != jitjson.DefaultParser || e.Size != len(jsonData) || e.Err != nil {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	remove()
+	jitjson.NewFromBytes[Person](jsonData).Unmarshal()
+	if len(global) != 1 {
+		t.Errorf("expected no events after removal, got %d", len(global))
+	}
+
+	var marshals []jitjson.ParseEvent
+	defer jitjson.OnMarshal(func(e jitjson.ParseEvent) { marshals = append(marshals, e) })()
+	data, err := jitjson.New([]int{1, 2}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marshals) != 1 || marshals[0].Type != "[]int" || marshals[0].Size != len(data) {
+		t.Errorf("unexpected marshal events %+v", marshals)
+	}
+}
+
+func TestHooksContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+
+	var events []jitjson.ParseEvent
+	hook := func(e jitjson.ParseEvent) { events = append(events, e) }
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`),
+		jitjson.WithOnMarshal(hook), jitjson.WithOnUnmarshal(hook))
+	if _, err := jit.UnmarshalContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	jit.Set(Person{Name: "Jane"})
+	if _, err := jit.MarshalContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jit.Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	for _, e := range events {
+		if e.Context.Value(key{}) != "request" {
+			t.Errorf("expected the caller's context for %s, got %v", e.Op, e.Context)
+		}
+	}
+
+	bad := jitjson.NewFromBytes[Person]([]byte(`[1]`), jitjson.WithOnUnmarshal(hook))
+	if _, err := bad.Unmarshal(); err == nil {
+		t.Fatal("expected error")
+	}
+	if e := events[len(events)-1]; e.Context != context.Background() || e.Err == nil {
+		t.Errorf("expected the background context and an error, got %+v", e)
+	}
+}
+
+func TestOnParse(t *testing.T) {
+	var events []jitjson.ParseEvent
+	remove := jitjson.OnParse(func(e jitjson.ParseEvent) { events = append(events, e) })
+	defer remove()
+
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`))
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jitjson.New(Person{}).Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != "jitjson_test.Person" {
+		t.Fatalf("expected one decoding event, got %+v", events)
+	}
+	f, ok := events[0].CallSite()
+	if !ok || !strings.HasSuffix(f.Function, ".TestOnParse") || !strings.HasSuffix(f.File, "hooks_test.go") {
+		t.Errorf("expected the call site in the test, got %+v, %v", f, ok)
+	}
+	if _, ok := (jitjson.ParseEvent{}).CallSite(); ok {
+		t.Error("expected no call site without callers")
+	}
+}
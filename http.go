@@ -0,0 +1,195 @@
+package jitjson
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxRequestBytes is the largest request body BindRequest reads, after any gzip
+// decompression, unless WithMaxRequestBytes or Config.MaxRequestBytes sets another limit.
+const DefaultMaxRequestBytes = 32 << 20
+
+// WithMaxRequestBytes limits the request bodies read by BindRequest to n bytes, after any
+// gzip decompression, overriding Config.MaxRequestBytes. Requests exceeding the limit
+// fail with http.StatusRequestEntityTooLarge.
+func WithMaxRequestBytes(n int64) Option {
+	return func(o *options) {
+		o.maxRequestBytes = n
+	}
+}
+
+// requestLimit returns the largest request body BindRequest reads with the options, or
+// with the defaults set by Configure for nil options.
+func (o *options) requestLimit() int64 {
+	if o == nil {
+		o = defaults.Load()
+	}
+	if o != nil && o.maxRequestBytes > 0 {
+		return o.maxRequestBytes
+	}
+	return DefaultMaxRequestBytes
+}
+
+// RequestError is returned by BindRequest and carries the HTTP status code that best
+// describes the failure, so handlers can reply with http.Error(w, err.Error(), err.Status).
+type RequestError struct {
+	Status int
+	Err    error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// BindRequest reads the JSON body of r into a JitJSON[T] without decoding it, so
+// handlers can defer decoding of request bodies they may never fully need. The
+// Content-Type must be application/json (or a +json type) if set, gzip encoded
+// bodies are decompressed, and bodies larger than the limit set by WithMaxRequestBytes
+// are rejected.
+// The body is checked with ScanValid so malformed requests fail at bind time, and against
+// the limits set by WithMaxBytes, WithMaxDepth and WithValidUTF8, as UnmarshalJSON checks
+// it. A Budget carried by the request context is attached to the value.
+func BindRequest[T any](r *http.Request, opts ...Option) (*JitJSON[T], error) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return nil, &RequestError{
+				Status: http.StatusUnsupportedMediaType,
+				Err:    fmt.Errorf("unsupported content type %q", ct),
+			}
+		}
+	}
+	if r.Body == nil {
+		return nil, &RequestError{Status: http.StatusBadRequest, Err: errors.New("missing request body")}
+	}
+
+	var body io.Reader = r.Body
+	switch enc := strings.ToLower(r.Header.Get("Content-Encoding")); enc {
+	case "", "identity":
+	case "gzip":
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, &RequestError{Status: http.StatusBadRequest, Err: fmt.Errorf("invalid gzip body: %w", err)}
+		}
+		defer zr.Close()
+		body = zr
+	default:
+		return nil, &RequestError{
+			Status: http.StatusUnsupportedMediaType,
+			Err:    fmt.Errorf("unsupported content encoding %q", enc),
+		}
+	}
+
+	o := newOptions(withContextBudget(r.Context(), opts))
+	limit := o.requestLimit()
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, &RequestError{Status: http.StatusBadRequest, Err: fmt.Errorf("reading request body: %w", err)}
+	}
+	if int64(len(data)) > limit {
+		return nil, &RequestError{
+			Status: http.StatusRequestEntityTooLarge,
+			Err:    &LimitError{Err: fmt.Errorf("%w: request body exceeds %d bytes", ErrTooLarge, limit)},
+		}
+	}
+	if data, err = o.accept(data); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		return nil, &RequestError{Status: status, Err: err}
+	}
+	if !ScanValid(data) {
+		return nil, &RequestError{Status: http.StatusBadRequest, Err: errors.New("invalid json")}
+	}
+
+	return newFromBytes[T](data, o), nil
+}
+
+// WriteJSON writes the JSON encoding of v to w with an application/json Content-Type.
+// For JitJSON[T] and AnyJitJSON values holding bytes, those bytes are written as-is
+// without a decode/encode round trip.
+func WriteJSON(w http.ResponseWriter, v json.Marshaler) error {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = []byte("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(data)
+	return err
+}
+
+// StatusError is returned by DoJSON and GetJSON when the server replies with a
+// non-2xx status code. Body holds the start of the response body for diagnostics.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %s", e.Status)
+}
+
+// maxStatusErrorBody is the number of response body bytes kept by StatusError.
+const maxStatusErrorBody = 1 << 10
+
+// DoJSON sends req with client and reads the response body into a JitJSON[T] without
+// decoding it, for API aggregators that forward most payloads untouched. Non-2xx
+// responses are returned as a *StatusError. If client is nil, http.DefaultClient is used.
+// The body is checked against the limits set by WithMaxBytes, WithMaxDepth and
+// WithValidUTF8, as UnmarshalJSON checks it. A Budget carried by the request context is
+// attached to the value.
+func DoJSON[T any](client *http.Client, req *http.Request, opts ...Option) (*JitJSON[T], error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxStatusErrorBody))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	o := newOptions(withContextBudget(req.Context(), opts))
+	if data, err = o.accept(data); err != nil {
+		return nil, err
+	}
+	return newFromBytes[T](data, o), nil
+}
+
+// GetJSON performs a GET request for url with client and returns the response body as
+// a JitJSON[T] without decoding it. See DoJSON for details.
+func GetJSON[T any](ctx context.Context, client *http.Client, url string, opts ...Option) (*JitJSON[T], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return DoJSON[T](client, req, opts...)
+}
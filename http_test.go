@@ -0,0 +1,172 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestBindRequest(t *testing.T) {
+	jsonData := `{"Name":"John","Age":30,"City":"New York"}`
+
+	t.Run("Plain body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(jsonData))
+		r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		jit, err := jitjson.BindRequest[Person](r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" {
+			t.Error("values do not match")
+		}
+	})
+
+	t.Run("Gzip body", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(jsonData))
+		zw.Close()
+
+		r := httptest.NewRequest(http.MethodPost, "/", &buf)
+		r.Header.Set("Content-Encoding", "gzip")
+
+		jit, err := jitjson.BindRequest[Person](r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := jit.Marshal()
+		if string(data) != jsonData {
+			t.Errorf("expected %s, got %s", jsonData, data)
+		}
+	})
+
+	errorCases := []struct {
+		name   string
+		body   string
+		header map[string]string
+		status int
+	}{
+		{"Wrong content type", jsonData, map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"Unknown encoding", jsonData, map[string]string{"Content-Encoding": "br"}, http.StatusUnsupportedMediaType},
+		{"Invalid gzip", jsonData, map[string]string{"Content-Encoding": "gzip"}, http.StatusBadRequest},
+		{"Invalid json", `{"Name":`, nil, http.StatusBadRequest},
+		{"Too large", `"` + strings.Repeat("a", 64) + `"`, nil, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+
+			_, err := jitjson.BindRequest[Person](r, jitjson.WithMaxRequestBytes(64))
+			var reqErr *jitjson.RequestError
+			if !errors.As(err, &reqErr) {
+				t.Fatalf("expected RequestError, got %v", err)
+			}
+			if reqErr.Status != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, reqErr.Status)
+			}
+			if tc.status == http.StatusRequestEntityTooLarge && !jitjson.IsLimitError(err) {
+				t.Errorf("expected a limit error, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Body limits", func(t *testing.T) {
+		limits := []struct {
+			name   string
+			body   string
+			opt    jitjson.Option
+			status int
+		}{
+			{"Max bytes", jsonData, jitjson.WithMaxBytes(16), http.StatusRequestEntityTooLarge},
+			{"Max depth", `[[[1]]]`, jitjson.WithMaxDepth(2), http.StatusBadRequest},
+			{"Valid UTF-8", "\"\xff\"", jitjson.WithValidUTF8(), http.StatusBadRequest},
+		}
+		for _, tc := range limits {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			var reqErr *jitjson.RequestError
+			if _, err := jitjson.BindRequest[any](r, tc.opt); !errors.As(err, &reqErr) || reqErr.Status != tc.status {
+				t.Errorf("%s: expected status %d, got %v", tc.name, tc.status, err)
+			}
+		}
+	})
+
+	t.Run("Configured limit", func(t *testing.T) {
+		jitjson.Configure(jitjson.Config{MaxRequestBytes: 8})
+		defer jitjson.Configure(jitjson.Config{})
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(jsonData))
+		var reqErr *jitjson.RequestError
+		if _, err := jitjson.BindRequest[Person](r); !errors.As(err, &reqErr) || reqErr.Status != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected the configured limit to apply, got %v", err)
+		}
+	})
+}
+
+func TestWriteJSON(t *testing.T) {
+	jsonData := `{"Name":"John",  "Age":30}`
+	w := httptest.NewRecorder()
+
+	jit := jitjson.NewFromBytes[Person]([]byte(jsonData))
+	if err := jitjson.WriteJSON(w, jit); err != nil {
+		t.Fatal(err)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %s", ct)
+	}
+	if w.Body.String() != jsonData {
+		t.Errorf("expected bytes to pass through unchanged, got %s", w.Body.String())
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	jsonData := `{"Name":"John","Age":30,"City":"New York"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(jsonData))
+	}))
+	defer srv.Close()
+
+	jit, err := jitjson.GetJSON[Person](context.Background(), srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" {
+		t.Error("values do not match")
+	}
+
+	_, err = jitjson.GetJSON[Person](context.Background(), nil, srv.URL+"/missing")
+	var statusErr *jitjson.StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusNotFound || !strings.Contains(string(statusErr.Body), "not found") {
+		t.Errorf("unexpected status error %+v", statusErr)
+	}
+
+	if _, err := jitjson.GetJSON[Person](context.Background(), nil, srv.URL, jitjson.WithMaxBytes(16)); !errors.Is(err, jitjson.ErrTooLarge) {
+		t.Errorf("expected the response body to be rejected, got %v", err)
+	}
+}
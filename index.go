@@ -0,0 +1,100 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Index is a hash index from the value of a key member to the positions of the items
+// holding it, for repeated point lookups into a large static collection. Only the key
+// of each item is extracted when the index is built, and Lookup decodes only the item
+// it finds, caching the result. Like JitJSON[T], an Index is not safe for concurrent use.
+type Index[T any] struct {
+	items     []*JitJSON[T]
+	positions map[Key][]int
+}
+
+// NewIndex builds an Index over items keyed by the member at keyPath:
+//
+//	users, err := jitjson.NewIndex(export, "id")
+//	user, ok, err := users.Lookup(42)
+//
+// Keys are extracted as by GroupBy, so items where keyPath is missing are indexed under
+// the zero Key, and 42 and "42" are different keys. Items holding only a value are
+// marshaled first.
+func NewIndex[T any](items []*JitJSON[T], keyPath string) (*Index[T], error) {
+	ix := &Index[T]{items: items, positions: map[Key][]int{}}
+	for i, item := range items {
+		key, err := itemKey(item, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		ix.positions[key] = append(ix.positions[key], i)
+	}
+	return ix, nil
+}
+
+// NewIndexFromDecoder builds an Index over the JSON values read from dec, each held as
+// a JitJSON[T] configured by opts without being decoded. Like AggregateDecoder, it reads
+// values until dec.More reports false, covering a stream of values or, once the opening
+// bracket has been read with dec.Token, the elements of an array.
+func NewIndexFromDecoder[T any](dec *json.Decoder, keyPath string, opts ...Option) (*Index[T], error) {
+	var items []*JitJSON[T]
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("jitjson: value %d: %w", i, err)
+		}
+		items = append(items, NewFromBytes[T](raw, opts...))
+	}
+	return NewIndex(items, keyPath)
+}
+
+// Len returns the number of indexed items.
+func (ix *Index[T]) Len() int {
+	return len(ix.items)
+}
+
+// Lookup decodes and returns the first item with the key, reporting false if there is
+// none. The key is a Key, or a string, number, boolean or nil converted by KeyOf.
+func (ix *Index[T]) Lookup(key any) (T, bool, error) {
+	var zero T
+	k, err := KeyOf(key)
+	if err != nil {
+		return zero, false, err
+	}
+	item := ix.item(k)
+	if item == nil {
+		return zero, false, nil
+	}
+	val, err := item.Unmarshal()
+	return val, true, err
+}
+
+// Item returns the first item with the key without decoding it, or nil if there is none
+// or the key is not valid for KeyOf.
+func (ix *Index[T]) Item(key any) *JitJSON[T] {
+	k, err := KeyOf(key)
+	if err != nil {
+		return nil
+	}
+	return ix.item(k)
+}
+
+func (ix *Index[T]) item(key Key) *JitJSON[T] {
+	pos := ix.positions[key]
+	if len(pos) == 0 {
+		return nil
+	}
+	return ix.items[pos[0]]
+}
+
+// Positions returns the positions of all items with the key, in order, or nil if the key
+// is not valid for KeyOf.
+func (ix *Index[T]) Positions(key any) []int {
+	k, err := KeyOf(key)
+	if err != nil {
+		return nil
+	}
+	return ix.positions[k]
+}
@@ -0,0 +1,82 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestIndex(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	var decodes int
+	hook := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	items := []*jitjson.JitJSON[user]{
+		jitjson.NewFromBytes[user]([]byte(`{"id": 1, "name": "John"}`), hook),
+		jitjson.NewFromBytes[user]([]byte(`{"id": 2, "name": "Jane"}`), hook),
+		jitjson.NewFromBytes[user]([]byte(`{"id": 1, "name": "Joe"}`), hook),
+		jitjson.NewFromBytes[user]([]byte(`{"name": "Jim"}`), hook),
+	}
+
+	ix, err := jitjson.NewIndex(items, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ix.Len() != 4 {
+		t.Errorf("expected 4 items, got %d", ix.Len())
+	}
+
+	u, ok, err := ix.Lookup(2)
+	if err != nil || !ok || u.Name != "Jane" {
+		t.Errorf("expected Jane, got %+v, %v, %v", u, ok, err)
+	}
+	if decodes != 1 {
+		t.Errorf("expected only the hit to be decoded, got %d decodes", decodes)
+	}
+
+	if u, ok, err := ix.Lookup(1); err != nil || !ok || u.Name != "John" {
+		t.Errorf("expected the first item with the key, got %+v, %v, %v", u, ok, err)
+	}
+	if pos := ix.Positions(1); !slices.Equal(pos, []int{0, 2}) {
+		t.Errorf("expected positions [0 2], got %v", pos)
+	}
+	if _, ok, _ := ix.Lookup("2"); ok {
+		t.Error("expected a string key not to match a number")
+	}
+	if _, ok, _ := ix.Lookup(3); ok {
+		t.Error("expected a missing key not to be found")
+	}
+	if ix.Item(3) != nil {
+		t.Error("expected no item for a missing key")
+	}
+	if u, ok, err := ix.Lookup(jitjson.Key{}); err != nil || !ok || u.Name != "Jim" {
+		t.Errorf("expected the item without an id under the zero Key, got %+v, %v, %v", u, ok, err)
+	}
+	if _, _, err := ix.Lookup([]int{1}); err == nil {
+		t.Error("expected an error for a non-scalar key")
+	}
+}
+
+func TestIndexFromDecoder(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`[{"Name": "John", "Age": 30}, {"Name": "Jane", "Age": 25}]`))
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	ix, err := jitjson.NewIndexFromDecoder[Person](dec, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok, err := ix.Lookup("Jane"); err != nil || !ok || p.Age != 25 {
+		t.Errorf("expected Jane aged 25, got %+v, %v, %v", p, ok, err)
+	}
+
+	dec = json.NewDecoder(strings.NewReader(`{"Name": "John"} {"Name": `))
+	if _, err := jitjson.NewIndexFromDecoder[Person](dec, "name"); err == nil {
+		t.Error("expected an error for a truncated value")
+	}
+}
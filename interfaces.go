@@ -0,0 +1,127 @@
+package jitjson
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultDiscriminator is the object member read to select the concrete type of an
+// interface, unless changed with SetDiscriminator.
+const DefaultDiscriminator = "type"
+
+// interfaceImpls holds the concrete types registered for an interface type.
+type interfaceImpls struct {
+	mu    sync.RWMutex
+	field string
+	impls map[string]reflect.Type
+}
+
+// interfaces maps interface types to their *interfaceImpls. hasInterfaces records
+// whether any are registered, so decoding other types skips the lookup.
+var (
+	interfaces    sync.Map
+	hasInterfaces atomic.Bool
+)
+
+// implsFor returns the registrations of the interface type Iface, creating them if
+// needed. It panics if Iface is not an interface type.
+func implsFor[Iface any]() *interfaceImpls {
+	typ := reflect.TypeFor[Iface]()
+	if typ.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("jitjson: %v is not an interface type", typ))
+	}
+	r, _ := interfaces.LoadOrStore(typ, &interfaceImpls{
+		field: DefaultDiscriminator,
+		impls: map[string]reflect.Type{},
+	})
+	hasInterfaces.Store(true)
+	return r.(*interfaceImpls)
+}
+
+// RegisterInterfaceImpl registers Impl as the concrete type JitJSON[Iface] decodes into
+// when the discriminator member of the object, "type" by default, equals discriminator:
+//
+//	jitjson.RegisterInterfaceImpl[Shape, *Circle]("circle")
+//	jitjson.RegisterInterfaceImpl[Shape, *Square]("square")
+//
+//	jit := jitjson.NewFromBytes[Shape]([]byte(`{"type":"circle","radius":2}`))
+//	shape, err := jit.Unmarshal() // shape holds a *Circle
+//
+// The discriminator is only read when Unmarshal runs, so decoding stays deferred.
+// Marshal encodes the concrete value as is, so Impl should include the discriminator
+// member for values to round trip. RegisterInterfaceImpl panics if Iface is not an
+// interface type or Impl does not implement it. It is typically called from init.
+func RegisterInterfaceImpl[Iface, Impl any](discriminator string) {
+	r := implsFor[Iface]()
+	impl := reflect.TypeFor[Impl]()
+	if !impl.Implements(reflect.TypeFor[Iface]()) {
+		panic(fmt.Sprintf("jitjson: %v does not implement %v", impl, reflect.TypeFor[Iface]()))
+	}
+	r.mu.Lock()
+	r.impls[discriminator] = impl
+	r.mu.Unlock()
+}
+
+// SetDiscriminator sets the object member read to select the concrete type registered
+// for Iface with RegisterInterfaceImpl. It panics if Iface is not an interface type.
+func SetDiscriminator[Iface any](field string) {
+	r := implsFor[Iface]()
+	r.mu.Lock()
+	r.field = field
+	r.mu.Unlock()
+}
+
+// concreteTarget returns the value to decode data into in place of v, a pointer to an
+// interface with registered implementations, and a function storing the decoded value
+// in v. If the interface has no registrations, v is returned unchanged.
+func concreteTarget(data []byte, v any) (any, func(), error) {
+	if !hasInterfaces.Load() {
+		return v, nil, nil
+	}
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Interface {
+		return v, nil, nil
+	}
+	if scanLiteral(data, skipSpace(data, 0), "null") > 0 {
+		return v, nil, nil
+	}
+	reg, ok := interfaces.Load(ptr.Type().Elem())
+	if !ok {
+		return v, nil, nil
+	}
+	r := reg.(*interfaceImpls)
+	r.mu.RLock()
+	field := r.field
+	r.mu.RUnlock()
+
+	raw, ok, err := FieldBytes(data, field)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("jitjson: decoding %v: missing discriminator %q", ptr.Type().Elem(), field)
+	}
+	var key string
+	if raw[0] == '"' {
+		key, err = unquote(raw)
+	}
+	if raw[0] != '"' || err != nil {
+		return nil, nil, fmt.Errorf("jitjson: decoding %v: discriminator %q is not a string", ptr.Type().Elem(), field)
+	}
+	r.mu.RLock()
+	impl, ok := r.impls[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("jitjson: decoding %v: no implementation registered for %s %q", ptr.Type().Elem(), field, key)
+	}
+
+	// pointer implementations are decoded in place; others are copied out afterwards
+	if impl.Kind() == reflect.Pointer {
+		target := reflect.New(impl.Elem())
+		return target.Interface(), func() { ptr.Elem().Set(target) }, nil
+	}
+	target := reflect.New(impl)
+	return target.Interface(), func() { ptr.Elem().Set(target.Elem()) }, nil
+}
@@ -0,0 +1,106 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Type   string  `json:"type"`
+	Radius float64 `json:"radius"`
+}
+
+func (c *Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Square struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+type Animal interface {
+	Sound() string
+}
+
+type Dog struct {
+	Kind string `json:"kind"`
+}
+
+func (Dog) Sound() string { return "woof" }
+
+func init() {
+	jitjson.RegisterInterfaceImpl[Shape, *Circle]("circle")
+	jitjson.RegisterInterfaceImpl[Shape, Square]("square")
+	jitjson.SetDiscriminator[Animal]("kind")
+	jitjson.RegisterInterfaceImpl[Animal, Dog]("dog")
+}
+
+func TestRegisterInterfaceImpl(t *testing.T) {
+	shape, err := jitjson.NewFromBytes[Shape]([]byte(`{"type":"circle","radius":2}`)).Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := shape.(*Circle); !ok || c.Radius != 2 {
+		t.Errorf("expected *Circle with radius 2, got %#v", shape)
+	}
+
+	shape, err = jitjson.NewFromBytes[Shape]([]byte(`{"side":3,"type":"square"}`)).Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape.Area() != 9 {
+		t.Errorf("expected Square with area 9, got %#v", shape)
+	}
+
+	animal, err := jitjson.NewFromBytes[Animal]([]byte(`{"kind":"dog"}`)).Unmarshal()
+	if err != nil || animal.Sound() != "woof" {
+		t.Errorf("expected Dog, got %#v, %v", animal, err)
+	}
+
+	// slices of lazy interface values decode each element by its own discriminator
+	var shapes []jitjson.JitJSON[Shape]
+	if err := json.Unmarshal([]byte(`[{"type":"circle","radius":1},{"type":"square","side":2},null]`), &shapes); err != nil {
+		t.Fatal(err)
+	}
+	var total float64
+	for i := range shapes {
+		s, err := shapes[i].Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != nil {
+			total += s.Area()
+		}
+	}
+	if total != 7 {
+		t.Errorf("expected total area 7, got %v", total)
+	}
+
+	for input, want := range map[string]string{
+		`{"radius":1}`:                   "missing discriminator",
+		`{"type":1}`:                     "not a string",
+		`{"type":"triangle"}`:            `no implementation registered for type "triangle"`,
+		`{"type":"circle","radius":"x"}`: "cannot unmarshal",
+	} {
+		if _, err := jitjson.NewFromBytes[Shape]([]byte(input)).Unmarshal(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestRegisterInterfaceImplPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for type not implementing the interface")
+		}
+	}()
+	jitjson.RegisterInterfaceImpl[Shape, Dog]("dog")
+}
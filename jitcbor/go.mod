@@ -0,0 +1,10 @@
+module github.com/mcwalrus/go-jitjson/jitcbor
+
+go 1.23.0
+
+require (
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/mcwalrus/go-jitjson v0.1.0
+)
+
+require github.com/x448/float16 v0.8.4 // indirect
@@ -0,0 +1,47 @@
+// Package jitcbor registers a CBOR jitjson.Parser under the name "cbor", so CBOR
+// payloads get the same just-in-time decoding and caching as JSON:
+//
+//	jit := jitjson.NewFromBytes[Reading](payload, jitjson.WithParser(jitcbor.ParserName))
+//	reading, err := jit.Unmarshal() // decoded from CBOR on first use
+//
+// Values using this parser hold CBOR bytes, so they should not be embedded in JSON.
+package jitcbor
+
+import (
+	"errors"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// ParserName is the name the CBOR parser is registered under with jitjson.RegisterParser.
+const ParserName = "cbor"
+
+func init() {
+	jitjson.RegisterParser(ParserName, Parser{})
+}
+
+// Parser implements jitjson.Parser with github.com/fxamacker/cbor/v2.
+type Parser struct{}
+
+// Marshal encodes v as CBOR.
+func (Parser) Marshal(v any) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+// Unmarshal decodes CBOR data into v. Malformed or truncated data and type mismatches
+// are reported as *jitjson.SyntaxError and *jitjson.TypeError.
+func (Parser) Unmarshal(data []byte, v any) error {
+	err := cbor.Unmarshal(data, v)
+	var syntaxErr *cbor.SyntaxError
+	var typeErr *cbor.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return &jitjson.SyntaxError{Err: err}
+	case errors.As(err, &typeErr):
+		return &jitjson.TypeError{Err: err}
+	}
+	return err
+}
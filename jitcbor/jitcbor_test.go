@@ -0,0 +1,52 @@
+package jitcbor_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitcbor"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestParser(t *testing.T) {
+	data, err := jitjson.New(Person{Name: "John", Age: 30}, jitjson.WithParser(jitcbor.ParserName)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jit := jitjson.NewFromBytes[Person](data, jitjson.WithParser(jitcbor.ParserName))
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" || p.Age != 30 {
+		t.Error("values do not match")
+	}
+
+	if _, err := jitjson.NewFromBytes[Person](data).Unmarshal(); err == nil {
+		t.Error("expected the default parser to reject cbor data")
+	}
+
+	canonical := jitjson.New(Person{Name: "John", Age: 30}, jitjson.WithParser(jitcbor.ParserName), jitjson.WithUseNumber(), jitjson.WithCanonical())
+	if out, err := canonical.Marshal(); err != nil || string(out) != string(data) {
+		t.Errorf("expected WithCanonical to be ignored, got %x, %v", out, err)
+	}
+}
+
+func TestParserErrors(t *testing.T) {
+	if _, err := jitjson.NewFromBytes[Person]([]byte{0xa1}, jitjson.WithParser(jitcbor.ParserName)).Unmarshal(); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error for truncated data, got %v", err)
+	}
+
+	data, err := jitjson.New("text", jitjson.WithParser(jitcbor.ParserName)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jitjson.NewFromBytes[int](data, jitjson.WithParser(jitcbor.ParserName)).Unmarshal(); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/mcwalrus/go-jitjson/jitecho
+
+go 1.23.0
+
+require (
+	github.com/labstack/echo/v4 v4.9.1
+	github.com/mcwalrus/go-jitjson v0.1.0
+)
+
+require (
+	github.com/labstack/gommon v0.4.0 // indirect
+	github.com/mattn/go-colorable v0.1.11 // indirect
+	github.com/mattn/go-isatty v0.0.14 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.1 // indirect
+	golang.org/x/crypto v0.40.0 // indirect
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
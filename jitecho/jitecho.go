@@ -0,0 +1,59 @@
+// Package jitecho binds and renders JitJSON values with echo, passing request and
+// response bodies through as raw bytes without a decode/encode round trip. It is a
+// separate module so the jitjson module does not depend on echo.
+//
+//	e := echo.New()
+//	e.JSONSerializer = jitecho.Serializer{}
+//
+// c.Bind and c.JSON then store and write lazy values as-is.
+package jitecho
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/bind"
+)
+
+// Serializer is an echo.JSONSerializer passing jitjson.Lazy values through as raw bytes.
+// Other values are encoded and decoded by encoding/json.
+type Serializer struct{}
+
+var _ echo.JSONSerializer = Serializer{}
+
+// Serialize writes the JSON encoding of i to the response, indented by indent if it is
+// not empty.
+func (Serializer) Serialize(c echo.Context, i any, indent string) error {
+	data, err := jitjson.Marshal(i)
+	if err != nil {
+		return err
+	}
+	if indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", indent); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	_, err = c.Response().Write(data)
+	return err
+}
+
+// Deserialize binds the JSON body of the request to i, as bind.Request does. Requests
+// rejected by jitjson.BindRequest fail with an *echo.HTTPError carrying its status.
+func (Serializer) Deserialize(c echo.Context, i any) error {
+	err := bind.Request(c.Request(), i)
+	var re *jitjson.RequestError
+	if errors.As(err, &re) {
+		return echo.NewHTTPError(re.Status, re.Error()).SetInternal(err)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package jitecho_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitecho"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestSerializer(t *testing.T) {
+	e := echo.New()
+	e.JSONSerializer = jitecho.Serializer{}
+
+	t.Run("Lazy value", func(t *testing.T) {
+		jsonData := `{"Name": "John",  "Age": 30}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(jsonData))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+
+		var jit jitjson.JitJSON[Person]
+		if err := c.Bind(&jit); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.JSON(http.StatusOK, &jit); err != nil {
+			t.Fatal(err)
+		}
+		if w.Body.String() != jsonData {
+			t.Errorf("expected the raw bytes, got %s", w.Body)
+		}
+	})
+
+	t.Run("Indent", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		w := c.Response().Writer.(*httptest.ResponseRecorder)
+		jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`))
+		if err := c.JSONPretty(http.StatusOK, jit, "  "); err != nil {
+			t.Fatal(err)
+		}
+		if w.Body.String() != "{\n  \"Name\": \"John\"\n}" {
+			t.Errorf("expected indented output, got %s", w.Body)
+		}
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"Name":`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		var jit jitjson.JitJSON[Person]
+		var he *echo.HTTPError
+		if err := c.Bind(&jit); !errors.As(err, &he) || he.Code != http.StatusBadRequest {
+			t.Errorf("expected a bad request, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,488 @@
+// Package jitexpr evaluates predicate expressions against jitjson.AnyJitJSON documents,
+// resolving only the paths an expression references, for routing and filtering rules
+// over event streams:
+//
+//	rule := jitexpr.MustCompile(`user.age > 30 && tags contains "vip"`)
+//	doc, err := jitjson.NewAny(event)
+//	ok, err := rule.Eval(doc)
+//
+// Expressions compare paths and literals with ==, !=, <, <=, >, >= and contains, and
+// combine the results with &&, || and !, grouped with parentheses. Paths are dotted
+// member names with optional [n] array indexes, such as items[0].sku. Literals are
+// numbers, double-quoted strings, true, false and null.
+package jitexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Expr is a compiled expression. It is safe for concurrent use.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses an expression.
+func Compile(src string) (*Expr, error) {
+	p := &parser{src: src}
+	p.next()
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed, for
+// initializing rules held in global variables.
+func MustCompile(src string) *Expr {
+	e, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression against doc, decoding only the members on the paths it
+// references. Paths that do not exist in doc evaluate to null, and comparisons between
+// values of different types are false, so rules over heterogeneous events do not fail.
+// The operands of &&, || and ! are true only if they are the boolean true. An error is
+// returned if a referenced part of doc is malformed.
+func (e *Expr) Eval(doc *jitjson.AnyJitJSON) (bool, error) {
+	v, err := e.root.eval(doc)
+	if err != nil {
+		return false, err
+	}
+	return v == true, nil
+}
+
+// node is a parsed expression, evaluating to a value as decoded by AnyJitJSON.ToInterface.
+type node interface {
+	eval(doc *jitjson.AnyJitJSON) (any, error)
+}
+
+// literal is a constant value.
+type literal struct{ val any }
+
+func (n literal) eval(*jitjson.AnyJitJSON) (any, error) {
+	return n.val, nil
+}
+
+// path is a reference to part of the document, holding member names and array indexes.
+type path []any
+
+func (n path) eval(doc *jitjson.AnyJitJSON) (any, error) {
+	cur := doc
+	for _, elem := range n {
+		switch elem := elem.(type) {
+		case string:
+			obj, ok := cur.AsObject()
+			if !ok {
+				if cur.Type() == jitjson.TypeObject {
+					return nil, fmt.Errorf("jitexpr: %s: invalid json object", n)
+				}
+				return nil, nil
+			}
+			if cur, ok = obj[elem]; !ok {
+				return nil, nil
+			}
+		case int:
+			arr, ok := cur.AsArray()
+			if !ok {
+				if cur.Type() == jitjson.TypeArray {
+					return nil, fmt.Errorf("jitexpr: %s: invalid json array", n)
+				}
+				return nil, nil
+			}
+			if elem >= len(arr) {
+				return nil, nil
+			}
+			cur = arr[elem]
+		}
+	}
+	v, err := cur.ToInterface()
+	if err != nil {
+		return nil, fmt.Errorf("jitexpr: %s: %w", n, err)
+	}
+	return v, nil
+}
+
+func (n path) String() string {
+	var b strings.Builder
+	for i, elem := range n {
+		switch elem := elem.(type) {
+		case string:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(elem)
+		case int:
+			fmt.Fprintf(&b, "[%d]", elem)
+		}
+	}
+	return b.String()
+}
+
+// not negates its operand.
+type not struct{ x node }
+
+func (n not) eval(doc *jitjson.AnyJitJSON) (any, error) {
+	v, err := n.x.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	return v != true, nil
+}
+
+// logical is a short-circuiting && or || of two operands.
+type logical struct {
+	and  bool
+	x, y node
+}
+
+func (n logical) eval(doc *jitjson.AnyJitJSON) (any, error) {
+	v, err := n.x.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	if (v == true) != n.and {
+		return v == true, nil
+	}
+	v, err = n.y.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	return v == true, nil
+}
+
+// compare applies a comparison operator to two operands.
+type compare struct {
+	op   string
+	x, y node
+}
+
+func (n compare) eval(doc *jitjson.AnyJitJSON) (any, error) {
+	x, err := n.x.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	y, err := n.y.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(x, y), nil
+	case "!=":
+		return !equal(x, y), nil
+	case "contains":
+		return contains(x, y), nil
+	}
+	c, ok := order(x, y)
+	if !ok {
+		return false, nil
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// equal reports whether two decoded values are equal.
+func equal(x, y any) bool {
+	return reflect.DeepEqual(x, y)
+}
+
+// contains reports whether the array x has an element equal to y, the object x has a
+// member named y, or the string x contains the string y.
+func contains(x, y any) bool {
+	switch x := x.(type) {
+	case []any:
+		for _, elem := range x {
+			if equal(elem, y) {
+				return true
+			}
+		}
+	case map[string]any:
+		if s, ok := y.(string); ok {
+			_, ok = x[s]
+			return ok
+		}
+	case string:
+		if s, ok := y.(string); ok {
+			return strings.Contains(x, s)
+		}
+	}
+	return false
+}
+
+// order compares two numbers or two strings, reporting false for other operands.
+func order(x, y any) (int, bool) {
+	switch x := x.(type) {
+	case float64:
+		if y, ok := y.(float64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if y, ok := y.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+// tokKind identifies the kind of a token.
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+// token is a lexical token of an expression, starting at byte offset pos.
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// parser is a recursive descent parser for expressions.
+type parser struct {
+	src string
+	off int
+	tok token
+	err error
+}
+
+// errorf returns a syntax error at the current token.
+func (p *parser) errorf(format string, args ...any) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("jitexpr: offset %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// next advances to the next token, recording the first lexical error.
+func (p *parser) next() {
+	for p.off < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.off]) >= 0 {
+		p.off++
+	}
+	start := p.off
+	if start == len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.src[start]
+	switch {
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for p.off < len(p.src) && isIdent(p.src[p.off]) {
+			p.off++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.off], pos: start}
+	case c == '-' || c >= '0' && c <= '9':
+		p.off++
+		for p.off < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.off]) >= 0 {
+			p.off++
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.off], pos: start}
+	case c == '"':
+		p.off++
+		for p.off < len(p.src) && p.src[p.off] != '"' {
+			if p.src[p.off] == '\\' {
+				p.off++
+			}
+			p.off++
+		}
+		if p.off >= len(p.src) {
+			p.tok = token{kind: tokEOF, pos: start}
+			p.err = fmt.Errorf("jitexpr: offset %d: unterminated string", start)
+			return
+		}
+		p.off++
+		p.tok = token{kind: tokString, text: p.src[start:p.off], pos: start}
+	default:
+		for _, op := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", ".", "[", "]"} {
+			if strings.HasPrefix(p.src[start:], op) {
+				p.off += len(op)
+				p.tok = token{kind: tokOp, text: op, pos: start}
+				return
+			}
+		}
+		p.off++
+		p.tok = token{kind: tokOp, text: p.src[start:p.off], pos: start}
+	}
+}
+
+// isIdent reports whether c may appear in an identifier after its first character.
+func isIdent(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// is reports whether the current token is the operator or keyword text.
+func (p *parser) is(text string) bool {
+	return (p.tok.kind == tokOp || p.tok.kind == tokIdent) && p.tok.text == text
+}
+
+func (p *parser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	for err == nil && p.is("||") {
+		p.next()
+		var y node
+		if y, err = p.parseAnd(); err == nil {
+			x = logical{x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) parseAnd() (node, error) {
+	x, err := p.parseNot()
+	for err == nil && p.is("&&") {
+		p.next()
+		var y node
+		if y, err = p.parseNot(); err == nil {
+			x = logical{and: true, x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.is("!") {
+		p.next()
+		x, err := p.parseNot()
+		return not{x}, err
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "contains"} {
+		if p.is(op) {
+			p.next()
+			y, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return compare{op: op, x: x, y: y}, nil
+		}
+	}
+	return x, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch {
+	case p.is("("):
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.is(")") {
+			return nil, p.errorf("expected \")\", got %s", p.tok)
+		}
+		p.next()
+		return x, nil
+	case tok.kind == tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %s", tok)
+		}
+		p.next()
+		return literal{f}, nil
+	case tok.kind == tokString:
+		var s string
+		if err := json.Unmarshal([]byte(tok.text), &s); err != nil {
+			return nil, p.errorf("invalid string %s", tok)
+		}
+		p.next()
+		return literal{s}, nil
+	case tok.kind == tokIdent:
+		switch tok.text {
+		case "true", "false":
+			p.next()
+			return literal{tok.text == "true"}, nil
+		case "null":
+			p.next()
+			return literal{nil}, nil
+		case "contains":
+			return nil, p.errorf("unexpected %s", tok)
+		}
+		return p.parsePath()
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+func (p *parser) parsePath() (node, error) {
+	n := path{p.tok.text}
+	p.next()
+	for {
+		switch {
+		case p.is("."):
+			p.next()
+			if p.tok.kind != tokIdent {
+				return nil, p.errorf("expected member name, got %s", p.tok)
+			}
+			n = append(n, p.tok.text)
+			p.next()
+		case p.is("["):
+			p.next()
+			i, err := strconv.Atoi(p.tok.text)
+			if p.tok.kind != tokNumber || err != nil || i < 0 {
+				return nil, p.errorf("expected array index, got %s", p.tok)
+			}
+			p.next()
+			if !p.is("]") {
+				return nil, p.errorf("expected \"]\", got %s", p.tok)
+			}
+			p.next()
+			n = append(n, i)
+		default:
+			return n, nil
+		}
+	}
+}
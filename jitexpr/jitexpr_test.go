@@ -0,0 +1,97 @@
+package jitexpr_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitexpr"
+)
+
+func TestEval(t *testing.T) {
+	doc, err := jitjson.NewAny([]byte(`{
+		"user": {"name": "John", "age": 42, "active": true},
+		"tags": ["vip", "beta"],
+		"items": [{"sku": "A-1", "qty": 2}],
+		"note": null
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`user.age > 30 && tags contains "vip"`, true},
+		{`user.age > 50 || tags contains "gold"`, false},
+		{`user.name == "John"`, true},
+		{`user.name != "John"`, false},
+		{`user.name >= "J" && user.name < "K"`, true},
+		{`user.active`, true},
+		{`!user.active`, false},
+		{`items[0].sku == "A-1" && items[0].qty <= 2`, true},
+		{`items[1].sku == null`, true},
+		{`user contains "age"`, true},
+		{`user.name contains "oh"`, true},
+		{`note == null && missing == null`, true},
+		{`missing.deeply[3] == null`, true},
+		{`user.age > "30"`, false},
+		{`user.age == 42.0`, true},
+		{`tags[1] == "beta"`, true},
+		{`(user.age < 18 || user.active) && !(note != null)`, true},
+		{`user.name`, false},
+	}
+	for _, tt := range tests {
+		e, err := jitexpr.Compile(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		got, err := e.Eval(doc)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestEvalLazy(t *testing.T) {
+	var doc jitjson.AnyJitJSON
+	if err := doc.UnmarshalJSON([]byte(`{"kind": "order", "payload": {"broken": [1 2]}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := jitexpr.MustCompile(`kind == "order"`).Eval(&doc)
+	if err != nil || !ok {
+		t.Errorf("expected unreferenced members to be skipped, got %v, %v", ok, err)
+	}
+	ok, err = jitexpr.MustCompile(`kind == "refund" && payload.broken[0] == 1`).Eval(&doc)
+	if err != nil || ok {
+		t.Errorf("expected && to short-circuit, got %v, %v", ok, err)
+	}
+	if _, err := jitexpr.MustCompile(`payload.broken[0] == 1`).Eval(&doc); err == nil {
+		t.Error("expected an error for a malformed referenced member")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`user.`,
+		`a ==`,
+		`a == "open`,
+		`a "b"`,
+		`(a == 1`,
+		`items[x]`,
+		`items[-1]`,
+		`a = 1`,
+		`contains "a"`,
+	} {
+		if _, err := jitexpr.Compile(src); err == nil {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package jitfiber binds and renders JitJSON values with fiber, passing request and
+// response bodies through as raw bytes without a decode/encode round trip. It is a
+// separate module so the jitjson module does not depend on fiber.
+//
+//	app := fiber.New(jitfiber.Config(fiber.Config{}))
+//
+// c.BodyParser and c.JSON then store and write lazy values as-is.
+package jitfiber
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Config returns cfg with its JSON encoder and decoder set to jitjson.Marshal and
+// jitjson.Unmarshal. Request bodies are copied when bound to lazy values, as fiber
+// reuses them once the handler returns.
+func Config(cfg fiber.Config) fiber.Config {
+	cfg.JSONEncoder = jitjson.Marshal
+	cfg.JSONDecoder = jitjson.Unmarshal
+	return cfg
+}
@@ -0,0 +1,42 @@
+package jitfiber_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitfiber"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestConfig(t *testing.T) {
+	app := fiber.New(jitfiber.Config(fiber.Config{}))
+	app.Post("/", func(c *fiber.Ctx) error {
+		var jit jitjson.JitJSON[Person]
+		if err := c.BodyParser(&jit); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return c.JSON(&jit)
+	})
+
+	jsonData := `{"Name": "John",  "Age": 30}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != jsonData {
+		t.Errorf("expected the raw bytes, got %d %s", resp.StatusCode, body)
+	}
+}
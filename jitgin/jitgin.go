@@ -0,0 +1,37 @@
+// Package jitgin binds and renders JitJSON values with gin, passing request and response
+// bodies through as raw bytes without a decode/encode round trip. It is a separate
+// module so the jitjson module does not depend on gin.
+//
+//	var jit jitjson.JitJSON[Person]
+//	if err := jitgin.ShouldBind(c, &jit); err != nil {
+//		c.AbortWithError(http.StatusBadRequest, err)
+//		return
+//	}
+//	jitgin.JSON(c, http.StatusOK, &jit)
+package jitgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+
+	"github.com/mcwalrus/go-jitjson/bind"
+)
+
+// Binding is the gin binding for JSON request bodies. Bodies bound to jitjson.Lazy values
+// are stored without decoding; other values are decoded by encoding/json. Use it with
+// c.ShouldBindWith, c.MustBindWith or c.ShouldBindBodyWith.
+var Binding binding.BindingBody = bind.JSON
+
+var _ render.Render = bind.Render{}
+
+// ShouldBind binds the JSON body of the request to obj with Binding.
+func ShouldBind(c *gin.Context, obj any) error {
+	return c.ShouldBindWith(obj, Binding)
+}
+
+// JSON writes obj as a JSON response with the given status code, passing lazy values
+// through from their stored bytes. Use it in place of c.JSON.
+func JSON(c *gin.Context, code int, obj any) {
+	c.Render(code, bind.Render{Data: obj})
+}
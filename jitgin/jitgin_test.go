@@ -0,0 +1,47 @@
+package jitgin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitgin"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/", func(c *gin.Context) {
+		var jit jitjson.JitJSON[Person]
+		if err := jitgin.ShouldBind(c, &jit); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		jitgin.JSON(c, http.StatusOK, &jit)
+	})
+
+	jsonData := `{"Name": "John",  "Age": 30}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != jsonData {
+		t.Errorf("expected the raw bytes, got %d %s", w.Code, w.Body)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"Name":`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad request, got %d", w.Code)
+	}
+}
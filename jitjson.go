@@ -3,7 +3,9 @@
 package jitjson
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"time"
 )
 
 // JitJSON[T] provides just-in-time (JIT) JSON parsing in Go for a value of type T.
@@ -12,74 +14,333 @@ import (
 type JitJSON[T any] struct {
 	data []byte
 	val  *T
+	verr error
+	opts *options
+	ext  *extension[T]
+}
+
+// extension holds the state of JitJSON[T] used only by optional features. It is
+// allocated on first use, so values not using them stay small.
+type extension[T any] struct {
+	// canonical records that data holds the canonical encoding set by WithCanonical.
+	canonical bool
+	// orig holds the data replaced by Set, whose number literals are spliced into the
+	// next encoding if WithPreserveNumbers is set.
+	orig []byte
+	// version is the version of data set by SetBytesWithVersion, and decodedAt the time
+	// val was decoded in Unix nanoseconds when ExpireAfter is set.
+	version   uint64
+	decodedAt int64
+	// pending produces the value on first use when neither data nor val is set, as
+	// created by Migrate.
+	pending func() (T, error)
+	// sampled records that the value is counted by WithAdaptive and not yet read.
+	sampled bool
+}
+
+// extend returns the extension of JitJSON[T], allocating it on first use.
+func (jit *JitJSON[T]) extend() *extension[T] {
+	if jit.ext == nil {
+		jit.ext = &extension[T]{}
+	}
+	return jit.ext
+}
+
+// dropEncoding forgets the state derived from the stored encoding, which is replaced.
+func (jit *JitJSON[T]) dropEncoding() {
+	if jit.ext != nil {
+		jit.ext.canonical = false
+		jit.ext.orig = nil
+	}
+}
+
+// isCanonical reports whether data holds the canonical encoding set by WithCanonical.
+func (jit *JitJSON[T]) isCanonical() bool {
+	return jit.ext != nil && jit.ext.canonical
+}
+
+// pending returns the function producing the value set by Migrate, if any.
+func (jit *JitJSON[T]) pending() func() (T, error) {
+	if jit.ext == nil {
+		return nil
+	}
+	return jit.ext.pending
 }
 
 // New creates JitJSON[T] from a value.
-func New[T any](val T) *JitJSON[T] {
-	return &JitJSON[T]{val: &val}
+func New[T any](val T, opts ...Option) *JitJSON[T] {
+	stats.deferredMarshals.Add(1)
+	return &JitJSON[T]{val: &val, opts: newOptions(opts)}
 }
 
 // NewFromBytes creates a JitJSON[T] from JSON byte data.
-func NewFromBytes[T any](data []byte) *JitJSON[T] {
-	return &JitJSON[T]{data: data}
+func NewFromBytes[T any](data []byte, opts ...Option) *JitJSON[T] {
+	return newFromBytes[T](data, newOptions(opts))
+}
+
+// newFromBytes creates a JitJSON[T] from JSON byte data with the options o.
+func newFromBytes[T any](data []byte, o *options) *JitJSON[T] {
+	data = o.trimBOM(data)
+	if o.eager(data) {
+		jit := newEager[T](data, o)
+		jit.sample()
+		return jit
+	}
+	recordDeferredUnmarshal(len(data))
+	jit := &JitJSON[T]{opts: o}
+	jit.setData(data)
+	jit.sample()
+	return jit
 }
 
 // Set JitJSON[T] to a new value.
 func (jit *JitJSON[T]) Set(val T) {
+	stats.deferredMarshals.Add(1)
+	if jit.opts != nil && jit.opts.preserveNumbers && jit.data != nil {
+		jit.extend().orig = jit.data
+	}
 	jit.val = &val
+	jit.verr = nil
 	jit.data = nil
+	if jit.ext != nil {
+		jit.ext.canonical = false
+	}
 }
 
 // Marshal performs deferred json marshaling for the value of JitJSON[T]. The method can return without evaluating
 // 'json.Marshal' if the value has been marshaled previously. Once marshaled, the encoded value is stored with the
-// jitjson for future use. If there is no value to marshal, the method returns nil, nil.
+// jitjson for future use. If there is no value to marshal, or jit is nil, the method returns nil, nil.
 func (jit *JitJSON[T]) Marshal() ([]byte, error) {
+	return jit.MarshalContext(context.Background())
+}
+
+// MarshalContext marshals JitJSON[T] like Marshal, passing ctx to the hooks observing the
+// encoding in ParseEvent.Context, so they can attach it to the request it belongs to.
+func (jit *JitJSON[T]) MarshalContext(ctx context.Context) ([]byte, error) {
+	if jit == nil {
+		return nil, nil
+	}
 	if jit.data != nil {
-		return jit.data, nil
+		stats.marshalCacheHits.Add(1)
+		data, err := jit.opts.load(jit.data)
+		if err != nil {
+			return nil, err
+		}
+		if jit.opts != nil && jit.opts.pooled {
+			// The data is held in a buffer recycled by Batch.Release, which the
+			// caller may hold on to for longer.
+			data = bytes.Clone(data)
+		}
+		return jit.canonicalize(data)
+	}
+	if jit.val == nil && jit.pending() != nil {
+		if err := jit.resolve(); err != nil {
+			return nil, err
+		}
 	}
 	if jit.val == nil {
 		return nil, nil
 	}
 
-	var err error
-	jit.data, err = json.Marshal(jit.val)
+	val, err := encodeValue(jit.val)
 	if err != nil {
 		return nil, err
 	}
+	stats.marshals.Add(1)
+	data, err := jit.opts.encode(ctx, val)
+	if err != nil {
+		return nil, err
+	}
+	// The encoding of a copy modified by encode hooks is not stored, since decoding it
+	// would not give back the value.
+	hooked := val != jit.val
+	if jit.ext != nil && jit.ext.orig != nil {
+		if orig, err := jit.opts.load(jit.ext.orig); err == nil {
+			data = spliceNumbers(data, orig)
+		}
+		if !hooked {
+			jit.ext.orig = nil
+		}
+	}
 
-	return jit.data, nil
+	if hooked || !jit.opts.keeps(KeepBytes) {
+		if jit.opts.canonicalizes() {
+			return Canonicalize(data)
+		}
+		return data, nil
+	}
+
+	stored, err := jit.opts.store(data)
+	if err != nil {
+		return nil, err
+	}
+	jit.data = stored
+	if jit.ext != nil {
+		jit.ext.canonical = false
+	}
+	if !jit.opts.keeps(KeepValue) {
+		jit.val = nil
+	}
+	return jit.canonicalize(data)
+}
+
+// canonicalize replaces the stored data, whose encoding is data, with its canonical
+// encoding if WithCanonical is set and it has not been canonicalized already.
+func (jit *JitJSON[T]) canonicalize(data []byte) ([]byte, error) {
+	if !jit.opts.canonicalizes() || jit.isCanonical() {
+		return data, nil
+	}
+	data, err := Canonicalize(data)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := jit.opts.store(data)
+	if err != nil {
+		return nil, err
+	}
+	jit.data = stored
+	jit.extend().canonical = true
+	return data, nil
 }
 
 // Unmarshal performs deferred json unmarshaling for the value of JitJSON[T]. The method can return without evaluating
 // 'json.Unmarshal' if the value has been unmarshaled previously. Once unmarshaled, the decoded value is stored with
-// the jitjson for future use. If there is no JSON data to unmarshal, the zero value of type T is returned.
-// If the JSON data does not unmarshal into the type T, the method will return an error.
+// the jitjson for future use. If there is no JSON data to unmarshal, or jit is nil, the zero value of type T is returned.
+// If the JSON data does not unmarshal into the type T, fails a hook registered with RegisterDecodeHook, or fails
+// validation set by WithValidator, the method will return an error.
 func (jit *JitJSON[T]) Unmarshal() (T, error) {
-	if jit.val != nil {
+	return jit.UnmarshalContext(context.Background())
+}
+
+// UnmarshalContext unmarshals JitJSON[T] like Unmarshal, passing ctx to the hooks
+// observing the decoding in ParseEvent.Context, such as to record it as a span of the
+// request's trace:
+//
+//	order, err := jit.UnmarshalContext(r.Context())
+func (jit *JitJSON[T]) UnmarshalContext(ctx context.Context) (T, error) {
+	if jit == nil {
+		var zero T
+		return zero, nil
+	}
+	jit.touch()
+	if jit.val != nil && !jit.expired() {
+		stats.unmarshalCacheHits.Add(1)
+		return *jit.val, jit.verr
+	}
+	if jit.data == nil && jit.val == nil && jit.pending() != nil {
+		if err := jit.resolve(); err != nil {
+			var val T
+			return val, err
+		}
 		return *jit.val, nil
 	}
-	var val T
 	if jit.data == nil {
+		var val T
 		return val, nil
 	}
+	data, err := jit.opts.load(jit.data)
+	if err != nil {
+		var val T
+		return val, err
+	}
 
-	jit.val = &val
-	err := json.Unmarshal(jit.data, jit.val)
+	jit.val = jit.newValue()
+	recordUnmarshal(len(data))
+	jit.verr = nil
+	err = jit.opts.decode(ctx, data, jit.val)
+	if jit.opts != nil && jit.opts.ttl > 0 {
+		jit.extend().decodedAt = time.Now().UnixNano()
+	}
 	if err != nil {
+		val := *jit.val
+		if !jit.opts.keeps(KeepValue) {
+			jit.val = nil
+		}
 		return val, err
 	}
 
-	return *jit.val, nil
+	jit.verr = afterDecode(jit.opts, jit.val)
+	val, verr := *jit.val, jit.verr
+	switch {
+	case !jit.opts.keeps(KeepValue):
+		jit.val, jit.verr = nil, nil
+	case !jit.opts.keeps(KeepBytes):
+		jit.data = nil
+	}
+	return val, verr
+}
+
+// UnmarshalValue decodes JitJSON[T] like Unmarshal, but without storing the decoded value,
+// so a later call decodes the data again. The value is decoded into a pooled *T and
+// returned by copy, so for small structs that are decoded once, such as in a loop over
+// many items, it can stay on the caller's stack instead of being allocated on the heap
+// behind a *T retained by JitJSON[T]. A value already stored by Unmarshal or Set is
+// returned as by Unmarshal.
+func (jit *JitJSON[T]) UnmarshalValue() (T, error) {
+	if jit == nil || (jit.val != nil && !jit.expired()) || jit.data == nil {
+		return jit.Unmarshal()
+	}
+	jit.touch()
+	var zero T
+	data, err := jit.opts.load(jit.data)
+	if err != nil {
+		return zero, err
+	}
+
+	pool := typePool[T]()
+	ptr, _ := pool.Get().(*T)
+	if ptr == nil {
+		ptr = new(T)
+	} else {
+		*ptr = zero
+	}
+	recordUnmarshal(len(data))
+	err = jit.opts.decode(context.Background(), data, ptr)
+	if err == nil {
+		err = afterDecode(jit.opts, ptr)
+	}
+	val := *ptr
+	*ptr = zero
+	pool.Put(ptr)
+	return val, err
 }
 
 // MarshalJSON can be used to marshal JitJSON[T] to JSON.
 func (jit *JitJSON[T]) MarshalJSON() ([]byte, error) {
+	if jit == nil {
+		return []byte("null"), nil
+	}
 	return jit.Marshal()
 }
 
-// UnmarshalJSON stores JSON data to be unmarshaled later.
+// UnmarshalJSON stores JSON data to be unmarshaled later. Data exceeding the limits set
+// by WithMaxBytes and WithMaxDepth, or by Configure, is rejected, as is data
+// that is not valid UTF-8 if WithValidUTF8 is set. Rejected data, or data the
+// BytesTransform fails to store, leaves JitJSON[T] unchanged.
 func (jit *JitJSON[T]) UnmarshalJSON(data []byte) error {
+	if jit.opts == nil {
+		jit.opts = defaults.Load()
+	}
+	data, err := jit.opts.accept(data)
+	if err != nil {
+		return err
+	}
+	if jit.opts != nil && jit.opts.copyData {
+		data = append([]byte(nil), data...)
+	}
+	defer jit.sample()
+	if jit.opts.eager(data) {
+		jit.decodeEager(data, jit.newValue())
+		return nil
+	}
+	stored, err := jit.opts.store(data)
+	if err != nil {
+		return err
+	}
+	recordDeferredUnmarshal(len(data))
 	jit.val = nil
-	jit.data = data
+	jit.verr = nil
+	jit.dropEncoding()
+	jit.data = stored
 	return nil
 }
@@ -3,6 +3,7 @@ package jitjson_test
 import (
 	"bytes"
 	"testing"
+	"unsafe"
 
 	"encoding/json"
 
@@ -51,6 +52,13 @@ func TestNewJitJSON(t *testing.T) {
 	})
 }
 
+func TestJitJSON_Size(t *testing.T) {
+	// The state of optional features is held apart, so values stay small.
+	if size := unsafe.Sizeof(jitjson.JitJSON[Person]{}); size > 64 {
+		t.Errorf("expected JitJSON to take at most 64 bytes, got %d", size)
+	}
+}
+
 func TestJitJSON_Nil(t *testing.T) {
 	jit := jitjson.New[*int](nil)
 
@@ -140,3 +148,35 @@ func TestJitJSON_Map(t *testing.T) {
 		t.Error("values do not match for person2")
 	}
 }
+
+func TestJitJSON_UnmarshalValue(t *testing.T) {
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30,"City":"New York"}`), count)
+
+	for i := 0; i < 2; i++ {
+		person, err := jit.UnmarshalValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if person.Name != "John" || person.Age != 30 || person.City != "New York" {
+			t.Error("values do not match")
+		}
+	}
+	if decodes != 2 || jit.DebugState().HasValue {
+		t.Errorf("expected the value not to be stored, got %d decodes", decodes)
+	}
+
+	jit.Set(Person{Name: "Jane"})
+	if person, err := jit.UnmarshalValue(); err != nil || person.Name != "Jane" {
+		t.Errorf("expected the set value, got %v, %v", person, err)
+	}
+
+	bad := jitjson.NewFromBytes[Person]([]byte(`{"Age":"thirty"}`))
+	if _, err := bad.UnmarshalValue(); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+	if person, err := jit.UnmarshalValue(); err != nil || person.Age != 0 {
+		t.Errorf("expected a zeroed value, got %v, %v", person, err)
+	}
+}
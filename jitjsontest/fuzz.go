@@ -0,0 +1,188 @@
+package jitjsontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// fuzzCorpus holds the seed inputs returned by FuzzCorpus.
+var fuzzCorpus = []string{
+	``,
+	` `,
+	"\t\r\n",
+	`null`,
+	` null `,
+	`nul`,
+	`true`,
+	`false`,
+	`truex`,
+	`0`,
+	`-0`,
+	`01`,
+	`1.`,
+	`.5`,
+	`1e`,
+	`-1.5e+10`,
+	`1e999`,
+	`123456789012345678901234567890`,
+	"\f1",
+	"1\f",
+	`""`,
+	`"`,
+	`"abc`,
+	`"a\"b"`,
+	`"é\n"`,
+	`"\x"`,
+	`"\u12"`,
+	"\"\x01\"",
+	`[]`,
+	`[`,
+	`[1,`,
+	`[1,]`,
+	`[1 2]`,
+	`[}`,
+	`[1,"a",true,null,[],{}]`,
+	`{}`,
+	`{`,
+	`{"a"}`,
+	`{"a":}`,
+	`{"a":1,}`,
+	`{1:2}`,
+	"{\"\x01\":1}",
+	"{\"\xbb\":0}",
+	`{"a":[1,{"b":null}],"c":"d"}`,
+	`{"a":1} x`,
+	`[[[[[[[[[[]]]]]]]]]]`,
+}
+
+// FuzzCorpus returns seed inputs for fuzz targets that decode JSON with jitjson.
+// They cover the edge cases of type detection and lazy splitting, such as whitespace
+// only input, unclosed strings, invalid escapes and numbers beyond float64 range, and
+// can be added to downstream targets with (*testing.F).Add.
+func FuzzCorpus() [][]byte {
+	corpus := make([][]byte, len(fuzzCorpus))
+	for i, s := range fuzzCorpus {
+		corpus[i] = []byte(s)
+	}
+	return corpus
+}
+
+// CheckAnyJitJSON decodes data with AnyJitJSON and returns an error describing the first
+// disagreement with encoding/json, such as a valid document being rejected, a value of
+// the wrong type or a malformed scalar being accepted. Arrays and objects are walked
+// through the As methods. It is the body of jitjson's own fuzz target and can be called
+// from downstream ones.
+func CheckAnyJitJSON(data []byte) error {
+	var a jitjson.AnyJitJSON
+	err := a.UnmarshalJSON(data)
+	if !json.Valid(data) {
+		if err == nil && !isContainer(data, '[', ']') && !isContainer(data, '{', '}') {
+			return fmt.Errorf("jitjsontest: invalid json %q accepted as %v", data, a.Type())
+		}
+		if err == nil {
+			// containers are split lazily, so they only need to be handled safely
+			a.AsArray()
+			a.AsObject()
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("jitjsontest: valid json %q rejected: %w", data, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var want interface{}
+	if err := dec.Decode(&want); err != nil {
+		return err
+	}
+	return checkAny(&a, want, "$")
+}
+
+// checkAny reports whether a holds the value decoded by encoding/json as want.
+func checkAny(a *jitjson.AnyJitJSON, want interface{}, path string) error {
+	mismatch := func(got interface{}) error {
+		return fmt.Errorf("jitjsontest: %s: got %v %v, want %v", path, a.Type(), got, want)
+	}
+	switch want := want.(type) {
+	case nil:
+		if !a.IsNull() {
+			return mismatch(nil)
+		}
+	case bool:
+		if b, ok := a.AsBool(); !ok || b != want {
+			return mismatch(b)
+		}
+	case json.Number:
+		if n, ok := a.AsNumber(); !ok || n != want {
+			return mismatch(n)
+		}
+	case string:
+		if s, ok := a.AsString(); !ok || s != want {
+			return mismatch(s)
+		}
+	case []interface{}:
+		arr, ok := a.AsArray()
+		if !ok || len(arr) != len(want) {
+			return mismatch(len(arr))
+		}
+		for i := range arr {
+			if err := checkAny(arr[i], want[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		obj, ok := a.AsObject()
+		if !ok || len(obj) != len(want) {
+			return mismatch(len(obj))
+		}
+		for k, v := range want {
+			m, ok := obj[k]
+			if !ok {
+				return fmt.Errorf("jitjsontest: %s: missing member %q", path, k)
+			}
+			if err := checkAny(m, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckJitJSON decodes data with JitJSON[T] configured by opts and returns an error
+// describing the first disagreement with encoding/json, or if re-marshaling the decoded
+// value does not produce valid JSON. It is the body of jitjson's own fuzz target and can
+// be called from downstream ones with their own types.
+func CheckJitJSON[T any](data []byte, opts ...jitjson.Option) error {
+	jit := jitjson.NewFromBytes[T](data, opts...)
+	val, err := jit.Unmarshal()
+
+	var want T
+	wantErr := json.Unmarshal(data, &want)
+	if (err == nil) != (wantErr == nil) {
+		return fmt.Errorf("jitjsontest: decoding %q: got error %v, want %v", data, err, wantErr)
+	}
+	if err != nil {
+		return nil
+	}
+
+	jit.Set(val)
+	out, err := jit.Marshal()
+	if err != nil {
+		return fmt.Errorf("jitjsontest: re-marshaling %q: %w", data, err)
+	}
+	if !json.Valid(out) {
+		return fmt.Errorf("jitjsontest: re-marshaling %q: invalid json %q", data, out)
+	}
+	return nil
+}
+
+// isContainer reports whether data, without surrounding JSON whitespace, starts with open
+// and ends with close.
+func isContainer(data []byte, open, close byte) bool {
+	data = bytes.Trim(data, " \t\r\n")
+	return len(data) > 1 && data[0] == open && data[len(data)-1] == close
+}
@@ -0,0 +1,133 @@
+// Package jitjsontest provides assertions for checking that types behave the same when
+// decoded lazily by jitjson as when decoded eagerly by encoding/json:
+//
+//	func TestOrderIsLazySafe(t *testing.T) {
+//		jitjsontest.RequireEquivalent[Order](t, []byte(`{"id":1,"items":[]}`))
+//	}
+//
+// It is the hand-written counterpart of the correctness test the performance-tester
+// generates. FuzzCorpus, CheckJitJSON and CheckAnyJitJSON supply the seeds and bodies
+// for fuzz targets over the same decoders.
+package jitjsontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// RequireEquivalent decodes data into T eagerly with json.Unmarshal and lazily through
+// JitJSON[T] configured by opts, and fails t unless both fail or both produce equal
+// values. The decoded value must also re-marshal to equivalent JSON either way, and if
+// data is valid JSON, AnyJitJSON must expose the same value as decoding it into an
+// interface{} with UseNumber.
+func RequireEquivalent[T any](t testing.TB, data []byte, opts ...jitjson.Option) {
+	t.Helper()
+
+	var want T
+	wantErr := json.Unmarshal(data, &want)
+	jit := jitjson.NewFromBytes[T](data, opts...)
+	got, err := jit.Unmarshal()
+	if (err == nil) != (wantErr == nil) {
+		t.Fatalf("jitjsontest: decoding %s into %T: lazy error %v, eager error %v", data, want, err, wantErr)
+	}
+	if err == nil {
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("jitjsontest: decoding %s into %T: lazy %#v, eager %#v", data, want, got, want)
+		}
+
+		wantData, wantErr := json.Marshal(want)
+		jit.Set(got)
+		gotData, err := jit.Marshal()
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("jitjsontest: encoding %T: lazy error %v, eager error %v", want, err, wantErr)
+		}
+		if err == nil && !equalJSON(gotData, wantData) {
+			t.Fatalf("jitjsontest: encoding %T: lazy %s, eager %s", want, gotData, wantData)
+		}
+	}
+
+	if json.Valid(data) {
+		RequireEquivalentAny(t, data)
+	}
+}
+
+// RequireEquivalentAny decodes data with AnyJitJSON and fails t unless it is accepted
+// and exposes the same value as decoding data into an interface{} with UseNumber.
+func RequireEquivalentAny(t testing.TB, data []byte) {
+	t.Helper()
+
+	want, err := decodeAny(data)
+	if err != nil {
+		t.Fatalf("jitjsontest: decoding %s: %v", data, err)
+	}
+	a, err := jitjson.NewAny(data)
+	if err != nil {
+		t.Fatalf("jitjsontest: decoding %s with AnyJitJSON: %v", data, err)
+	}
+	if got := toInterface(a); !reflect.DeepEqual(got, want) {
+		t.Fatalf("jitjsontest: decoding %s with AnyJitJSON: lazy %#v, eager %#v", data, got, want)
+	}
+}
+
+// equalJSON reports whether a and b encode the same value.
+func equalJSON(a, b []byte) bool {
+	x, err := decodeAny(a)
+	if err != nil {
+		return false
+	}
+	y, err := decodeAny(b)
+	return err == nil && reflect.DeepEqual(x, y)
+}
+
+// decodeAny decodes data into an interface{}, keeping numbers as json.Number.
+func decodeAny(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// toInterface converts a to the value encoding/json decodes with UseNumber. Values the
+// As methods cannot read are returned as the AnyJitJSON itself, so they never compare
+// equal.
+func toInterface(a *jitjson.AnyJitJSON) interface{} {
+	switch a.Type() {
+	case jitjson.TypeNull:
+		return nil
+	case jitjson.TypeBool:
+		v, _ := a.AsBool()
+		return v
+	case jitjson.TypeNumber:
+		v, _ := a.AsNumber()
+		return v
+	case jitjson.TypeString:
+		v, _ := a.AsString()
+		return v
+	case jitjson.TypeArray:
+		arr, ok := a.AsArray()
+		if !ok {
+			return a
+		}
+		v := make([]interface{}, len(arr))
+		for i, elem := range arr {
+			v[i] = toInterface(elem)
+		}
+		return v
+	case jitjson.TypeObject:
+		obj, ok := a.AsObject()
+		if !ok {
+			return a
+		}
+		v := make(map[string]interface{}, len(obj))
+		for k, member := range obj {
+			v[k] = toInterface(member)
+		}
+		return v
+	}
+	return a
+}
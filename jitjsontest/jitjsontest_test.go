@@ -0,0 +1,51 @@
+package jitjsontest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitjsontest"
+)
+
+type Order struct {
+	ID    int64             `json:"id"`
+	Items []string          `json:"items"`
+	Meta  map[string]string `json:"meta,omitempty"`
+	Total float64           `json:"total"`
+}
+
+// recorder is a testing.TB that records the failure instead of stopping the test.
+type recorder struct {
+	testing.TB
+	failed string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = fmt.Sprintf(format, args...)
+}
+
+func TestRequireEquivalent(t *testing.T) {
+	jitjsontest.RequireEquivalent[Order](t, []byte(`{"id":1,"items":["a","b"],"meta":{"k":"v"},"total":1.50}`))
+	jitjsontest.RequireEquivalent[Order](t, []byte(`{"id":"1"}`))
+	jitjsontest.RequireEquivalent[[]interface{}](t, []byte(`[1,"a",true,null,{"b":[]}]`))
+	jitjsontest.RequireEquivalent[Order](t, []byte(`{"id":1,"total":1e2}`), jitjson.WithCanonical())
+
+	r := &recorder{TB: t}
+	jitjsontest.RequireEquivalent[interface{}](r, []byte(`{"n":1}`), jitjson.WithUseNumber())
+	if r.failed == "" {
+		t.Error("expected json.Number decoding to differ from eager decoding")
+	}
+}
+
+func TestRequireEquivalentAny(t *testing.T) {
+	jitjsontest.RequireEquivalentAny(t, []byte(`{"a":[1,2.5,"x",{"b":null}],"c":false}`))
+
+	r := &recorder{TB: t}
+	jitjsontest.RequireEquivalentAny(r, []byte(`[1,`))
+	if r.failed == "" {
+		t.Error("expected invalid json to fail")
+	}
+}
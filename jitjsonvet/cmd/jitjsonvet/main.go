@@ -0,0 +1,17 @@
+// Command jitjsonvet reports misuse of jitjson types. It runs standalone on packages,
+// or as a go vet tool:
+//
+//	go vet -vettool=$(which jitjsonvet) ./...
+//
+// See package jitjsonvet for the checks performed.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/mcwalrus/go-jitjson/jitjsonvet"
+)
+
+func main() {
+	singlechecker.Main(jitjsonvet.Analyzer)
+}
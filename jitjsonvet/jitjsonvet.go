@@ -0,0 +1,302 @@
+// Package jitjsonvet defines an analyzer reporting common misuse of jitjson types.
+// It can be run standalone or with go vet through cmd/jitjsonvet, which lives with it
+// in its own module so that users of jitjson do not depend on golang.org/x/tools:
+//
+//	go install github.com/mcwalrus/go-jitjson/jitjsonvet/cmd/jitjsonvet@latest
+//	go vet -vettool=$(which jitjsonvet) ./...
+//
+// The analyzer reports:
+//
+//   - JitJSON values captured by goroutines while still in use by the launching
+//     function or launched in a loop, since their methods are not safe for
+//     concurrent use;
+//   - maps with non-pointer JitJSON or AnyJitJSON values, whose cached state is
+//     lost because map values are not addressable;
+//   - Marshal calls after the value returned by Unmarshal has been modified, which
+//     return the stale cached encoding instead of the modified value;
+//   - instantiations of jitjson generics with function, channel, complex or
+//     unsafe.Pointer types, which cannot be encoded as JSON.
+package jitjsonvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// jitjsonPath is the import path of the analyzed package.
+const jitjsonPath = "github.com/mcwalrus/go-jitjson"
+
+// Analyzer reports misuse of jitjson types.
+var Analyzer = &analysis.Analyzer{
+	Name:     "jitjson",
+	Doc:      "report misuse of jitjson.JitJSON and jitjson.AnyJitJSON values",
+	URL:      "https://pkg.go.dev/github.com/mcwalrus/go-jitjson/jitjsonvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	checkInstances(pass)
+
+	nodes := []ast.Node{(*ast.MapType)(nil), (*ast.GoStmt)(nil), (*ast.BlockStmt)(nil)}
+	insp.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.MapType:
+			checkMapType(pass, n)
+		case *ast.GoStmt:
+			checkGoStmt(pass, n, stack)
+		case *ast.BlockStmt:
+			checkStaleMarshal(pass, n.List)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// isJitType reports whether t is jitjson.JitJSON[T] or jitjson.AnyJitJSON.
+func isJitType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != jitjsonPath {
+		return false
+	}
+	return obj.Name() == "JitJSON" || obj.Name() == "AnyJitJSON"
+}
+
+// isJitPointer reports whether t is a pointer to a jitjson type.
+func isJitPointer(t types.Type) bool {
+	ptr, ok := types.Unalias(t).(*types.Pointer)
+	return ok && isJitType(ptr.Elem())
+}
+
+// typeString formats t with package names rather than import paths.
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == pass.Pkg {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// checkMapType reports maps holding jitjson values rather than pointers to them.
+func checkMapType(pass *analysis.Pass, m *ast.MapType) {
+	if t := pass.TypesInfo.TypeOf(m.Value); t != nil && isJitType(t) {
+		pass.Reportf(m.Value.Pos(), "map values of type %s are not addressable, so their cached state is lost; use a pointer",
+			typeString(pass, t))
+	}
+}
+
+// checkInstances reports jitjson generics instantiated with types JSON cannot encode.
+func checkInstances(pass *analysis.Pass) {
+	for ident, inst := range pass.TypesInfo.Instances {
+		obj := pass.TypesInfo.Uses[ident]
+		if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != jitjsonPath {
+			continue
+		}
+		for i := 0; i < inst.TypeArgs.Len(); i++ {
+			arg := inst.TypeArgs.At(i)
+			if unsupported(arg) {
+				pass.Reportf(ident.Pos(), "%s instantiated with %s, which cannot be encoded as JSON",
+					obj.Name(), typeString(pass, arg))
+			}
+		}
+	}
+}
+
+// unsupported reports whether values of type t cannot be encoded by encoding/json.
+func unsupported(t types.Type) bool {
+	for {
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = ptr.Elem()
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Signature, *types.Chan:
+		return true
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Complex64, types.Complex128, types.UnsafePointer:
+			return true
+		}
+	}
+	return false
+}
+
+// checkGoStmt reports jitjson values captured by a goroutine that are still used by
+// the launching function, or shared between goroutines launched in a loop.
+func checkGoStmt(pass *analysis.Pass, g *ast.GoStmt, stack []ast.Node) {
+	lit, ok := g.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	var fn ast.Node
+	var loop ast.Node
+	for i := len(stack) - 2; i >= 0 && fn == nil; i-- {
+		switch n := stack[i].(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			if loop == nil {
+				loop = n
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			fn = n
+		}
+	}
+	if fn == nil {
+		return
+	}
+
+	reported := map[types.Object]bool{}
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || reported[obj] || !isJitPointer(obj.Type()) || within(lit, obj.Pos()) {
+			return true
+		}
+		inLoop := loop != nil && !within(loop, obj.Pos())
+		if inLoop || usedAfter(pass, fn, obj, g.End()) {
+			reported[obj] = true
+			pass.Reportf(ident.Pos(), "%s is shared with a goroutine, but jitjson values are not safe for concurrent use", ident.Name)
+		}
+		return true
+	})
+}
+
+// within reports whether pos lies inside n.
+func within(n ast.Node, pos token.Pos) bool {
+	return n.Pos() <= pos && pos < n.End()
+}
+
+// usedAfter reports whether obj is referenced in fn after pos.
+func usedAfter(pass *analysis.Pass, fn ast.Node, obj types.Object, pos token.Pos) bool {
+	used := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if used {
+			return false
+		}
+		if ident, ok := n.(*ast.Ident); ok && ident.Pos() >= pos && pass.TypesInfo.Uses[ident] == obj {
+			used = true
+		}
+		return true
+	})
+	return used
+}
+
+// checkStaleMarshal reports Marshal calls on a jitjson value after the value returned
+// by its Unmarshal method was modified in the same block, without an intervening Set.
+func checkStaleMarshal(pass *analysis.Pass, stmts []ast.Stmt) {
+	decoded := map[types.Object]types.Object{} // decoded value -> jitjson variable
+	modified := map[types.Object]bool{}        // jitjson variables with modified values
+
+	for _, stmt := range stmts {
+		if as, ok := stmt.(*ast.AssignStmt); ok && len(as.Rhs) == 1 && len(as.Lhs) == 2 {
+			if recv, method := jitCall(pass, as.Rhs[0]); recv != nil && method == "Unmarshal" {
+				if v := identObj(pass, as.Lhs[0]); v != nil {
+					decoded[v] = recv
+					modified[recv] = false
+				}
+				continue
+			}
+		}
+
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					markModified(pass, lhs, decoded, modified)
+				}
+			case *ast.IncDecStmt:
+				markModified(pass, n.X, decoded, modified)
+			case *ast.CallExpr:
+				recv, method := jitCall(pass, n)
+				if recv == nil {
+					return true
+				}
+				switch method {
+				case "Set":
+					modified[recv] = false
+				case "Marshal", "MarshalJSON":
+					if modified[recv] {
+						pass.Reportf(n.Pos(), "%s returns the cached encoding, which does not reflect changes to the value returned by Unmarshal; call Set first",
+							method)
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// markModified records a modification of expr if it is rooted at a decoded value.
+func markModified(pass *analysis.Pass, expr ast.Expr, decoded map[types.Object]types.Object, modified map[types.Object]bool) {
+	if _, ok := expr.(*ast.Ident); ok {
+		return // reassigning the variable does not modify the decoded value
+	}
+	for {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			expr = e.X
+			continue
+		case *ast.IndexExpr:
+			expr = e.X
+			continue
+		case *ast.StarExpr:
+			expr = e.X
+			continue
+		case *ast.ParenExpr:
+			expr = e.X
+			continue
+		}
+		break
+	}
+	if obj := identObj(pass, expr); obj != nil {
+		if recv, ok := decoded[obj]; ok {
+			modified[recv] = true
+		}
+	}
+}
+
+// jitCall returns the receiver variable and method name of a method call on a
+// variable holding a pointer to a jitjson type.
+func jitCall(pass *analysis.Pass, expr ast.Expr) (types.Object, string) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil, ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, ""
+	}
+	recv := identObj(pass, sel.X)
+	if recv == nil || !isJitPointer(recv.Type()) {
+		return nil, ""
+	}
+	return recv, sel.Sel.Name
+}
+
+// identObj returns the object referenced by expr if it is an identifier.
+func identObj(pass *analysis.Pass, expr ast.Expr) types.Object {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+	return pass.TypesInfo.ObjectOf(ident)
+}
@@ -0,0 +1,13 @@
+package jitjsonvet_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/mcwalrus/go-jitjson/jitjsonvet"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), jitjsonvet.Analyzer, "a")
+}
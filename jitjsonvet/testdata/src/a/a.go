@@ -0,0 +1,87 @@
+package a
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type Person struct {
+	Name string
+	Tags []string
+}
+
+var byID map[string]jitjson.JitJSON[Person] // want `map values of type jitjson.JitJSON\[Person\] are not addressable`
+
+var anyByID map[string]*jitjson.AnyJitJSON
+
+var nested map[string]map[int]jitjson.AnyJitJSON // want `map values of type jitjson.AnyJitJSON are not addressable`
+
+func unsupportedTypes() {
+	jitjson.New(func() {})            // want `New instantiated with func\(\), which cannot be encoded as JSON`
+	var ch *jitjson.JitJSON[chan int] // want `JitJSON instantiated with chan int, which cannot be encoded as JSON`
+	_ = ch
+	jitjson.NewFromBytes[complex128](nil)      // want `NewFromBytes instantiated with complex128`
+	jitjson.NewFromBytes[*unsafe.Pointer](nil) // want `NewFromBytes instantiated with \*unsafe.Pointer`
+	jitjson.New(Person{})
+}
+
+func sharedAfterGo(data []byte) {
+	jit := jitjson.NewFromBytes[Person](data)
+	go func() {
+		jit.Unmarshal() // want `jit is shared with a goroutine`
+	}()
+	jit.Marshal()
+}
+
+func sharedInLoop(data []byte) {
+	jit := jitjson.NewFromBytes[Person](data)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jit.Unmarshal() // want `jit is shared with a goroutine`
+		}()
+	}
+	wg.Wait()
+}
+
+func handedOff(data []byte) {
+	jit := jitjson.NewFromBytes[Person](data)
+	go func() {
+		jit.Unmarshal()
+	}()
+
+	for i := 0; i < 3; i++ {
+		local := jitjson.NewFromBytes[Person](data)
+		go func() {
+			local.Unmarshal()
+		}()
+	}
+}
+
+func staleMarshal(jit *jitjson.JitJSON[Person]) {
+	p, _ := jit.Unmarshal()
+	p.Name = "Jane"
+	jit.Marshal() // want `Marshal returns the cached encoding`
+
+	p.Tags[0] = "x"
+	jit.MarshalJSON() // want `MarshalJSON returns the cached encoding`
+
+	jit.Set(p)
+	jit.Marshal()
+}
+
+func notStale(jit *jitjson.JitJSON[Person]) {
+	p, _ := jit.Unmarshal()
+	p = Person{Name: "Jane"}
+	_ = p
+	jit.Marshal()
+
+	q, _ := jit.Unmarshal()
+	q.Name = "Jim"
+	jit.Set(q)
+	jit.Marshal()
+}
@@ -0,0 +1,18 @@
+// Package jitjson is a stub of the jitjson API used by the analyzer tests.
+package jitjson
+
+type JitJSON[T any] struct {
+	data []byte
+	val  *T
+}
+
+func New[T any](val T) *JitJSON[T]                   { return &JitJSON[T]{val: &val} }
+func NewFromBytes[T any](data []byte) *JitJSON[T]    { return &JitJSON[T]{data: data} }
+func (jit *JitJSON[T]) Set(val T)                    { jit.val = &val }
+func (jit *JitJSON[T]) Marshal() ([]byte, error)     { return jit.data, nil }
+func (jit *JitJSON[T]) MarshalJSON() ([]byte, error) { return jit.data, nil }
+func (jit *JitJSON[T]) Unmarshal() (T, error)        { return *jit.val, nil }
+
+type AnyJitJSON struct {
+	data []byte
+}
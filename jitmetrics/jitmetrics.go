@@ -0,0 +1,136 @@
+// Package jitmetrics exports the jitjson parse counters, the undecoded-bytes gauge and
+// per-parser latencies, so the efficiency of lazy parsing is visible on dashboards. It is
+// a separate module so the jitjson module does not depend on Prometheus. The metrics can
+// be published with expvar:
+//
+//	jitmetrics.Publish("jitjson") // served at /debug/vars
+//
+// or registered with Prometheus:
+//
+//	prometheus.MustRegister(jitmetrics.NewCollector())
+//
+// Both enable the per-parser latencies with jitjson.EnableParserTimings, which are not
+// recorded otherwise.
+package jitmetrics
+
+import (
+	"expvar"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Publish publishes the jitjson counters as an expvar variable under name. The value
+// is computed on each read. Like expvar.Publish, it panics if name is already in use.
+func Publish(name string) {
+	jitjson.EnableParserTimings()
+	expvar.Publish(name, expvar.Func(func() any { return Values() }))
+}
+
+// Values returns the jitjson counters in the form published by Publish.
+func Values() map[string]any {
+	s := jitjson.Stats()
+	parsers := map[string]any{}
+	for name, t := range jitjson.ParserTimings() {
+		parsers[name] = map[string]any{
+			"marshals":          t.Marshals,
+			"marshal_seconds":   t.MarshalTime.Seconds(),
+			"unmarshals":        t.Unmarshals,
+			"unmarshal_seconds": t.UnmarshalTime.Seconds(),
+		}
+	}
+	return map[string]any{
+		"deferred_marshals":    s.DeferredMarshals,
+		"deferred_unmarshals":  s.DeferredUnmarshals,
+		"marshals":             s.Marshals,
+		"unmarshals":           s.Unmarshals,
+		"marshal_cache_hits":   s.MarshalCacheHits,
+		"unmarshal_cache_hits": s.UnmarshalCacheHits,
+		"avoided_marshals":     s.AvoidedMarshals(),
+		"avoided_unmarshals":   s.AvoidedUnmarshals(),
+		"bytes_deferred":       s.BytesDeferred,
+		"bytes_decoded":        s.BytesDecoded,
+		"undecoded_bytes":      s.UndecodedBytes(),
+		"parsers":              parsers,
+	}
+}
+
+// Collector is a prometheus.Collector for the jitjson counters. Parser latencies are
+// reported as a summary with an "op" label of "marshal" or "unmarshal".
+type Collector struct {
+	deferredMarshals   *prometheus.Desc
+	deferredUnmarshals *prometheus.Desc
+	marshals           *prometheus.Desc
+	unmarshals         *prometheus.Desc
+	marshalCacheHits   *prometheus.Desc
+	unmarshalCacheHits *prometheus.Desc
+	avoidedMarshals    *prometheus.Desc
+	avoidedUnmarshals  *prometheus.Desc
+	bytesDeferred      *prometheus.Desc
+	bytesDecoded       *prometheus.Desc
+	undecodedBytes     *prometheus.Desc
+	parserDuration     *prometheus.Desc
+}
+
+// NewCollector creates a Collector. Metric names are prefixed with "jitjson_".
+func NewCollector() *Collector {
+	jitjson.EnableParserTimings()
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc("jitjson_"+name, help, labels, nil)
+	}
+	return &Collector{
+		deferredMarshals:   desc("deferred_marshals_total", "Values stored without being encoded."),
+		deferredUnmarshals: desc("deferred_unmarshals_total", "Encodings stored without being decoded."),
+		marshals:           desc("marshals_total", "Encodings performed by Marshal."),
+		unmarshals:         desc("unmarshals_total", "Decodings performed by Unmarshal."),
+		marshalCacheHits:   desc("marshal_cache_hits_total", "Marshal calls answered from stored bytes."),
+		unmarshalCacheHits: desc("unmarshal_cache_hits_total", "Unmarshal calls answered from a stored value."),
+		avoidedMarshals:    desc("avoided_marshals", "Deferred values that have not been encoded."),
+		avoidedUnmarshals:  desc("avoided_unmarshals", "Deferred encodings that have not been decoded."),
+		bytesDeferred:      desc("deferred_bytes_total", "Encoded bytes stored for deferred decoding."),
+		bytesDecoded:       desc("decoded_bytes_total", "Deferred encoded bytes decoded by Unmarshal."),
+		undecodedBytes:     desc("undecoded_bytes", "Deferred encoded bytes that have not been decoded."),
+		parserDuration:     desc("parser_duration_seconds", "Time spent encoding and decoding by parser.", "parser", "op"),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.deferredMarshals
+	ch <- c.deferredUnmarshals
+	ch <- c.marshals
+	ch <- c.unmarshals
+	ch <- c.marshalCacheHits
+	ch <- c.unmarshalCacheHits
+	ch <- c.avoidedMarshals
+	ch <- c.avoidedUnmarshals
+	ch <- c.bytesDeferred
+	ch <- c.bytesDecoded
+	ch <- c.undecodedBytes
+	ch <- c.parserDuration
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	s := jitjson.Stats()
+	counter := func(d *prometheus.Desc, v uint64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v))
+	}
+	counter(c.deferredMarshals, s.DeferredMarshals)
+	counter(c.deferredUnmarshals, s.DeferredUnmarshals)
+	counter(c.marshals, s.Marshals)
+	counter(c.unmarshals, s.Unmarshals)
+	counter(c.marshalCacheHits, s.MarshalCacheHits)
+	counter(c.unmarshalCacheHits, s.UnmarshalCacheHits)
+	counter(c.bytesDeferred, s.BytesDeferred)
+	counter(c.bytesDecoded, s.BytesDecoded)
+	ch <- prometheus.MustNewConstMetric(c.avoidedMarshals, prometheus.GaugeValue, float64(s.AvoidedMarshals()))
+	ch <- prometheus.MustNewConstMetric(c.avoidedUnmarshals, prometheus.GaugeValue, float64(s.AvoidedUnmarshals()))
+	ch <- prometheus.MustNewConstMetric(c.undecodedBytes, prometheus.GaugeValue, float64(s.UndecodedBytes()))
+
+	for name, t := range jitjson.ParserTimings() {
+		ch <- prometheus.MustNewConstSummary(c.parserDuration, t.Marshals, t.MarshalTime.Seconds(), nil, name, "marshal")
+		ch <- prometheus.MustNewConstSummary(c.parserDuration, t.Unmarshals, t.UnmarshalTime.Seconds(), nil, name, "unmarshal")
+	}
+}
@@ -0,0 +1,64 @@
+package jitmetrics_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitmetrics"
+)
+
+type Person struct {
+	Name string
+}
+
+func TestPublish(t *testing.T) {
+	jitmetrics.Publish("jitjson_test")
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`))
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+
+	v := expvar.Get("jitjson_test")
+	if v == nil {
+		t.Fatal("expected expvar to be published")
+	}
+
+	var values struct {
+		Unmarshals uint64                    `json:"unmarshals"`
+		Parsers    map[string]map[string]any `json:"parsers"`
+	}
+	if err := json.Unmarshal([]byte(v.String()), &values); err != nil {
+		t.Fatal(err)
+	}
+	if values.Unmarshals == 0 {
+		t.Error("expected unmarshals to be counted")
+	}
+	if _, ok := values.Parsers[jitjson.DefaultParser]; !ok {
+		t.Errorf("expected timings for %s, got %v", jitjson.DefaultParser, values.Parsers)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(jitmetrics.NewCollector()); err != nil {
+		t.Fatal(err)
+	}
+
+	jit := jitjson.New(Person{Name: "Jane"})
+	if _, err := jit.Marshal(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := testutil.GatherAndCount(reg, "jitjson_marshals_total", "jitjson_undecoded_bytes", "jitjson_parser_duration_seconds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n < 4 {
+		t.Errorf("expected marshal counter, undecoded bytes gauge and parser summaries, got %d metrics", n)
+	}
+}
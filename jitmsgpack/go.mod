@@ -0,0 +1,10 @@
+module github.com/mcwalrus/go-jitjson/jitmsgpack
+
+go 1.23.0
+
+require (
+	github.com/mcwalrus/go-jitjson v0.1.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
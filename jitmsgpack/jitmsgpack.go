@@ -0,0 +1,51 @@
+// Package jitmsgpack registers a MessagePack jitjson.Parser under the name "msgpack",
+// so MessagePack payloads get the same just-in-time decoding and caching as JSON:
+//
+//	jit := jitjson.NewFromBytes[Call](payload, jitjson.WithParser(jitmsgpack.ParserName))
+//	call, err := jit.Unmarshal() // decoded from MessagePack on first use
+//
+// Values using this parser hold MessagePack bytes, so they should not be embedded in JSON.
+package jitmsgpack
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// ParserName is the name the MessagePack parser is registered under with jitjson.RegisterParser.
+const ParserName = "msgpack"
+
+func init() {
+	jitjson.RegisterParser(ParserName, Parser{})
+}
+
+// Parser implements jitjson.Parser with github.com/vmihailenco/msgpack/v5.
+type Parser struct{}
+
+// Marshal encodes v as MessagePack.
+func (Parser) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal decodes MessagePack data into v. Malformed data and type mismatches are
+// reported as *jitjson.SyntaxError and *jitjson.TypeError.
+func (Parser) Unmarshal(data []byte, v any) error {
+	err := msgpack.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+	// msgpack reports both classes with plain errors, told apart by their messages.
+	msg := err.Error()
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), strings.Contains(msg, "unknown code"):
+		return &jitjson.SyntaxError{Err: err}
+	case strings.Contains(msg, "invalid code="), strings.Contains(msg, "but msgpack has"):
+		return &jitjson.TypeError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package jitmsgpack_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitmsgpack"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestParser(t *testing.T) {
+	data, err := jitjson.New(Person{Name: "John", Age: 30}, jitjson.WithParser(jitmsgpack.ParserName)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jit := jitjson.NewFromBytes[Person](data, jitjson.WithParser(jitmsgpack.ParserName))
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" || p.Age != 30 {
+		t.Error("values do not match")
+	}
+
+	if _, err := jitjson.NewFromBytes[Person](data).Unmarshal(); err == nil {
+		t.Error("expected the default parser to reject msgpack data")
+	}
+}
+
+func TestParserErrors(t *testing.T) {
+	data, err := jitjson.New(Person{Name: "John", Age: 30}, jitjson.WithParser(jitmsgpack.ParserName)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jitjson.NewFromBytes[Person](data[:len(data)-2], jitjson.WithParser(jitmsgpack.ParserName)).Unmarshal(); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error for truncated data, got %v", err)
+	}
+	if _, err := jitjson.NewFromBytes[int](data, jitjson.WithParser(jitmsgpack.ParserName)).Unmarshal(); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+}
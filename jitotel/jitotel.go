@@ -0,0 +1,62 @@
+// Package jitotel records OpenTelemetry spans for the deferred parsing of jitjson values,
+// so traces show exactly when lazy parses fire inside request handling. Hook observes
+// parses like any jitjson.Hook, and parents each span to the span in the context given
+// to UnmarshalContext or MarshalContext:
+//
+//	jitjson.OnUnmarshal(jitotel.Hook(nil))
+//	...
+//	order, err := jit.UnmarshalContext(r.Context()) // recorded as a "jitjson.Unmarshal" span
+//
+// Spans carry the parser name, type and payload size as attributes. Parses through
+// Unmarshal and Marshal, which take no context, are recorded as root spans.
+package jitotel
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// ScopeName is the instrumentation scope of the tracer used when none is given.
+const ScopeName = "github.com/mcwalrus/go-jitjson"
+
+// Span attribute keys.
+const (
+	ParserKey      = attribute.Key("jitjson.parser")
+	TypeKey        = attribute.Key("jitjson.type")
+	PayloadSizeKey = attribute.Key("jitjson.payload_size")
+)
+
+// Hook returns a jitjson.Hook recording each parse it observes as a span of tracer,
+// timed by the event's duration. If tracer is nil, a tracer named ScopeName is obtained
+// from the global TracerProvider. Register it with jitjson.OnUnmarshal and
+// jitjson.OnMarshal, or per value with jitjson.WithOnUnmarshal and jitjson.WithOnMarshal.
+func Hook(tracer trace.Tracer) jitjson.Hook {
+	if tracer == nil {
+		tracer = otel.Tracer(ScopeName)
+	}
+	return func(e jitjson.ParseEvent) {
+		name := "jitjson.Marshal"
+		if e.Op == jitjson.OpUnmarshal {
+			name = "jitjson.Unmarshal"
+		}
+		end := time.Now()
+		_, span := tracer.Start(e.Context, name,
+			trace.WithTimestamp(end.Add(-e.Duration)),
+			trace.WithAttributes(
+				ParserKey.String(e.Parser),
+				TypeKey.String(e.Type),
+				PayloadSizeKey.Int(e.Size),
+			))
+		if e.Err != nil {
+			span.RecordError(e.Err)
+			span.SetStatus(codes.Error, e.Err.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+	}
+}
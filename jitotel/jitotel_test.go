@@ -0,0 +1,72 @@
+package jitotel_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitotel"
+)
+
+type Person struct {
+	Name string
+}
+
+func TestHook(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+	hook := jitjson.WithOnUnmarshal(jitotel.Hook(tracer))
+
+	ctx, parent := tracer.Start(context.Background(), "handler")
+	jsonData := []byte(`{"Name":"John"}`)
+	jit := jitjson.NewFromBytes[Person](jsonData, hook)
+	if _, err := jit.UnmarshalContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	bad := jitjson.NewFromBytes[Person]([]byte(`[]`), hook)
+	if _, err := bad.UnmarshalContext(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "jitjson.Unmarshal" {
+		t.Errorf("unexpected span name %q", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected span to be a child of the handler span")
+	}
+	if span.EndTime().Before(span.StartTime()) {
+		t.Errorf("unexpected span times %v to %v", span.StartTime(), span.EndTime())
+	}
+
+	attrs := map[string]any{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs[string(jitotel.ParserKey)] != jitjson.DefaultParser {
+		t.Errorf("unexpected parser attribute %v", attrs[string(jitotel.ParserKey)])
+	}
+	if attrs[string(jitotel.TypeKey)] != "jitotel_test.Person" {
+		t.Errorf("unexpected type attribute %v", attrs[string(jitotel.TypeKey)])
+	}
+	if attrs[string(jitotel.PayloadSizeKey)] != int64(len(jsonData)) {
+		t.Errorf("unexpected payload size attribute %v", attrs[string(jitotel.PayloadSizeKey)])
+	}
+
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", spans[1].Status())
+	}
+	if spans[1].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected failed span to be a child of the handler span")
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/mcwalrus/go-jitjson/jitpgx
+
+go 1.23.0
+
+require (
+	github.com/jackc/pgx/v5 v5.7.5
+	github.com/mcwalrus/go-jitjson v0.1.0
+)
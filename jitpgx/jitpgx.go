@@ -0,0 +1,89 @@
+// Package jitpgx registers json and jsonb codecs with pgx v5 that scan columns into
+// JitJSON with a single copy of the wire bytes:
+//
+//	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//		jitpgx.Register(conn.TypeMap())
+//		return nil
+//	}
+//	...
+//	var order jitjson.JitJSON[Order]
+//	err := conn.QueryRow(ctx, "SELECT doc FROM orders WHERE id = $1", id).Scan(&order)
+//
+// Without it, pgx scans jsonb into JitJSON through sql.Scanner, which copies text format
+// columns once into a string and again into the bytes JitJSON keeps. AnyJitJSON is
+// scanned the same way. Scanned values are decoded only when Unmarshal is called. Query
+// arguments of jitjson types are encoded through driver.Valuer, so they are marshaled on
+// demand or their stored bytes are sent as-is.
+package jitpgx
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Register replaces the json and jsonb codecs of m with ones scanning into JitJSON and
+// AnyJitJSON without an intermediate copy. Targets of other types are scanned as before.
+func Register(m *pgtype.Map) {
+	m.RegisterType(&pgtype.Type{Name: "json", OID: pgtype.JSONOID, Codec: &Codec{Codec: &pgtype.JSONCodec{Marshal: json.Marshal, Unmarshal: json.Unmarshal}}})
+	m.RegisterType(&pgtype.Type{Name: "jsonb", OID: pgtype.JSONBOID, Codec: &Codec{Codec: &pgtype.JSONBCodec{Marshal: json.Marshal, Unmarshal: json.Unmarshal}, jsonb: true}})
+}
+
+// Codec wraps a json or jsonb codec, planning its own scans into jitjson values and
+// leaving everything else to the wrapped codec.
+type Codec struct {
+	pgtype.Codec
+	jsonb bool
+}
+
+// lazyTarget is implemented by *JitJSON[T] and *AnyJitJSON.
+type lazyTarget interface {
+	sql.Scanner
+	json.Unmarshaler
+}
+
+// PlanScan returns a plan storing the column bytes in target if it is a jitjson value,
+// and the plan of the wrapped codec otherwise.
+func (c *Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(lazyTarget); ok && isJitJSON(target) {
+		return &scanPlan{jsonb: c.jsonb && format == pgtype.BinaryFormatCode}
+	}
+	return c.Codec.PlanScan(m, oid, format, target)
+}
+
+// isJitJSON reports whether target points to a type declared by jitjson, so that other
+// types implementing sql.Scanner keep their own scanning.
+func isJitJSON(target any) bool {
+	typ := reflect.TypeOf(target)
+	return typ.Kind() == reflect.Pointer && typ.Elem().PkgPath() == reflect.TypeFor[jitjson.AnyJitJSON]().PkgPath()
+}
+
+// scanPlan stores a column in a jitjson value. Binary jsonb columns are prefixed with a
+// version byte, which is checked and dropped.
+type scanPlan struct {
+	jsonb bool
+}
+
+func (p *scanPlan) Scan(src []byte, dst any) error {
+	target := dst.(lazyTarget)
+	if src == nil {
+		return target.Scan(nil)
+	}
+	if p.jsonb {
+		if len(src) == 0 {
+			return fmt.Errorf("jitpgx: jsonb too short")
+		}
+		if src[0] != 1 {
+			return fmt.Errorf("jitpgx: unknown jsonb version number %d", src[0])
+		}
+		src = src[1:]
+	}
+	// pgx reuses its read buffer between rows
+	return target.UnmarshalJSON(bytes.Clone(src))
+}
@@ -0,0 +1,137 @@
+package jitpgx_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitpgx"
+)
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func TestScan(t *testing.T) {
+	m := pgtype.NewMap()
+	jitpgx.Register(m)
+
+	tests := []struct {
+		name   string
+		oid    uint32
+		format int16
+		src    string
+	}{
+		{"json text", pgtype.JSONOID, pgtype.TextFormatCode, `{"Name":"John","Age":30}`},
+		{"json binary", pgtype.JSONOID, pgtype.BinaryFormatCode, `{"Name":"John","Age":30}`},
+		{"jsonb text", pgtype.JSONBOID, pgtype.TextFormatCode, `{"Name":"John","Age":30}`},
+		{"jsonb binary", pgtype.JSONBOID, pgtype.BinaryFormatCode, "\x01" + `{"Name":"John","Age":30}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decodes int
+			jit := jitjson.New(Person{}, jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+
+			buf := []byte(tt.src)
+			if err := m.Scan(tt.oid, tt.format, buf, jit); err != nil {
+				t.Fatal(err)
+			}
+			if decodes != 0 {
+				t.Errorf("expected scan to defer decoding, got %d decodes", decodes)
+			}
+
+			// pgx reuses its read buffer between rows
+			for i := range buf {
+				buf[i] = ' '
+			}
+
+			p, err := jit.Unmarshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Name != "John" || p.Age != 30 {
+				t.Errorf("unexpected value %+v", p)
+			}
+		})
+	}
+}
+
+func TestScanNull(t *testing.T) {
+	m := pgtype.NewMap()
+	jitpgx.Register(m)
+
+	jit := jitjson.New(Person{Name: "John"})
+	if err := m.Scan(pgtype.JSONBOID, pgtype.BinaryFormatCode, nil, jit); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := jit.Value(); v != nil {
+		t.Errorf("expected NULL to reset the value, got %s", v)
+	}
+}
+
+func TestScanAny(t *testing.T) {
+	m := pgtype.NewMap()
+	jitpgx.Register(m)
+
+	buf := []byte("\x01" + `"hello"`)
+	var a jitjson.AnyJitJSON
+	if err := m.Scan(pgtype.JSONBOID, pgtype.BinaryFormatCode, buf, &a); err != nil {
+		t.Fatal(err)
+	}
+	copy(buf, "\x01\"world\"")
+
+	if s, ok := a.AsString(); !ok || s != "hello" {
+		t.Errorf("expected scanned bytes to be copied, got %q", s)
+	}
+}
+
+func TestScanBadVersion(t *testing.T) {
+	m := pgtype.NewMap()
+	jitpgx.Register(m)
+
+	var jit jitjson.JitJSON[Person]
+	if err := m.Scan(pgtype.JSONBOID, pgtype.BinaryFormatCode, []byte("\x02{}"), &jit); err == nil {
+		t.Error("expected an error for an unknown jsonb version")
+	}
+}
+
+func TestScanOtherTargets(t *testing.T) {
+	m := pgtype.NewMap()
+	jitpgx.Register(m)
+
+	var p Person
+	if err := m.Scan(pgtype.JSONBOID, pgtype.TextFormatCode, []byte(`{"Name":"John"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" {
+		t.Errorf("unexpected value %+v", p)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	m := pgtype.NewMap()
+	jitpgx.Register(m)
+
+	var decodes int
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`), jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+	buf, err := m.Encode(pgtype.JSONBOID, pgtype.BinaryFormatCode, jit, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "\x01"+`{"Name":"John","Age":30}` {
+		t.Errorf("unexpected encoding %q", buf)
+	}
+	if decodes != 0 {
+		t.Errorf("expected stored bytes to be sent as-is, got %d decodes", decodes)
+	}
+
+	buf, err = m.Encode(pgtype.JSONBOID, pgtype.BinaryFormatCode, &jitjson.JitJSON[Person]{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf != nil {
+		t.Errorf("expected an empty JitJSON to encode as NULL, got %q", buf)
+	}
+}
@@ -0,0 +1,264 @@
+// Package jitxml bridges XML documents to jitjson, converting elements to JSON lazily so
+// XML feeds can be handled with the same tooling as JSON ones. Only the elements that
+// are accessed are converted; locating an element skips over its siblings without
+// converting them.
+//
+// Elements convert to JSON as follows. An element with neither attributes nor child
+// elements becomes a string of its text. Otherwise it becomes an object with a member
+// "@name" for each attribute, a member for each child element name, holding an array
+// if the name repeats, and a member "#text" for any text. Text is trimmed of leading
+// and trailing white space, and names are used without their namespace.
+package jitxml
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Element is an element of an XML document, holding its encoding until it is converted.
+type Element struct {
+	// Name is the local name of the element.
+	Name string
+	// Attr holds the attributes of the element.
+	Attr []xml.Attr
+
+	raw  []byte
+	json *jitjson.AnyJitJSON
+}
+
+// New returns the root element of the XML document data. Only the start tag of the root
+// is read; the rest of the document is read as elements are accessed.
+func New(data []byte) (*Element, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, &jitjson.SyntaxError{Err: errors.New("jitxml: no root element")}
+		}
+		if err != nil {
+			return nil, syntaxError(err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return &Element{Name: start.Name.Local, Attr: start.Attr, raw: data[offset:]}, nil
+		}
+	}
+}
+
+// Children returns the child elements of e named name, or all of them if name is empty,
+// in document order.
+func (e *Element) Children(name string) ([]*Element, error) {
+	var children []*Element
+	err := e.walk(func(child *Element) bool {
+		if name == "" || child.Name == name {
+			children = append(children, child)
+		}
+		return true
+	})
+	return children, err
+}
+
+// Child returns the first child element of e named name, reporting false if there is
+// none. The siblings after it are not read.
+func (e *Element) Child(name string) (*Element, bool, error) {
+	var found *Element
+	err := e.walk(func(child *Element) bool {
+		if child.Name == name {
+			found = child
+			return false
+		}
+		return true
+	})
+	return found, found != nil, err
+}
+
+// Path returns the element at the dot-separated path of child element names below e,
+// following the first child of each name, reporting false if there is none.
+func (e *Element) Path(path string) (*Element, bool, error) {
+	for _, name := range strings.Split(path, ".") {
+		child, ok, err := e.Child(name)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		e = child
+	}
+	return e, true, nil
+}
+
+// walk calls fn with each child element of e until fn returns false.
+func (e *Element) walk(fn func(child *Element) bool) error {
+	dec := xml.NewDecoder(bytes.NewReader(e.raw))
+	if _, err := dec.Token(); err != nil {
+		return syntaxError(err)
+	}
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return syntaxError(err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if err := dec.Skip(); err != nil {
+				return syntaxError(err)
+			}
+			child := &Element{Name: tok.Name.Local, Attr: tok.Attr, raw: e.raw[offset:dec.InputOffset()]}
+			if !fn(child) {
+				return nil
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// JSON returns e converted to JSON, converting it on first use.
+func (e *Element) JSON() (*jitjson.AnyJitJSON, error) {
+	if e.json != nil {
+		return e.json, nil
+	}
+	dec := xml.NewDecoder(bytes.NewReader(e.raw))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+	start, ok := tok.(xml.StartElement)
+	if !ok {
+		return nil, &jitjson.SyntaxError{Err: errors.New("jitxml: expected a start element")}
+	}
+	data, err := convert(dec, start)
+	if err != nil {
+		return nil, err
+	}
+	e.json, err = jitjson.NewAny(data)
+	return e.json, err
+}
+
+// Decode converts e to JSON and decodes it into a value of type T.
+func Decode[T any](e *Element, opts ...jitjson.Option) (T, error) {
+	a, err := e.JSON()
+	if err != nil {
+		var val T
+		return val, err
+	}
+	data, err := a.MarshalJSON()
+	if err != nil {
+		var val T
+		return val, err
+	}
+	return jitjson.NewFromBytes[T](data, opts...).Unmarshal()
+}
+
+// ToJSON converts the XML document data to JSON, as an object with a single member named
+// after the root element.
+func ToJSON(data []byte) ([]byte, error) {
+	root, err := New(data)
+	if err != nil {
+		return nil, err
+	}
+	a, err := root.JSON()
+	if err != nil {
+		return nil, err
+	}
+	val, err := a.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	key, _ := json.Marshal(root.Name)
+	out := append([]byte{'{'}, key...)
+	out = append(out, ':')
+	out = append(out, val...)
+	return append(out, '}'), nil
+}
+
+// object collects the members of a converted element in the order they first appear.
+type object struct {
+	keys    []string
+	members map[string][][]byte
+}
+
+func (o *object) add(key string, val []byte) {
+	if o.members == nil {
+		o.members = map[string][][]byte{}
+	}
+	if _, ok := o.members[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.members[key] = append(o.members[key], val)
+}
+
+// convert converts the element started by start, reading it from dec up to its end.
+func convert(dec *xml.Decoder, start xml.StartElement) ([]byte, error) {
+	var obj object
+	for _, attr := range start.Attr {
+		obj.add("@"+attr.Name.Local, quote(attr.Value))
+	}
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, syntaxError(err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			child, err := convert(dec, tok)
+			if err != nil {
+				return nil, err
+			}
+			obj.add(tok.Name.Local, child)
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(obj.keys) == 0 {
+				return quote(s), nil
+			}
+			if s != "" {
+				obj.add("#text", quote(s))
+			}
+			return obj.encode(), nil
+		}
+	}
+}
+
+// encode encodes o as a JSON object, with repeated members as arrays.
+func (o *object) encode() []byte {
+	out := []byte{'{'}
+	for i, key := range o.keys {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, quote(key)...)
+		out = append(out, ':')
+		vals := o.members[key]
+		if len(vals) == 1 {
+			out = append(out, vals[0]...)
+			continue
+		}
+		out = append(out, '[')
+		out = append(out, bytes.Join(vals, []byte{','})...)
+		out = append(out, ']')
+	}
+	return append(out, '}')
+}
+
+// quote returns s as a JSON string.
+func quote(s string) []byte {
+	data, _ := json.Marshal(s)
+	return data
+}
+
+// syntaxError classifies errors reading XML as syntax errors.
+func syntaxError(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return &jitjson.SyntaxError{Err: fmt.Errorf("jitxml: %w", err)}
+}
@@ -0,0 +1,91 @@
+package jitxml_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jitxml"
+)
+
+const feed = `<?xml version="1.0"?>
+<orders region="eu">
+	<!-- generated -->
+	<order id="1">
+		<customer>John</customer>
+		<item sku="A-1">2</item>
+		<item sku="B-2">1</item>
+	</order>
+	<order id="2"><customer>Jane</customer></order>
+	<broken><unclosed></broken>
+</orders>`
+
+type order struct {
+	ID       string `json:"@id"`
+	Customer string `json:"customer"`
+	Items    []struct {
+		SKU string `json:"@sku"`
+		Qty string `json:"#text"`
+	} `json:"item"`
+}
+
+func TestElement(t *testing.T) {
+	root, err := jitxml.New([]byte(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Name != "orders" || root.Attr[0].Value != "eu" {
+		t.Errorf("unexpected root %s %v", root.Name, root.Attr)
+	}
+
+	first, ok, err := root.Child("order")
+	if err != nil || !ok {
+		t.Fatalf("expected an order, got %v, %v", ok, err)
+	}
+	a, err := first.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"@id":"1","customer":"John","item":[{"@sku":"A-1","#text":"2"},{"@sku":"B-2","#text":"1"}]}`
+	if got, _ := a.MarshalJSON(); string(got) != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	o, err := jitxml.Decode[order](first)
+	if err != nil || o.ID != "1" || len(o.Items) != 2 || o.Items[1].SKU != "B-2" {
+		t.Errorf("unexpected order %+v, %v", o, err)
+	}
+
+	customer, ok, err := root.Path("order.customer")
+	if err != nil || !ok {
+		t.Fatalf("expected a customer, got %v, %v", ok, err)
+	}
+	if a, err := jitxml.Decode[string](customer); err != nil || a != "John" {
+		t.Errorf("unexpected customer %q, %v", a, err)
+	}
+	if _, ok, err := root.Path("order.missing"); ok || err != nil {
+		t.Errorf("expected no element, got %v, %v", ok, err)
+	}
+}
+
+func TestElementErrors(t *testing.T) {
+	root, err := jitxml.New([]byte(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.Children("order"); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error reading past the broken element, got %v", err)
+	}
+	if _, err := jitxml.New([]byte(`<!-- empty -->`)); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	out, err := jitxml.ToJSON([]byte(`<a x="1"><b>one</b><b/>text</a>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":{"@x":"1","b":["one",""],"#text":"text"}}`; string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+}
@@ -0,0 +1,146 @@
+// Package jityaml provides just-in-time YAML parsing, mirroring jitjson.JitJSON[T] for
+// YAML documents. It also registers a YAML jitjson.Parser under the name "yaml".
+package jityaml
+
+import (
+	"errors"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// ParserName is the name the YAML parser is registered under with jitjson.RegisterParser.
+const ParserName = "yaml"
+
+func init() {
+	jitjson.RegisterParser(ParserName, Parser{})
+}
+
+// Parser implements jitjson.Parser with gopkg.in/yaml.v3.
+type Parser struct{}
+
+// Marshal encodes v as YAML.
+func (Parser) Marshal(v any) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+// Unmarshal decodes YAML data into v. Type mismatches and malformed data are reported
+// as *jitjson.TypeError and *jitjson.SyntaxError.
+func (Parser) Unmarshal(data []byte, v any) error {
+	err := yaml.Unmarshal(data, v)
+	var typeErr *yaml.TypeError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &typeErr):
+		return &jitjson.TypeError{Err: err}
+	case strings.HasPrefix(err.Error(), "yaml: "):
+		// yaml.v3 reports malformed data with errors prefixed "yaml: ", without a
+		// type of their own; errors of UnmarshalYAML methods are left as they are.
+		return &jitjson.SyntaxError{Err: err}
+	}
+	return err
+}
+
+// JitYAML[T] provides just-in-time (JIT) YAML parsing for a value of type T. Parsing to
+// or from YAML is deferred until needed via the Marshal and Unmarshal methods. When
+// nested in a YAML document, the value's node is kept and decoded only on demand.
+type JitYAML[T any] struct {
+	data []byte
+	node *yaml.Node
+	val  *T
+}
+
+// New creates JitYAML[T] from a value.
+func New[T any](val T) *JitYAML[T] {
+	return &JitYAML[T]{val: &val}
+}
+
+// NewFromBytes creates a JitYAML[T] from YAML byte data.
+func NewFromBytes[T any](data []byte) *JitYAML[T] {
+	return &JitYAML[T]{data: data}
+}
+
+// Set JitYAML[T] to a new value.
+func (jit *JitYAML[T]) Set(val T) {
+	jit.val = &val
+	jit.data = nil
+	jit.node = nil
+}
+
+// Marshal performs deferred YAML marshaling for the value of JitYAML[T]. Once marshaled,
+// the encoded value is stored for future use. If there is no value, it returns nil, nil.
+func (jit *JitYAML[T]) Marshal() ([]byte, error) {
+	if jit.data != nil {
+		return jit.data, nil
+	}
+
+	var err error
+	switch {
+	case jit.val != nil:
+		jit.data, err = yaml.Marshal(jit.val)
+	case jit.node != nil:
+		jit.data, err = yaml.Marshal(jit.node)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		jit.data = nil
+		return nil, err
+	}
+	return jit.data, nil
+}
+
+// Unmarshal performs deferred YAML unmarshaling for the value of JitYAML[T]. Once
+// unmarshaled, the decoded value is stored for future use. If there is no YAML data,
+// the zero value of type T is returned.
+func (jit *JitYAML[T]) Unmarshal() (T, error) {
+	if jit.val != nil {
+		return *jit.val, nil
+	}
+
+	var val T
+	var err error
+	switch {
+	case jit.node != nil:
+		err = jit.node.Decode(&val)
+	case jit.data != nil:
+		err = yaml.Unmarshal(jit.data, &val)
+	default:
+		return val, nil
+	}
+	if err != nil {
+		return val, err
+	}
+
+	jit.val = &val
+	return val, nil
+}
+
+// MarshalYAML implements yaml.Marshaler, so JitYAML[T] can be nested in YAML documents.
+func (jit *JitYAML[T]) MarshalYAML() (any, error) {
+	switch {
+	case jit.val != nil:
+		return jit.val, nil
+	case jit.node != nil:
+		return jit.node, nil
+	case jit.data != nil:
+		var node yaml.Node
+		if err := yaml.Unmarshal(jit.data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+	default:
+		return nil, nil
+	}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler by keeping the node to be decoded later.
+func (jit *JitYAML[T]) UnmarshalYAML(node *yaml.Node) error {
+	jit.node = node
+	jit.data = nil
+	jit.val = nil
+	return nil
+}
@@ -0,0 +1,87 @@
+package jityaml_test
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mcwalrus/go-jitjson"
+	"github.com/mcwalrus/go-jitjson/jityaml"
+)
+
+type Person struct {
+	Name string `yaml:"name"`
+	Age  int    `yaml:"age"`
+}
+
+type Config struct {
+	Service string                   `yaml:"service"`
+	Owner   *jityaml.JitYAML[Person] `yaml:"owner"`
+}
+
+func TestJitYAML(t *testing.T) {
+	t.Run("Unmarshal bytes", func(t *testing.T) {
+		jit := jityaml.NewFromBytes[Person]([]byte("name: John\nage: 30\n"))
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" || p.Age != 30 {
+			t.Error("values do not match")
+		}
+	})
+
+	t.Run("Marshal value", func(t *testing.T) {
+		jit := jityaml.New(Person{Name: "Jane", Age: 25})
+		data, err := jit.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "name: Jane\nage: 25\n" {
+			t.Errorf("unexpected encoding %q", data)
+		}
+	})
+
+	t.Run("Nested field", func(t *testing.T) {
+		var cfg Config
+		err := yaml.Unmarshal([]byte("service: api\nowner:\n  name: John\n  age: 30\n"), &cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		owner, err := cfg.Owner.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if owner.Name != "John" {
+			t.Error("values do not match")
+		}
+
+		out, err := yaml.Marshal(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "service: api\nowner:\n    name: John\n    age: 30\n" {
+			t.Errorf("unexpected encoding %q", out)
+		}
+	})
+}
+
+func TestParser(t *testing.T) {
+	jit := jitjson.NewFromBytes[Person]([]byte("name: John\n"), jitjson.WithParser(jityaml.ParserName))
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" {
+		t.Error("values do not match")
+	}
+
+	bad := jitjson.NewFromBytes[Person]([]byte("name: [John\n"), jitjson.WithParser(jityaml.ParserName))
+	if _, err := bad.Unmarshal(); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+	mismatch := jitjson.NewFromBytes[Person]([]byte("age: old\n"), jitjson.WithParser(jityaml.ParserName))
+	if _, err := mismatch.Unmarshal(); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package jsonrpc provides JSON-RPC 2.0 envelopes whose params and result are held
+// lazily, so servers can route on the method without decoding the payloads of
+// methods they proxy elsewhere.
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// Version is the JSON-RPC protocol version carried by every envelope.
+const Version = "2.0"
+
+// Error codes defined by the JSON-RPC 2.0 specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC 2.0 request or notification. Params are kept as raw bytes
+// until accessed, and ID is kept verbatim so it can be echoed in the response.
+type Request struct {
+	JSONRPC string              `json:"jsonrpc"`
+	Method  string              `json:"method"`
+	Params  *jitjson.AnyJitJSON `json:"params,omitempty"`
+	ID      json.RawMessage     `json:"id,omitempty"`
+
+	// Err is set on the elements of a batch that are not valid requests, as returned by
+	// ParseRequests. It must be replied to with NewError(r.ID, r.Err.Code, r.Err.Message),
+	// whether or not the element has an ID.
+	Err *Error `json:"-"`
+}
+
+// IsNotification reports whether the request has no ID and expects no response.
+// Invalid elements of a batch, with Err set, are answered all the same.
+func (r *Request) IsNotification() bool {
+	return r.ID == nil && r.Err == nil
+}
+
+// Params decodes the params of r into a value of type T.
+func Params[T any](r *Request) (T, error) {
+	var val T
+	if r.Params == nil {
+		return val, nil
+	}
+	data, err := r.Params.MarshalJSON()
+	if err != nil {
+		return val, err
+	}
+	return jitjson.NewFromBytes[T](data).Unmarshal()
+}
+
+// Response is a JSON-RPC 2.0 response. The result is kept as raw bytes, so results
+// from upstream services can be forwarded verbatim.
+type Response struct {
+	JSONRPC string              `json:"jsonrpc"`
+	Result  *jitjson.AnyJitJSON `json:"result,omitempty"`
+	Error   *Error              `json:"error,omitempty"`
+	ID      json.RawMessage     `json:"id"`
+}
+
+// Error is the error object of a JSON-RPC 2.0 response.
+type Error struct {
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    *jitjson.AnyJitJSON `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc: %s (%d)", e.Message, e.Code)
+}
+
+// NewResult creates a successful response to the request with the given id. Lazy
+// values are used from their stored bytes without being decoded.
+func NewResult(id json.RawMessage, result any) (*Response, error) {
+	data, err := jitjson.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	res, err := jitjson.NewAny(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{JSONRPC: Version, Result: res, ID: nullID(id)}, nil
+}
+
+// NewError creates an error response to the request with the given id.
+func NewError(id json.RawMessage, code int, message string) *Response {
+	return &Response{JSONRPC: Version, Error: &Error{Code: code, Message: message}, ID: nullID(id)}
+}
+
+// nullID returns id, or null when the request id is unknown.
+func nullID(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+// ParseRequests parses a single request or a batch of requests, reporting whether
+// data was a batch. Only the envelopes are decoded; params remain raw bytes.
+//
+// An error is returned if data is not well-formed JSON, is an empty batch, or is a
+// single invalid request. Invalid elements of a batch do not fail the others: they are
+// returned as requests with Err set to an invalid request error, and their ID if it
+// could be decoded, so each gets its own error response.
+func ParseRequests(data []byte) ([]*Request, bool, error) {
+	var any jitjson.AnyJitJSON
+	if err := json.Unmarshal(data, &any); err != nil {
+		return nil, false, &Error{Code: CodeParseError, Message: "parse error"}
+	}
+	switch any.Type() {
+	case jitjson.TypeArray:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return nil, true, &Error{Code: CodeParseError, Message: "parse error"}
+		}
+		if len(elems) == 0 {
+			return nil, true, &Error{Code: CodeInvalidRequest, Message: "empty batch"}
+		}
+		reqs := make([]*Request, len(elems))
+		for i, elem := range elems {
+			reqs[i] = parseRequest(elem)
+		}
+		return reqs, true, nil
+	case jitjson.TypeObject:
+		req := parseRequest(data)
+		if req.Err != nil {
+			return nil, false, req.Err
+		}
+		return []*Request{req}, false, nil
+	}
+	return nil, false, &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+}
+
+// parseRequest parses a single request, setting Err if it is not valid.
+func parseRequest(data []byte) *Request {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil || req.JSONRPC != Version || req.Method == "" {
+		// Echo the ID if it can be found; otherwise the response carries a null ID.
+		var env struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if json.Unmarshal(data, &env) != nil {
+			env.ID = nil
+		}
+		return &Request{ID: env.ID, Err: &Error{Code: CodeInvalidRequest, Message: "invalid request"}}
+	}
+	return &req
+}
+
+// MarshalResponses encodes responses as a batch array, or as a single object when
+// batch is false. Results are spliced from their stored bytes.
+func MarshalResponses(resps []*Response, batch bool) ([]byte, error) {
+	if batch {
+		return json.Marshal(resps)
+	}
+	if len(resps) != 1 {
+		return nil, errors.New("jsonrpc: expected exactly one response")
+	}
+	return json.Marshal(resps[0])
+}
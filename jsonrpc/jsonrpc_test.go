@@ -0,0 +1,138 @@
+package jsonrpc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson/jsonrpc"
+)
+
+type addParams struct {
+	A, B int
+}
+
+func TestParseRequests(t *testing.T) {
+	t.Run("Single", func(t *testing.T) {
+		reqs, batch, err := jsonrpc.ParseRequests([]byte(`{"jsonrpc":"2.0","method":"add","params":{"A":1,"B":2},"id":7}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if batch || len(reqs) != 1 {
+			t.Fatalf("expected a single request, got %d (batch %v)", len(reqs), batch)
+		}
+		if reqs[0].Method != "add" || reqs[0].IsNotification() {
+			t.Error("unexpected request envelope")
+		}
+
+		params, err := jsonrpc.Params[addParams](reqs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if params.A+params.B != 3 {
+			t.Error("values do not match")
+		}
+
+		resp, err := jsonrpc.NewResult(reqs[0].ID, params.A+params.B)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := jsonrpc.MarshalResponses([]*jsonrpc.Response{resp}, batch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != `{"jsonrpc":"2.0","result":3,"id":7}` {
+			t.Errorf("unexpected response %s", out)
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		data := `[
+			{"jsonrpc":"2.0","method":"proxy","params":[1,{"deep":true}],"id":"a"},
+			{"jsonrpc":"2.0","method":"notify"}
+		]`
+		reqs, batch, err := jsonrpc.ParseRequests([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !batch || len(reqs) != 2 {
+			t.Fatalf("expected a batch of 2, got %d (batch %v)", len(reqs), batch)
+		}
+		if !reqs[1].IsNotification() {
+			t.Error("expected notification")
+		}
+
+		// forward params verbatim as the result
+		resp, err := jsonrpc.NewResult(reqs[0].ID, reqs[0].Params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := jsonrpc.MarshalResponses([]*jsonrpc.Response{
+			resp,
+			jsonrpc.NewError(nil, jsonrpc.CodeMethodNotFound, "method not found"),
+		}, batch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `[{"jsonrpc":"2.0","result":[1,{"deep":true}],"id":"a"},` +
+			`{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"},"id":null}]`
+		if string(out) != want {
+			t.Errorf("expected %s, got %s", want, out)
+		}
+	})
+
+	t.Run("Batch with invalid elements", func(t *testing.T) {
+		data := `[{"jsonrpc":"2.0","method":"add","id":1},{"jsonrpc":"1.0","method":"add","id":2},1,null]`
+		reqs, batch, err := jsonrpc.ParseRequests([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !batch || len(reqs) != 4 {
+			t.Fatalf("expected a batch of 4, got %d (batch %v)", len(reqs), batch)
+		}
+		if reqs[0].Err != nil || reqs[0].Method != "add" {
+			t.Errorf("expected the valid element to parse, got %+v", reqs[0])
+		}
+
+		var resps []*jsonrpc.Response
+		for _, req := range reqs[1:] {
+			if req.Err == nil || req.Err.Code != jsonrpc.CodeInvalidRequest || req.IsNotification() {
+				t.Fatalf("expected an invalid request, got %+v", req)
+			}
+			resps = append(resps, jsonrpc.NewError(req.ID, req.Err.Code, req.Err.Message))
+		}
+		out, err := jsonrpc.MarshalResponses(resps, batch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request"},"id":2},` +
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request"},"id":null},` +
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request"},"id":null}]`
+		if string(out) != want {
+			t.Errorf("expected %s, got %s", want, out)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		data string
+		code int
+	}{
+		{"Parse error", `{"jsonrpc":`, jsonrpc.CodeParseError},
+		{"Wrong version", `{"jsonrpc":"1.0","method":"add"}`, jsonrpc.CodeInvalidRequest},
+		{"Missing method", `{"jsonrpc":"2.0","id":1}`, jsonrpc.CodeInvalidRequest},
+		{"Empty batch", `[]`, jsonrpc.CodeInvalidRequest},
+		{"Scalar", `1`, jsonrpc.CodeInvalidRequest},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := jsonrpc.ParseRequests([]byte(tc.data))
+			var rpcErr *jsonrpc.Error
+			if !errors.As(err, &rpcErr) {
+				t.Fatalf("expected jsonrpc.Error, got %v", err)
+			}
+			if rpcErr.Code != tc.code {
+				t.Errorf("expected code %d, got %d", tc.code, rpcErr.Code)
+			}
+		})
+	}
+}
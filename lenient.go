@@ -0,0 +1,125 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ElementError reports an element of a JSON array that UnmarshalArrayLenient skipped
+// because it is not valid JSON.
+type ElementError struct {
+	// Index is the position of the element in the array, or -1 if the data is not an
+	// array at all.
+	Index int
+	// Offset is the byte offset of the element in the array's data.
+	Offset int
+	// Err describes the problem, as a *ParseError positioned within the element when
+	// the parser reports one.
+	Err error
+}
+
+func (e *ElementError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("jitjson: %v", e.Err)
+	}
+	return fmt.Sprintf("jitjson: element %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalArrayLenient splits the JSON array in data into JitJSON[T] values without
+// decoding them, skipping elements that are not valid JSON instead of rejecting the
+// whole array. It suits ingestion of dirty third-party feeds, where one broken record
+// should not discard the rest. The valid elements are returned in order, and each
+// skipped element is reported with its index. Elements are delimited by the commas and
+// closing bracket of the array itself, so a broken element, such as one with an
+// unterminated string or unbalanced brackets, may swallow the elements after it.
+//
+// Like NewFromBytes, the elements reference data without copying it. Elements that are
+// valid JSON but do not match T report an error from their Unmarshal as usual.
+func UnmarshalArrayLenient[T any](data []byte, opts ...Option) ([]*JitJSON[T], []ElementError) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return nil, []ElementError{{Index: -1, Err: errors.New("invalid json array")}}
+	}
+
+	o := newOptions(opts)
+	var items []*JitJSON[T]
+	var errs []ElementError
+	for index, start := 0, i+1; ; index++ {
+		end, last := lenientElementEnd(data, start)
+		offset, stop := skipSpace(data, start), end
+		for stop > offset && isSpace(data[stop-1]) {
+			stop--
+		}
+		elem := data[offset:stop]
+
+		switch {
+		case len(elem) == 0 && last && index == 0:
+			// empty array
+		case ScanValid(elem):
+			recordDeferredUnmarshal(len(elem))
+			item := &JitJSON[T]{opts: o}
+			item.setData(elem)
+			items = append(items, item)
+		default:
+			errs = append(errs, ElementError{Index: index, Offset: offset, Err: elementError(elem)})
+		}
+		if last {
+			if end >= len(data) || skipSpace(data, end+1) != len(data) {
+				errs = append(errs, ElementError{Index: -1, Offset: end, Err: errors.New("unterminated or trailing data after json array")})
+			}
+			return items, errs
+		}
+		start = end + 1
+	}
+}
+
+// lenientElementEnd returns the index of the comma or closing bracket ending the array
+// element starting at data[i], and whether it is the last element. Brackets within the
+// element are only counted, not matched, so that broken elements still end at the next
+// comma of the enclosing array.
+func lenientElementEnd(data []byte, i int) (int, bool) {
+	depth := 0
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			end := stringEnd(data, i)
+			if end < 0 {
+				return len(data), true
+			}
+			i = end - 1
+		case '[', '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ']':
+			if depth == 0 {
+				return i, true
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return i, false
+			}
+		}
+	}
+	return len(data), true
+}
+
+// elementError returns the error encoding/json reports for the invalid JSON elem.
+func elementError(elem []byte) error {
+	if len(elem) == 0 {
+		return errors.New("empty element")
+	}
+	var v json.RawMessage
+	if err := json.Unmarshal(elem, &v); err != nil {
+		return newParseError(elem, err, nil)
+	}
+	return errors.New("invalid json")
+}
@@ -0,0 +1,53 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestUnmarshalArrayLenient(t *testing.T) {
+	data := []byte(`[{"Name":"John"}, {"Name":}, {"Name":"Jane"}, tru, {"Name": "Jill"} ]`)
+	items, errs := jitjson.UnmarshalArrayLenient[Person](data)
+
+	var names []string
+	for _, item := range items {
+		p, err := item.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, p.Name)
+	}
+	if len(names) != 3 || names[0] != "John" || names[1] != "Jane" || names[2] != "Jill" {
+		t.Errorf("expected John, Jane and Jill, got %v", names)
+	}
+	if len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 3 {
+		t.Fatalf("expected errors for elements 1 and 3, got %v", errs)
+	}
+	if string(data[errs[0].Offset:errs[0].Offset+8]) != `{"Name":` {
+		t.Errorf("expected offset of element 1, got %d", errs[0].Offset)
+	}
+	var parseErr *jitjson.ParseError
+	if !errors.As(&errs[0], &parseErr) {
+		t.Errorf("expected a ParseError, got %v", errs[0].Err)
+	}
+
+	if items, errs := jitjson.UnmarshalArrayLenient[Person]([]byte(` [ ] `)); len(items) != 0 || len(errs) != 0 {
+		t.Errorf("expected empty array, got %v, %v", items, errs)
+	}
+	if _, errs := jitjson.UnmarshalArrayLenient[Person]([]byte(`{}`)); len(errs) != 1 || errs[0].Index != -1 {
+		t.Errorf("expected a single error for a non-array, got %v", errs)
+	}
+
+	// an unterminated string swallows the rest of the array
+	items, errs = jitjson.UnmarshalArrayLenient[Person]([]byte(`[{"Name":"A"}, {"Name":"B}, {"Name":"C"}]`))
+	if len(items) != 1 || len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != -1 {
+		t.Errorf("expected one item, a broken element and an unterminated array, got %d items, %v", len(items), errs)
+	}
+
+	items, errs = jitjson.UnmarshalArrayLenient[Person]([]byte(`[1,,2]`))
+	if len(items) != 2 || len(errs) != 1 || errs[0].Index != 1 {
+		t.Errorf("expected an empty element error, got %d items, %v", len(items), errs)
+	}
+}
@@ -0,0 +1,58 @@
+package jitjson
+
+import "context"
+
+// ParseLimiter limits the number of decodes of large payloads running at once, smoothing
+// the CPU spikes when a burst of large documents is decoded on first access together.
+// Decodes of smaller payloads are not limited. A ParseLimiter is attached to values with
+// WithParseLimiter, and is typically shared by all values of a service. It is safe for
+// concurrent use.
+type ParseLimiter struct {
+	minSize int
+	sem     chan struct{}
+}
+
+// NewParseLimiter returns a ParseLimiter allowing at most concurrency decodes of payloads
+// of minSize bytes or more to run at once. It panics if concurrency is less than one.
+func NewParseLimiter(concurrency, minSize int) *ParseLimiter {
+	if concurrency < 1 {
+		panic("jitjson: NewParseLimiter concurrency must be positive")
+	}
+	return &ParseLimiter{minSize: minSize, sem: make(chan struct{}, concurrency)}
+}
+
+// InFlight returns the number of limited decodes running.
+func (l *ParseLimiter) InFlight() int {
+	return len(l.sem)
+}
+
+// WithParseLimiter makes decodes of JitJSON[T] wait for l before decoding payloads of at
+// least its minimum size. The wait is not included in the durations reported to hooks
+// and tracers, and ends with the context's error if the context given to
+// UnmarshalContext is done first. Values decoded eagerly, being small, are normally below
+// the minimum.
+//
+// A decode holds its slot until it returns, so decodes sharing l must not nest: if the
+// UnmarshalJSON method of T decodes another value limited by l, decodes at full
+// concurrency wait on each other forever, unless their contexts are canceled.
+func WithParseLimiter(l *ParseLimiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// acquire waits for a slot to decode data with the configured ParseLimiter, if it is
+// limited, returning a function releasing the slot or nil. It returns the error of ctx
+// if ctx is done before a slot is free.
+func (o *options) acquire(ctx context.Context, data []byte) (release func(), err error) {
+	if o == nil || o.limiter == nil || len(data) < o.limiter.minSize {
+		return nil, nil
+	}
+	l := o.limiter
+	select {
+	case l.sem <- struct{}{}:
+		return func() { <-l.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package jitjson_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// concurrencyParser wraps encoding/json and records the most decodes run at once.
+type concurrencyParser struct {
+	running, max atomic.Int32
+}
+
+func (p *concurrencyParser) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (p *concurrencyParser) Unmarshal(data []byte, v any) error {
+	n := p.running.Add(1)
+	defer p.running.Add(-1)
+	for {
+		max := p.max.Load()
+		if n <= max || p.max.CompareAndSwap(max, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return json.Unmarshal(data, v)
+}
+
+func TestParseLimiter(t *testing.T) {
+	parser := &concurrencyParser{}
+	registerParser(t, "concurrency", parser)
+	l := jitjson.NewParseLimiter(2, 64)
+
+	large := []byte(`{"Name":"` + strings.Repeat("x", 64) + `"}`)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jit := jitjson.NewFromBytes[Person](large, jitjson.WithParser("concurrency"), jitjson.WithParseLimiter(l))
+			if _, err := jit.Unmarshal(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if max := parser.max.Load(); max > 2 {
+		t.Errorf("expected at most 2 decodes at once, got %d", max)
+	}
+	if n := l.InFlight(); n != 0 {
+		t.Errorf("expected no decodes in flight, got %d", n)
+	}
+}
+
+func TestParseLimiterContext(t *testing.T) {
+	l := jitjson.NewParseLimiter(1, 0)
+	blockEntered, blockRelease = make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jitjson.NewFromBytes[blockingValue]([]byte(`{}`), jitjson.WithParseLimiter(l)).Unmarshal()
+	}()
+	<-blockEntered
+	defer func() {
+		close(blockRelease)
+		<-done
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), jitjson.WithParseLimiter(l))
+	if _, err := jit.UnmarshalContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+}
+
+// blockingValue holds its decode, and so its ParseLimiter slot, until blockRelease is
+// closed, signaling blockEntered once decoding.
+type blockingValue struct{}
+
+var blockEntered, blockRelease chan struct{}
+
+func (*blockingValue) UnmarshalJSON([]byte) error {
+	blockEntered <- struct{}{}
+	<-blockRelease
+	return nil
+}
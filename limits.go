@@ -0,0 +1,66 @@
+package jitjson
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTooLarge is returned when JSON data exceeds the configured size limit.
+	ErrTooLarge = errors.New("jitjson: data exceeds size limit")
+	// ErrTooDeep is returned when JSON data exceeds the configured nesting depth limit.
+	ErrTooDeep = errors.New("jitjson: data exceeds nesting depth limit")
+)
+
+// WithMaxBytes limits the size of the JSON data accepted by SetBytes and UnmarshalJSON
+// to n bytes, overriding Config.MaxBytes. Because the cost of a payload otherwise only
+// appears when it is finally decoded, long-lived services should set limits, here or
+// with Configure, to reject adversarial payloads on arrival. Zero means no limit.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithMaxDepth limits the nesting depth of the JSON data accepted by SetBytes and
+// UnmarshalJSON to n levels of arrays and objects, overriding Config.MaxDepth. Zero
+// means no limit.
+func WithMaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
+
+// limits returns the size and depth limits configured by the options, or by Configure
+// for nil options.
+func (o *options) limits() (maxBytes int64, maxDepth int) {
+	if o == nil {
+		o = defaults.Load()
+	}
+	if o == nil {
+		return 0, 0
+	}
+	return o.maxBytes, o.maxDepth
+}
+
+// checkLimits returns an error if data exceeds the limits configured by the options,
+// or by Configure for nil options.
+func (o *options) checkLimits(data []byte) error {
+	maxBytes, maxDepth := o.limits()
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return &LimitError{Err: fmt.Errorf("%w: %d bytes exceeds %d", ErrTooLarge, len(data), maxBytes)}
+	}
+	if maxDepth > 0 && depthExceeds(data, maxDepth) {
+		return &LimitError{Err: fmt.Errorf("%w of %d", ErrTooDeep, maxDepth)}
+	}
+	return nil
+}
+
+// SetBytes sets JitJSON[T] to the JSON data, which is decoded on the next Unmarshal.
+// Unlike NewFromBytes, the data is checked against the configured size, depth and UTF-8
+// requirements. Like UnmarshalJSON, the data is retained as passed, so the caller must
+// not modify it afterwards, unless WithCopyOnUnmarshal or Config.CopyOnUnmarshal is set,
+// in which case a copy is stored.
+func (jit *JitJSON[T]) SetBytes(data []byte) error {
+	return jit.UnmarshalJSON(data)
+}
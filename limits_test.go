@@ -0,0 +1,56 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestLimits(t *testing.T) {
+	deep := []byte(strings.Repeat("[", 5) + strings.Repeat("]", 5))
+	quoted := []byte(`["[[[[[[[[[["]`)
+
+	t.Run("Per instance", func(t *testing.T) {
+		jit := jitjson.NewFromBytes[any](nil, jitjson.WithMaxBytes(11), jitjson.WithMaxDepth(4))
+		if err := jit.SetBytes([]byte(`"0123456789ab"`)); !errors.Is(err, jitjson.ErrTooLarge) {
+			t.Errorf("expected ErrTooLarge, got %v", err)
+		}
+		if err := jit.SetBytes(deep); !errors.Is(err, jitjson.ErrTooDeep) {
+			t.Errorf("expected ErrTooDeep, got %v", err)
+		}
+		if err := jit.SetBytes([]byte(`[[1]]`)); err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := jit.Unmarshal(); v == nil {
+			t.Error("expected accepted data to be decoded")
+		}
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		jitjson.Configure(jitjson.Config{MaxBytes: 16, MaxDepth: 4})
+		defer jitjson.Configure(jitjson.Config{})
+
+		var doc struct {
+			Data *jitjson.JitJSON[any]
+			Any  *jitjson.AnyJitJSON
+		}
+		if err := json.Unmarshal([]byte(`{"Data": `+string(deep)+`}`), &doc); !errors.Is(err, jitjson.ErrTooDeep) {
+			t.Errorf("expected ErrTooDeep, got %v", err)
+		}
+		if _, err := jitjson.NewAny([]byte(`"01234567890123456789"`)); !errors.Is(err, jitjson.ErrTooLarge) {
+			t.Errorf("expected ErrTooLarge, got %v", err)
+		}
+		if err := json.Unmarshal([]byte(`{"Any": `+string(quoted)+`}`), &doc); err != nil {
+			t.Errorf("expected brackets in strings to be ignored, got %v", err)
+		}
+
+		// per-instance limits take precedence
+		jit := jitjson.NewFromBytes[any](nil, jitjson.WithMaxDepth(8))
+		if err := jit.SetBytes(deep); err != nil {
+			t.Errorf("expected instance limit to override MaxDepth, got %v", err)
+		}
+	})
+}
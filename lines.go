@@ -0,0 +1,54 @@
+package jitjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ReadAnyLines returns an iterator over the JSON Lines (NDJSON) values read from r, each
+// held as an AnyJitJSON without being decoded, so logs of mixed shapes can be explored
+// without a schema:
+//
+//	for doc, err := range jitjson.ReadAnyLines(f) {
+//		if err != nil {
+//			log.Print(err)
+//			continue
+//		}
+//		if obj, ok := doc.AsObject(); ok {
+//			level, _ := obj["level"].AsString()
+//			counts[level]++
+//		}
+//	}
+//
+// Blank lines are skipped. A line that is not valid JSON is reported with its line
+// number, and iteration continues with the next line. An error reading r is reported
+// and ends the iteration. Lines are not limited in length.
+func ReadAnyLines(r io.Reader) iter.Seq2[*AnyJitJSON, error] {
+	return func(yield func(*AnyJitJSON, error) bool) {
+		br := bufio.NewReader(r)
+		for n := 1; ; n++ {
+			line, err := br.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				yield(nil, fmt.Errorf("jitjson: reading line %d: %w", n, err))
+				return
+			}
+			if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+				a, aerr := NewAny(trimmed)
+				if aerr != nil {
+					aerr = fmt.Errorf("jitjson: line %d: %w", n, aerr)
+					a = nil
+				}
+				if !yield(a, aerr) {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package jitjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestReadAnyLines(t *testing.T) {
+	input := "{\"level\":\"info\",\"n\":1}\n\n[1,2]\r\n{broken\n\"text\"\n42"
+
+	var types []jitjson.ValueType
+	var errs []error
+	for doc, err := range jitjson.ReadAnyLines(strings.NewReader(input)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		types = append(types, doc.Type())
+	}
+
+	want := []jitjson.ValueType{jitjson.TypeObject, jitjson.TypeArray, jitjson.TypeString, jitjson.TypeNumber}
+	if len(types) != len(want) {
+		t.Fatalf("expected %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("expected %v at %d, got %v", want[i], i, types[i])
+		}
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "line 4") {
+		t.Errorf("expected an error for line 4, got %v", errs)
+	}
+
+	// iteration stops when the loop breaks
+	count := 0
+	for range jitjson.ReadAnyLines(strings.NewReader(input)) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("expected one iteration, got %d", count)
+	}
+
+	errRead := errors.New("read failed")
+	for doc, err := range jitjson.ReadAnyLines(iotest.ErrReader(errRead)) {
+		if doc != nil || !errors.Is(err, errRead) {
+			t.Errorf("expected read error, got %v, %v", doc, err)
+		}
+	}
+}
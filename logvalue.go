@@ -0,0 +1,138 @@
+package jitjson
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// LogMaxBytes limits the JSON included when JitJSON[T] and AnyJitJSON values are
+// logged with log/slog. Longer encodings are cut short and end with an ellipsis, so
+// accidentally logging a large payload does not flood the logs.
+var LogMaxBytes = 1 << 10
+
+// LogRedactKeys names the object members, at any depth, whose values are replaced by
+// "[REDACTED]" when JitJSON[T] and AnyJitJSON values are logged with log/slog. Names are
+// matched case-insensitively.
+var LogRedactKeys []string
+
+// LogValue implements slog.LogValuer. The value is logged as a group holding the size of
+// its encoding and a compact, redacted preview of at most LogMaxBytes, without being
+// decoded. A value without an encoding is marshaled first.
+func (jit *JitJSON[T]) LogValue() slog.Value {
+	data, err := jit.Marshal()
+	if err != nil {
+		return slog.GroupValue(slog.String("error", err.Error()))
+	}
+	return logValue(data)
+}
+
+// LogValue implements slog.LogValuer, logging the value as JitJSON[T] does.
+func (a *AnyJitJSON) LogValue() slog.Value {
+	data, err := a.encoded()
+	if err != nil {
+		return slog.GroupValue(slog.String("error", err.Error()))
+	}
+	return logValue(data)
+}
+
+// logValue returns the log representation of the encoding data.
+func logValue(data []byte) slog.Value {
+	if data == nil {
+		data = []byte("null")
+	}
+	preview, ok := appendRedacted(nil, bytes.TrimSpace(data), LogMaxBytes)
+	if !ok {
+		if len(LogRedactKeys) > 0 {
+			preview = []byte("[invalid json]")
+		} else {
+			preview = data
+		}
+	}
+	return slog.GroupValue(
+		slog.Int("size", len(data)),
+		slog.String("json", truncate(preview, LogMaxBytes)),
+	)
+}
+
+// appendRedacted appends the JSON value v to dst without insignificant whitespace and
+// with the members named by LogRedactKeys redacted, stopping once dst exceeds limit
+// bytes. It reports false if v is not valid JSON as far as it was read.
+func appendRedacted(dst, v []byte, limit int) ([]byte, bool) {
+	if len(v) == 0 {
+		return dst, false
+	}
+	ok := true
+	switch v[0] {
+	case '{':
+		dst = append(dst, '{')
+		first, stopped := true, false
+		valid := splitObject(v, func(key, val []byte) bool {
+			if len(dst) > limit {
+				stopped = true
+				return false
+			}
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = append(dst, key...)
+			dst = append(dst, ':')
+			if name, err := unquote(key); err == nil && redacted(name) {
+				dst = append(dst, `"[REDACTED]"`...)
+				return true
+			}
+			dst, ok = appendRedacted(dst, bytes.TrimSpace(val), limit)
+			return ok
+		})
+		if !ok || !valid && !stopped {
+			return dst, false
+		}
+		dst = append(dst, '}')
+	case '[':
+		elems, valid := splitArray(v)
+		if !valid {
+			return dst, false
+		}
+		dst = append(dst, '[')
+		for i, elem := range elems {
+			if len(dst) > limit {
+				break
+			}
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, ok = appendRedacted(dst, bytes.TrimSpace(elem), limit); !ok {
+				break
+			}
+		}
+		dst = append(dst, ']')
+	default:
+		dst = append(dst, v...)
+	}
+	return dst, ok
+}
+
+// redacted reports whether the member name is listed in LogRedactKeys.
+func redacted(name string) bool {
+	for _, key := range LogRedactKeys {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate returns data as a string of at most max bytes, cut at a UTF-8 character
+// boundary and ending with an ellipsis if it is longer.
+func truncate(data []byte, max int) string {
+	if len(data) <= max {
+		return string(data)
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return string(data[:cut]) + "…"
+}
@@ -0,0 +1,58 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	jit := jitjson.NewFromBytes[map[string]any]([]byte(`{
+		"user": {"name": "John", "Password": "hunter2"},
+		"token": "abc"
+	}`), count)
+	jitjson.LogRedactKeys = []string{"password", "token"}
+	defer func() { jitjson.LogRedactKeys = nil }()
+
+	logger.Info("received", "payload", jit)
+	want := `level=INFO msg=received payload.size=72 payload.json="{\"user\":{\"name\":\"John\",\"Password\":\"[REDACTED]\"},\"token\":\"[REDACTED]\"}"` + "\n"
+	if buf.String() != want {
+		t.Errorf("expected %s, got %s", want, buf.String())
+	}
+	if decodes != 0 {
+		t.Errorf("expected the payload not to be decoded, got %d decodes", decodes)
+	}
+
+	jitjson.LogMaxBytes = 16
+	defer func() { jitjson.LogMaxBytes = 1 << 10 }()
+	doc, err := jitjson.NewAny([]byte(`[` + strings.Repeat(`"ééééé",`, 1000) + `1]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	logger.Info("doc", "doc", doc)
+	if !strings.Contains(buf.String(), `doc.size=13003 doc.json="[\"ééééé\",\"…"`) {
+		t.Errorf("expected a truncated preview, got %s", buf.String())
+	}
+
+	jitjson.LogRedactKeys = nil
+	invalid := jitjson.NewFromBytes[map[string]any]([]byte(`{"a": [1 2]}`))
+	if v := invalid.LogValue().Group(); len(v) != 2 || v[1].Value.String() != `{"a": [1 2]}` {
+		t.Errorf("expected invalid json to be logged as is, got %v", v)
+	}
+}
@@ -0,0 +1,77 @@
+package jitjson
+
+import "sync"
+
+// batchBuffers recycles the buffers of released batches.
+var batchBuffers sync.Pool
+
+// Batch holds a batch of message payloads wrapped as JitJSON[T] values. All payloads
+// are copied into a single buffer owned by the batch, so consumers such as kafka-go or
+// sarama may reuse their message buffers as soon as WrapMessages returns. The buffer
+// is recycled by Release, so Marshal returns copies of the payloads that stay valid
+// after it.
+type Batch[T any] struct {
+	items []*JitJSON[T]
+	buf   []byte
+}
+
+// WrapMessages copies the message payloads msgs into one buffer and wraps each as a
+// JitJSON[T] without decoding it, so event pipelines only pay for decoding the
+// messages they do not filter out. Empty payloads are wrapped as empty values.
+func WrapMessages[T any](msgs [][]byte, opts ...Option) *Batch[T] {
+	var size int
+	for _, msg := range msgs {
+		size += len(msg)
+	}
+
+	var buf []byte
+	if b, ok := batchBuffers.Get().(*[]byte); ok && cap(*b) >= size {
+		buf = (*b)[:0]
+	} else {
+		buf = make([]byte, 0, size)
+	}
+
+	o := newOptions(opts).clone()
+	o.pooled = true
+	nodes := make([]JitJSON[T], len(msgs))
+	items := make([]*JitJSON[T], len(msgs))
+	for i, msg := range msgs {
+		nodes[i].opts = o
+		if len(msg) > 0 {
+			start := len(buf)
+			buf = append(buf, msg...)
+			nodes[i].setData(buf[start:len(buf):len(buf)])
+			recordDeferredUnmarshal(len(msg))
+		}
+		items[i] = &nodes[i]
+	}
+
+	return &Batch[T]{items: items, buf: buf}
+}
+
+// Items returns the wrapped messages in their original order.
+func (b *Batch[T]) Items() []*JitJSON[T] {
+	return b.items
+}
+
+// Len returns the number of wrapped messages.
+func (b *Batch[T]) Len() int {
+	return len(b.items)
+}
+
+// Release releases the batch's decoded values and recycles its buffer for later
+// batches. Neither the batch nor any of its items may be used after Release; the
+// results of their Marshal calls remain valid.
+func (b *Batch[T]) Release() {
+	for _, item := range b.items {
+		item.Release()
+		item.data = nil
+		item.val = nil
+	}
+	if b.buf != nil {
+		buf := b.buf[:0]
+		batchBuffers.Put(&buf)
+	}
+	b.items = nil
+	b.buf = nil
+}
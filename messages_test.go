@@ -0,0 +1,61 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWrapMessages(t *testing.T) {
+	msgs := [][]byte{
+		[]byte(`{"Name":"John","Age":30}`),
+		nil,
+		[]byte(`{"Name":"Jane","Age":25}`),
+	}
+
+	batch := jitjson.WrapMessages[Person](msgs)
+	if batch.Len() != 3 {
+		t.Fatalf("expected 3 items, got %d", batch.Len())
+	}
+
+	// consumers reuse their message buffers
+	copy(msgs[0], `{"Name":"Jack","Age":99}`)
+
+	p, err := batch.Items()[0].Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" || p.Age != 30 {
+		t.Error("expected payload to be copied")
+	}
+
+	p, err = batch.Items()[1].Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != (Person{}) {
+		t.Error("expected empty payload to decode to the zero value")
+	}
+
+	data, err := batch.Items()[2].Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Name":"Jane","Age":25}` {
+		t.Errorf("unexpected payload %s", data)
+	}
+
+	batch.Release()
+	if batch.Len() != 0 {
+		t.Error("expected released batch to be empty")
+	}
+
+	// released buffers are reused by later batches
+	next := jitjson.WrapMessages[Person]([][]byte{[]byte(`{"Name":"Jack","Age":99,"City":"Paris"}`)})
+	if p, _ := next.Items()[0].Unmarshal(); p.Name != "Jack" {
+		t.Error("values do not match")
+	}
+	if string(data) != `{"Name":"Jane","Age":25}` {
+		t.Errorf("expected the marshaled payload to outlive the batch, got %s", data)
+	}
+}
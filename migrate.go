@@ -0,0 +1,42 @@
+package jitjson
+
+// Migrate returns a JitJSON[New] holding the result of converting the value of jit with
+// f, for rolling schema migrations over stored archives:
+//
+//	v2 := jitjson.Migrate(v1, func(old EventV1) (EventV2, error) {
+//		return EventV2{ID: old.ID, At: time.Unix(old.Timestamp, 0)}, nil
+//	})
+//	data, err := v2.Marshal() // decodes v1, converts and encodes only now
+//
+// Nothing is done until the result is first marshaled or unmarshaled: jit is then
+// decoded, through its own cache, and converted with f, and the new value is encoded
+// only if the result is marshaled. An error decoding jit or returned by f is returned
+// by that call, and the conversion is tried again on the next. Setting the result's
+// value or encoding first discards the conversion. Opts configure the result.
+func Migrate[Old, New any](jit *JitJSON[Old], f func(Old) (New, error), opts ...Option) *JitJSON[New] {
+	stats.deferredMarshals.Add(1)
+	return &JitJSON[New]{
+		opts: newOptions(opts),
+		ext: &extension[New]{pending: func() (New, error) {
+			old, err := jit.Unmarshal()
+			if err != nil {
+				var zero New
+				return zero, err
+			}
+			return f(old)
+		}},
+	}
+}
+
+// resolve produces the value of JitJSON[T] with its pending function, which is discarded
+// once it succeeds.
+func (jit *JitJSON[T]) resolve() error {
+	val, err := jit.ext.pending()
+	if err != nil {
+		return err
+	}
+	jit.ext.pending = nil
+	jit.val = &val
+	jit.verr = nil
+	return nil
+}
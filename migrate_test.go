@@ -0,0 +1,60 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestMigrate(t *testing.T) {
+	type PersonV2 struct {
+		FullName string
+		Adult    bool
+	}
+	calls := 0
+	convert := func(p Person) (PersonV2, error) {
+		calls++
+		if p.Name == "" {
+			return PersonV2{}, errors.New("missing name")
+		}
+		return PersonV2{FullName: p.Name, Adult: p.Age >= 18}, nil
+	}
+
+	var decodes int
+	old := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`), jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+	migrated := jitjson.Migrate(old, convert)
+	if decodes != 0 || calls != 0 {
+		t.Errorf("expected nothing to be decoded or converted, got %d decodes and %d calls", decodes, calls)
+	}
+
+	data, err := migrated.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"FullName":"John","Adult":true}` {
+		t.Errorf("unexpected encoding %s", data)
+	}
+	if v, err := migrated.Unmarshal(); err != nil || v.FullName != "John" || calls != 1 {
+		t.Errorf("expected the converted value once, got %+v, %v, %d calls", v, err, calls)
+	}
+
+	failing := jitjson.Migrate(jitjson.NewFromBytes[Person]([]byte(`{"Age":3}`)), convert)
+	if _, err := failing.Unmarshal(); err == nil {
+		t.Error("expected the conversion error")
+	}
+	if _, err := failing.Marshal(); err == nil || calls != 3 {
+		t.Errorf("expected the conversion to be retried, got %v after %d calls", err, calls)
+	}
+
+	broken := jitjson.Migrate(jitjson.NewFromBytes[Person]([]byte(`{"Age":`)), convert)
+	if _, err := broken.Marshal(); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected the decode error, got %v", err)
+	}
+
+	replaced := jitjson.Migrate(jitjson.NewFromBytes[Person]([]byte(`{"Age":`)), convert)
+	replaced.Set(PersonV2{FullName: "Jane"})
+	if v, err := replaced.Unmarshal(); err != nil || v.FullName != "Jane" {
+		t.Errorf("expected Set to discard the conversion, got %+v, %v", v, err)
+	}
+}
@@ -0,0 +1,115 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// MultiDecoder reads a stream of concatenated JSON values, such as {...}{...}{...} with
+// or without whitespace between them, holding each as a JitJSON[T] without decoding it.
+// This framing is used by several vendors for bulk exports and event streams:
+//
+//	dec := jitjson.NewMultiDecoder[Event](resp.Body)
+//	for event, err := range dec.All() {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Only the structure of each value is scanned as it is read. Values exceeding the limits
+// set by WithMaxBytes and WithMaxDepth, or by Configure, are rejected, and no more of the
+// stream than the size limit allows is buffered for a value, so oversized values are
+// rejected before they are read in full. Whitespace before a value counts towards its
+// size limit.
+type MultiDecoder[T any] struct {
+	dec  *json.Decoder
+	r    *capReader
+	opts *options
+	n    int
+	err  error
+}
+
+// NewMultiDecoder returns a MultiDecoder reading from r, whose values are configured
+// by opts.
+func NewMultiDecoder[T any](r io.Reader, opts ...Option) *MultiDecoder[T] {
+	cr := &capReader{r: r}
+	return &MultiDecoder[T]{dec: json.NewDecoder(cr), r: cr, opts: newOptions(opts)}
+}
+
+// Next returns the next value, or io.EOF once the stream ends. After any other error
+// the stream cannot be resumed, and Next keeps returning that error.
+func (d *MultiDecoder[T]) Next() (*JitJSON[T], error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	maxBytes, _ := d.opts.limits()
+	if maxBytes > 0 {
+		// Allow one byte past the limit, so a value of exactly maxBytes can be told
+		// from a longer one.
+		d.r.limit = d.dec.InputOffset() + maxBytes + 1
+	}
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		switch {
+		case errors.Is(err, errCapped):
+			err = &LimitError{Err: fmt.Errorf("%w: value exceeds %d bytes", ErrTooLarge, maxBytes)}
+		case !errors.Is(err, io.EOF):
+			err = classify(err)
+		}
+		if !errors.Is(err, io.EOF) {
+			err = fmt.Errorf("jitjson: value %d: %w", d.n, err)
+		}
+		d.err = err
+		return nil, err
+	}
+	if err := d.opts.checkLimits(raw); err != nil {
+		d.err = fmt.Errorf("jitjson: value %d: %w", d.n, err)
+		return nil, d.err
+	}
+	d.n++
+	return newFromBytes[T](raw, d.opts), nil
+}
+
+// errCapped is returned by capReader once its limit is reached.
+var errCapped = errors.New("jitjson: read limit reached")
+
+// capReader reads from r until limit bytes have been read in total, if limit is set.
+type capReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (c *capReader) Read(p []byte) (int, error) {
+	if c.limit > 0 {
+		if c.n >= c.limit {
+			return 0, errCapped
+		}
+		if rem := c.limit - c.n; int64(len(p)) > rem {
+			p = p[:rem]
+		}
+	}
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// All returns an iterator over the remaining values. An error other than the end of
+// the stream is yielded once and ends the iteration.
+func (d *MultiDecoder[T]) All() iter.Seq2[*JitJSON[T], error] {
+	return func(yield func(*JitJSON[T], error) bool) {
+		for {
+			jit, err := d.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(jit, err) || err != nil {
+				return
+			}
+		}
+	}
+}
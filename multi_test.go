@@ -0,0 +1,80 @@
+package jitjson_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestMultiDecoder(t *testing.T) {
+	var decodes int
+	dec := jitjson.NewMultiDecoder[Person](strings.NewReader(`{"Name":"A"}{"Name":"B"} {"Name":"C"}`+"\n"),
+		jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+
+	var items []*jitjson.JitJSON[Person]
+	for jit, err := range dec.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, jit)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no values to be decoded, got %d decodes", decodes)
+	}
+	var names string
+	for _, jit := range items {
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names += p.Name
+	}
+	if names != "ABC" {
+		t.Errorf("expected ABC, got %q", names)
+	}
+	if _, err := dec.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	dec = jitjson.NewMultiDecoder[Person](strings.NewReader(`{"Name":"A"}{"Name":}{"Name":"C"}`))
+	var n int
+	var lastErr error
+	for _, err := range dec.All() {
+		n++
+		lastErr = err
+	}
+	if n != 2 || !jitjson.IsSyntaxError(lastErr) || !strings.Contains(lastErr.Error(), "value 1") {
+		t.Errorf("expected one value and then a syntax error for value 1, got %d, %v", n, lastErr)
+	}
+	if _, err := dec.Next(); err != lastErr {
+		t.Errorf("expected the error to be sticky, got %v", err)
+	}
+}
+
+func TestMultiDecoderLimits(t *testing.T) {
+	small := `{"Name":"A"}`
+	r := strings.NewReader(small + small + `{"Name":"` + strings.Repeat("x", 1<<20))
+	dec := jitjson.NewMultiDecoder[Person](r, jitjson.WithMaxBytes(int64(len(small))))
+	for i := 0; i < 2; i++ {
+		if _, err := dec.Next(); err != nil {
+			t.Fatalf("expected value %d within the limit, got %v", i, err)
+		}
+	}
+	if _, err := dec.Next(); !errors.Is(err, jitjson.ErrTooLarge) || !jitjson.IsLimitError(err) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if read := r.Size() - int64(r.Len()); read > 4*int64(len(small)) {
+		t.Errorf("expected the oversized value not to be read in full, read %d bytes", read)
+	}
+
+	deep := jitjson.NewMultiDecoder[any](strings.NewReader(`[[1]] [[[1]]]`), jitjson.WithMaxDepth(2))
+	if _, err := deep.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deep.Next(); !errors.Is(err, jitjson.ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package jitjson
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Null[T] is a JitJSON[T] that may be null, analogous to sql.Null[T]. Valid is false
+// when the value is null, which is the zero value, and the value is decoded lazily
+// with Get otherwise. Null[T] marshals to null when it is not valid, and it can be used
+// by value in structs, where copies share the decoded value:
+//
+//	type User struct {
+//		Nickname jitjson.Null[string]  `json:"nickname"`
+//		Address  jitjson.Null[Address] `json:"address"`
+//	}
+type Null[T any] struct {
+	jit   *JitJSON[T]
+	Valid bool
+}
+
+// NewNull creates a valid Null[T] from a value.
+func NewNull[T any](val T, opts ...Option) Null[T] {
+	return Null[T]{jit: New(val, opts...), Valid: true}
+}
+
+// Get returns the value of Null[T], decoding it on first use. The zero value of T is
+// returned if Null[T] is not valid.
+func (n Null[T]) Get() (T, error) {
+	if !n.Valid || n.jit == nil {
+		var zero T
+		return zero, nil
+	}
+	return n.jit.Unmarshal()
+}
+
+// Set Null[T] to a valid value. Copies of Null[T] made before are not affected.
+func (n *Null[T]) Set(val T) {
+	n.jit = New(val)
+	n.Valid = true
+}
+
+// SetNull sets Null[T] to null.
+func (n *Null[T]) SetNull() {
+	n.jit = nil
+	n.Valid = false
+}
+
+// IsZero reports whether Null[T] is null, for the omitzero struct tag option.
+func (n Null[T]) IsZero() bool {
+	return !n.Valid
+}
+
+// MarshalJSON marshals the value of Null[T], or null if it is not valid. Undecoded
+// data is returned as is.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	if n.jit == nil {
+		var zero T
+		return json.Marshal(zero)
+	}
+	return n.jit.Marshal()
+}
+
+// UnmarshalJSON stores JSON data to be decoded by Get, or sets Null[T] to null if data
+// is null.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if scanLiteral(data, skipSpace(data, 0), "null") > 0 {
+		n.SetNull()
+		return nil
+	}
+	jit := new(JitJSON[T])
+	if err := jit.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	n.jit = jit
+	n.Valid = true
+	return nil
+}
+
+// Scan implements sql.Scanner, storing the bytes of json and jsonb columns as with
+// JitJSON[T]. A NULL column sets Null[T] to null.
+func (n *Null[T]) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		n.SetNull()
+		return nil
+	}
+	return n.UnmarshalJSON(data)
+}
+
+// Value implements driver.Valuer, writing NULL if Null[T] is not valid.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.MarshalJSON()
+}
@@ -0,0 +1,79 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type Profile struct {
+	Nickname jitjson.Null[string] `json:"nickname"`
+	Home     jitjson.Null[Person] `json:"home"`
+	Score    jitjson.Null[int]    `json:"score,omitzero"`
+}
+
+func TestNull(t *testing.T) {
+	var p Profile
+	if err := json.Unmarshal([]byte(`{"nickname": null, "home": {"Name": "John", "Age": 30}}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Nickname.Valid || !p.Home.Valid || p.Score.Valid {
+		t.Errorf("unexpected validity %v %v %v", p.Nickname.Valid, p.Home.Valid, p.Score.Valid)
+	}
+
+	// a member that does not decode into T fails only when it is read
+	var q Profile
+	if err := json.Unmarshal([]byte(`{"home": {"Age": "thirty"}}`), &q); err != nil {
+		t.Errorf("expected no decoding, got %v", err)
+	}
+	if _, err := q.Home.Get(); err == nil {
+		t.Error("expected the decode error on Get")
+	}
+
+	home, err := p.Home.Get()
+	if err != nil || home.Name != "John" {
+		t.Errorf("unexpected home %v, %v", home, err)
+	}
+	nick, err := p.Nickname.Get()
+	if err != nil || nick != "" {
+		t.Errorf("expected the zero value, got %q, %v", nick, err)
+	}
+
+	p.Nickname.Set("johnny")
+	p.Home.SetNull()
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"nickname":"johnny","home":null}`; string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+
+	p.Score = jitjson.NewNull(7)
+	out, err = json.Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"nickname":"johnny","home":null,"score":7}`; string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+}
+
+func TestNullSQL(t *testing.T) {
+	var n jitjson.Null[Person]
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Fatalf("expected NULL to scan as invalid, got %v, %v", n.Valid, err)
+	}
+	if v, err := n.Value(); err != nil || v != nil {
+		t.Errorf("expected a NULL value, got %v, %v", v, err)
+	}
+
+	if err := n.Scan([]byte(`{"Name": "Jane"}`)); err != nil || !n.Valid {
+		t.Fatalf("unexpected scan %v, %v", n.Valid, err)
+	}
+	v, err := n.Value()
+	if err != nil || string(v.([]byte)) != `{"Name": "Jane"}` {
+		t.Errorf("expected the scanned bytes, got %s, %v", v, err)
+	}
+}
@@ -0,0 +1,167 @@
+package jitjson
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// WithPreserveNumbers makes Marshal keep the original number literals of a value that
+// was decoded from JSON and later replaced with Set, such as 1.0, 1e2 or 2.50. Where a
+// number in the new encoding is exactly equal to the number at the same path in the
+// original data, the original literal is spliced in instead of Go's formatting of it.
+// It applies to JSON parsers only.
+func WithPreserveNumbers() Option {
+	return func(o *options) {
+		o.preserveNumbers = true
+	}
+}
+
+// spliceNumbers returns enc with the number literals that equal those at the same path
+// in orig replaced by the literals of orig. If either is malformed, enc is returned.
+func spliceNumbers(enc, orig []byte) []byte {
+	out, ok := appendSpliced(make([]byte, 0, len(enc)), bytes.TrimSpace(enc), bytes.TrimSpace(orig))
+	if !ok {
+		return enc
+	}
+	return out
+}
+
+// appendSpliced appends enc to dst, splicing in number literals from orig.
+func appendSpliced(dst, enc, orig []byte) ([]byte, bool) {
+	if len(enc) == 0 {
+		return nil, false
+	}
+	if len(orig) == 0 {
+		return append(dst, enc...), true
+	}
+	switch enc[0] {
+	case '{':
+		if orig[0] != '{' {
+			return append(dst, enc...), true
+		}
+		members := map[string][]byte{}
+		if !splitObject(orig, func(key, val []byte) bool {
+			k, err := unquote(key)
+			members[k] = val
+			return err == nil
+		}) {
+			return nil, false
+		}
+
+		dst = append(dst, '{')
+		first, ok := true, true
+		valid := splitObject(enc, func(key, val []byte) bool {
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			k, err := unquote(key)
+			if err != nil {
+				return false
+			}
+			dst = append(append(dst, key...), ':')
+			dst, ok = appendSpliced(dst, val, members[k])
+			return ok
+		})
+		return append(dst, '}'), valid
+	case '[':
+		if orig[0] != '[' {
+			return append(dst, enc...), true
+		}
+		elems, ok := splitArray(enc)
+		origElems, origOk := splitArray(orig)
+		if !ok || !origOk {
+			return nil, false
+		}
+		dst = append(dst, '[')
+		for i, elem := range elems {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var o []byte
+			if i < len(origElems) {
+				o = origElems[i]
+			}
+			if dst, ok = appendSpliced(dst, elem, o); !ok {
+				return nil, false
+			}
+		}
+		return append(dst, ']'), true
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		if sameNumber(enc, orig) {
+			return append(dst, orig...), true
+		}
+	}
+	return append(dst, enc...), true
+}
+
+// sameNumber reports whether the encoded number a denotes exactly the same value as the
+// original literal b, such as 1.5 and 1.50, or 100 and 1e2. Numbers that merely round
+// to the same float64 differ, so changed values are never replaced.
+func sameNumber(a, b []byte) bool {
+	if !isNumberLiteral(b) {
+		return false
+	}
+	if bytes.Equal(a, b) {
+		return true
+	}
+	x, ok := decimalOf(a)
+	if !ok {
+		return false
+	}
+	y, ok := decimalOf(b)
+	return ok && x == y
+}
+
+// decimal is a number literal normalized to sign, significant digits and exponent,
+// so that equal numbers have equal decimals. Zero has no digits and is not negative.
+type decimal struct {
+	neg    bool
+	digits string
+	exp    int
+}
+
+// decimalOf normalizes the JSON number literal data, reporting false if it is not one
+// or its exponent is out of range.
+func decimalOf(data []byte) (decimal, bool) {
+	if !isNumberLiteral(data) {
+		return decimal{}, false
+	}
+	var d decimal
+	s := string(data)
+	if s[0] == '-' {
+		d.neg, s = true, s[1:]
+	}
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(strings.TrimPrefix(s[i+1:], "+"))
+		if err != nil || exp > 1<<30 || exp < -1<<30 {
+			return decimal{}, false
+		}
+		d.exp, s = exp, s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		d.exp -= len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+	s = strings.TrimLeft(s, "0")
+	for len(s) > 0 && s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+		d.exp++
+	}
+	if s == "" {
+		return decimal{}, true
+	}
+	d.digits = s
+	return d, true
+}
+
+// isNumberLiteral reports whether data is a valid JSON number.
+func isNumberLiteral(data []byte) bool {
+	return len(data) > 0 && scanNumber(data, 0) == len(data)
+}
+
+// isIntegerLiteral reports whether the number literal data has no fraction or exponent.
+func isIntegerLiteral(data []byte) bool {
+	return bytes.IndexAny(data, ".eE") < 0
+}
@@ -0,0 +1,54 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWithPreserveNumbers(t *testing.T) {
+	type Doc struct {
+		ID     int64     `json:"id"`
+		Price  float64   `json:"price"`
+		Scale  float64   `json:"scale"`
+		Big    uint64    `json:"big"`
+		Values []float64 `json:"values"`
+		Name   string    `json:"name"`
+	}
+	input := `{"id": 1234567890123456789, "price": 1.0, "scale": 1e2, "big": 12345678901234567890, "values": [2.50, 3.0], "name": "a"}`
+
+	jit := jitjson.NewFromBytes[Doc]([]byte(input), jitjson.WithPreserveNumbers())
+	doc, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Name = "b"
+	doc.Values[1] = 4
+	jit.Set(doc)
+
+	data, err := jit.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":1234567890123456789,"price":1.0,"scale":1e2,"big":12345678901234567890,"values":[2.50,4],"name":"b"}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	doc.ID = 1234567890123456800
+	doc.Price = 1.0000000000000002
+	jit.Set(doc)
+	data, _ = jit.Marshal()
+	want = `{"id":1234567890123456800,"price":1.0000000000000002,"scale":1e2,"big":12345678901234567890,"values":[2.50,4],"name":"b"}`
+	if string(data) != want {
+		t.Errorf("expected modified numbers to be encoded, got %s", data)
+	}
+
+	plain := jitjson.NewFromBytes[Doc]([]byte(input))
+	doc, _ = plain.Unmarshal()
+	plain.Set(doc)
+	data, _ = plain.Marshal()
+	if string(data) == want {
+		t.Error("expected Go number formatting without WithPreserveNumbers")
+	}
+}
@@ -0,0 +1,87 @@
+package jitjson
+
+import (
+	"sync"
+	"time"
+)
+
+// Option configures the behaviour of a JitJSON[T]. Options are passed to New and
+// NewFromBytes, or applied to an existing value with SetOptions.
+type Option func(*options)
+
+// options holds the per-instance configuration of a JitJSON[T]. A nil *options is
+// valid and represents the defaults, so zero-value JitJSON[T] values stay small.
+type options struct {
+	pool            *sync.Pool
+	typePool        bool
+	parser          Parser
+	parserName      string
+	useNumber       bool
+	maxBytes        int64
+	maxDepth        int
+	maxRequestBytes int64
+	stripBOM        bool
+	validUTF8       bool
+	canonical       bool
+	rejectTrailing  bool
+	preserveNumbers bool
+	validator       StructValidator
+	onMarshal       []Hook
+	onUnmarshal     []Hook
+	ttl             time.Duration
+	transform       BytesTransform
+	retention       Retention
+	copyData        bool
+	pooled          bool
+	timeFormat      TimeFormat
+	eagerBelow      int
+	budget          *Budget
+	adaptive        *Adaptive
+	limiter         *ParseLimiter
+}
+
+// newOptions applies opts to the defaults set by Configure, returning the shared
+// defaults, or nil when none are set, when there are no opts.
+func newOptions(opts []Option) *options {
+	if len(opts) == 0 {
+		return defaults.Load()
+	}
+	o := defaults.Load().clone()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// SetOptions applies opts to JitJSON[T], for values that were created by json.Unmarshal
+// rather than New or NewFromBytes. Other values sharing the options of JitJSON[T], such
+// as the items of a Batch, are not affected.
+func (jit *JitJSON[T]) SetOptions(opts ...Option) {
+	if len(opts) == 0 {
+		return
+	}
+	if jit.opts == nil {
+		jit.opts = defaults.Load()
+	}
+	o := jit.opts.clone()
+	for _, opt := range opts {
+		opt(o)
+	}
+	jit.opts = o
+}
+
+// accept prepares data to be stored, removing any byte order mark and checking it
+// against the configured UTF-8, trailing data, and size and depth requirements.
+func (o *options) accept(data []byte) ([]byte, error) {
+	data = o.trimBOM(data)
+	if err := o.checkUTF8(data); err != nil {
+		return nil, err
+	}
+	if err := o.checkTrailing(data); err != nil {
+		return nil, err
+	}
+	if err := o.checkLimits(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
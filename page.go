@@ -0,0 +1,54 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Page reads the JSON array from r and returns the limit elements starting at offset,
+// each held as a JitJSON[T] configured by opts without being decoded, for paging APIs
+// over large stored documents:
+//
+//	f, err := os.Open("export.json")
+//	items, err := jitjson.Page[Order](f, 200, 50)
+//
+// Elements before the window are skipped one at a time without being kept, and reading
+// stops once the window is full, so memory use depends only on the window. Fewer than
+// limit elements are returned when the array ends first. Elements exceeding the limits
+// set by Configure are rejected.
+func Page[T any](r io.Reader, offset, limit int, opts ...Option) ([]*JitJSON[T], error) {
+	if offset < 0 || limit < 0 {
+		return nil, errors.New("jitjson: negative page offset or limit")
+	}
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('[') {
+		return nil, errors.New("jitjson: expected a json array")
+	}
+
+	var skip json.RawMessage
+	for i := 0; i < offset && dec.More(); i++ {
+		if err := dec.Decode(&skip); err != nil {
+			return nil, fmt.Errorf("jitjson: element %d: %w", i, err)
+		}
+		skip = skip[:0]
+	}
+
+	items := []*JitJSON[T]{}
+	for i := offset; len(items) < limit && dec.More(); i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("jitjson: element %d: %w", i, err)
+		}
+		if err := (*options)(nil).checkLimits(raw); err != nil {
+			return nil, fmt.Errorf("jitjson: element %d: %w", i, err)
+		}
+		items = append(items, NewFromBytes[T](raw, opts...))
+	}
+	return items, nil
+}
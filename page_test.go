@@ -0,0 +1,64 @@
+package jitjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestPage(t *testing.T) {
+	data := `[{"Name": "A"}, {"Name": "B"}, {"Name": "C"}, {"Name": "D"}, {"Name": "E"}]`
+
+	names := func(items []*jitjson.JitJSON[Person]) string {
+		var s string
+		for _, item := range items {
+			p, err := item.Unmarshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s += p.Name
+		}
+		return s
+	}
+
+	for _, tt := range []struct {
+		offset, limit int
+		want          string
+	}{
+		{0, 2, "AB"},
+		{2, 2, "CD"},
+		{4, 2, "E"},
+		{5, 2, ""},
+		{9, 2, ""},
+		{1, 0, ""},
+	} {
+		items, err := jitjson.Page[Person](strings.NewReader(data), tt.offset, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(items); got != tt.want {
+			t.Errorf("offset %d, limit %d: expected %q, got %q", tt.offset, tt.limit, tt.want, got)
+		}
+	}
+
+	// reading stops at the end of the window, before the malformed tail
+	items, err := jitjson.Page[Person](strings.NewReader(`[{"Name": "A"}, {"Name": "B"}, {"Na`), 1, 1)
+	if err != nil || names(items) != "B" {
+		t.Errorf("expected B, got %v", err)
+	}
+
+	if _, err := jitjson.Page[Person](strings.NewReader(`{"Name": "A"}`), 0, 1); err == nil {
+		t.Error("expected an error for a non-array document")
+	}
+	if _, err := jitjson.Page[Person](strings.NewReader(data), -1, 1); err == nil {
+		t.Error("expected an error for a negative offset")
+	}
+
+	jitjson.Configure(jitjson.Config{MaxDepth: 1})
+	defer jitjson.Configure(jitjson.Config{})
+	if _, err := jitjson.Page[Person](strings.NewReader(`[{"Name": {"x": 1}}]`), 0, 1); !errors.Is(err, jitjson.ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package jitjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultParser is the name of the parser used when none is configured, backed by
+// the standard library's encoding/json package.
+const DefaultParser = "encoding/json"
+
+// Parser is an encoding backend used by JitJSON[T] to perform deferred marshaling and
+// unmarshaling. Implementations must be safe for concurrent use.
+type Parser interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdParser implements Parser with encoding/json.
+type stdParser struct{}
+
+func (stdParser) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (stdParser) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// numberParser implements Parser with encoding/json, decoding numbers in interface
+// values as json.Number.
+type numberParser struct{ stdParser }
+
+func (numberParser) Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &SyntaxError{Err: errors.New("invalid character after top-level value")}
+	}
+	return nil
+}
+
+var parsers = struct {
+	sync.RWMutex
+	m map[string]Parser
+}{
+	m: map[string]Parser{DefaultParser: stdParser{}},
+}
+
+// RegisterParser makes a Parser available by name to WithParser and Benchmark.
+// Registering a name twice replaces the earlier parser. It panics if p is nil.
+func RegisterParser(name string, p Parser) {
+	if p == nil {
+		panic("jitjson: RegisterParser parser is nil")
+	}
+	parsers.Lock()
+	defer parsers.Unlock()
+	parsers.m[name] = p
+}
+
+// LookupParser returns the Parser registered under name.
+func LookupParser(name string) (Parser, bool) {
+	parsers.RLock()
+	defer parsers.RUnlock()
+	p, ok := parsers.m[name]
+	return p, ok
+}
+
+// Parsers returns the names of all registered parsers in sorted order.
+func Parsers() []string {
+	parsers.RLock()
+	defer parsers.RUnlock()
+	names := make([]string, 0, len(parsers.m))
+	for name := range parsers.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithParser makes JitJSON[T] use the parser registered under name. If no parser is
+// registered under name when the value is encoded or decoded, Marshal and Unmarshal
+// return an error naming it. An empty name means DefaultParser.
+func WithParser(name string) Option {
+	return func(o *options) {
+		o.parser, _ = LookupParser(name)
+		o.parserName = name
+	}
+}
+
+// missingParser implements Parser for a name with no registered parser, failing every
+// call.
+type missingParser string
+
+func (p missingParser) Marshal(v any) ([]byte, error)      { return nil, p.err() }
+func (p missingParser) Unmarshal(data []byte, v any) error { return p.err() }
+
+func (p missingParser) err() error {
+	return fmt.Errorf("jitjson: parser %q is not registered", string(p))
+}
+
+// unregisterParser removes the parser registered under name, for tests.
+func unregisterParser(name string) {
+	parsers.Lock()
+	defer parsers.Unlock()
+	delete(parsers.m, name)
+}
+
+// WithUseNumber makes Unmarshal decode numbers held in interface values as json.Number
+// rather than float64, preserving integers beyond 2^53 such as 64-bit IDs. It applies
+// to DefaultParser only.
+func WithUseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
+// codec returns the Parser configured by the options. A parser not registered when the
+// options were built is looked up again, and is reported by missingParser if it is
+// still not registered.
+func (o *options) codec() Parser {
+	switch {
+	case o == nil:
+		return stdParser{}
+	case o.useNumber && o.codecName() == DefaultParser:
+		return numberParser{}
+	case o.parser != nil:
+		return o.parser
+	case o.codecName() == DefaultParser:
+		return stdParser{}
+	}
+	if p, ok := LookupParser(o.parserName); ok {
+		return p
+	}
+	return missingParser(o.parserName)
+}
+
+// codecName returns the name of the Parser configured by the options.
+func (o *options) codecName() string {
+	if o == nil || o.parserName == "" {
+		return DefaultParser
+	}
+	return o.parserName
+}
+
+// stdCodec reports whether the configured parser is encoding/json, whose encodings the
+// options rewriting JSON, such as WithCanonical and WithTimeFormat, apply to.
+func (o *options) stdCodec() bool {
+	return o.codecName() == DefaultParser
+}
+
+// convertsTimes reports whether time.Time values are converted with a TimeFormat.
+func (o *options) convertsTimes() bool {
+	return o != nil && o.timeFormat != nil && o.stdCodec()
+}
+
+// canonicalizes reports whether Marshal returns canonical encodings, as set by
+// WithCanonical.
+func (o *options) canonicalizes() bool {
+	return o != nil && o.canonical && o.stdCodec()
+}
+
+// clock returns the time an encoding or decoding starts if its latency is recorded by
+// EnableParserTimings or observed by hooks, and the zero time otherwise, so the clock is
+// only read when needed.
+func (o *options) clock(op string) time.Time {
+	if !timings.Load() && !o.observed(op) {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// encode marshals v with the configured parser, recording the latency of the call
+// and reporting it to any hooks with ctx.
+func (o *options) encode(ctx context.Context, v any) ([]byte, error) {
+	start := o.clock(OpMarshal)
+	data, err := o.codec().Marshal(v)
+	if err == nil && o.convertsTimes() {
+		data, err = formatTimes(o.timeFormat, data, v)
+	}
+	if !start.IsZero() {
+		elapsed := time.Since(start)
+		if timings.Load() {
+			c := countersFor(o.codecName())
+			c.marshals.Add(1)
+			c.marshalNanos.Add(uint64(elapsed))
+		}
+		o.notify(ctx, OpMarshal, v, len(data), elapsed, err)
+	}
+	return data, err
+}
+
+// decode unmarshals data into v with the configured parser, recording the latency of the call
+// and reporting it to any hooks with ctx. Errors locating a position in data are returned
+// as a *ParseError, located in data even when times were converted before decoding.
+func (o *options) decode(ctx context.Context, data []byte, v any) error {
+	if err := o.charge(data); err != nil {
+		return err
+	}
+	release, err := o.acquire(ctx, data)
+	if err != nil {
+		return err
+	}
+	if release != nil {
+		defer release()
+	}
+	start := o.clock(OpUnmarshal)
+	target, store, err := concreteTarget(data, v)
+	src := data
+	var spans []timeSpan
+	if err == nil && o.convertsTimes() {
+		var converted []byte
+		if converted, spans, err = parseTimes(o.timeFormat, data, target); err == nil {
+			src = converted
+		}
+	}
+	if err == nil {
+		err = o.codec().Unmarshal(src, target)
+	}
+	if err == nil && store != nil {
+		store()
+	}
+	if err != nil {
+		err = newParseError(data, classify(err), spans)
+	}
+	if !start.IsZero() {
+		elapsed := time.Since(start)
+		if timings.Load() {
+			c := countersFor(o.codecName())
+			c.unmarshals.Add(1)
+			c.unmarshalNanos.Add(uint64(elapsed))
+		}
+		o.notify(ctx, OpUnmarshal, v, len(data), elapsed, err)
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// countingParser wraps encoding/json and counts calls.
+type countingParser struct {
+	marshals, unmarshals int
+}
+
+func (p *countingParser) Marshal(v any) ([]byte, error) {
+	p.marshals++
+	return json.Marshal(v)
+}
+
+func (p *countingParser) Unmarshal(data []byte, v any) error {
+	p.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+// registerParser registers p under name for the duration of the test.
+func registerParser(t *testing.T, name string, p jitjson.Parser) {
+	t.Helper()
+	jitjson.RegisterParser(name, p)
+	t.Cleanup(func() { jitjson.UnregisterParser(name) })
+}
+
+func TestWithParser(t *testing.T) {
+	parser := &countingParser{}
+	registerParser(t, "counting", parser)
+
+	if !slices.Contains(jitjson.Parsers(), "counting") {
+		t.Error("expected registered parser to be listed")
+	}
+
+	jit := jitjson.New(Person{Name: "John"}, jitjson.WithParser("counting"))
+	if _, err := jit.Marshal(); err != nil {
+		t.Fatal(err)
+	}
+
+	jit = jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), jitjson.WithParser("counting"))
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+
+	if parser.marshals != 1 || parser.unmarshals != 1 {
+		t.Errorf("expected parser to be used, got %d marshals and %d unmarshals", parser.marshals, parser.unmarshals)
+	}
+
+	if _, ok := jitjson.LookupParser("missing"); ok {
+		t.Error("expected missing parser lookup to fail")
+	}
+	if _, err := jitjson.New(Person{}, jitjson.WithParser("missing")).Marshal(); err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Errorf("expected an error naming the missing parser, got %v", err)
+	}
+	if _, err := jitjson.NewFromBytes[Person]([]byte(`{}`), jitjson.WithParser("missing")).Unmarshal(); err == nil {
+		t.Error("expected an error decoding with a missing parser")
+	}
+}
+
+func TestWithUseNumber(t *testing.T) {
+	jsonData := []byte(`{"id": 9007199254740993, "tags": [1.5]}`)
+
+	jit := jitjson.NewFromBytes[map[string]any](jsonData)
+	m, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["id"].(float64); !ok {
+		t.Fatalf("expected float64 by default, got %T", m["id"])
+	}
+
+	for _, opts := range [][]jitjson.Option{
+		{jitjson.WithUseNumber()},
+		{jitjson.WithParser(jitjson.DefaultParser), jitjson.WithUseNumber()},
+	} {
+		jit = jitjson.NewFromBytes[map[string]any](jsonData, opts...)
+		m, err = jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, ok := m["id"].(json.Number); !ok || id.String() != "9007199254740993" {
+			t.Errorf("expected json.Number 9007199254740993, got %T %v", m["id"], m["id"])
+		}
+		if tags := m["tags"].([]any); tags[0] != json.Number("1.5") {
+			t.Errorf("expected nested json.Number, got %T", tags[0])
+		}
+	}
+
+	bad := jitjson.NewFromBytes[map[string]any]([]byte(`{} {}`), jitjson.WithUseNumber())
+	if _, err := bad.Unmarshal(); err == nil {
+		t.Error("expected error for trailing data")
+	}
+}
@@ -0,0 +1,280 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// FieldError reports a member that UnmarshalPartial could not decode.
+type FieldError struct {
+	// Path locates the member within the document, such as $.address.zip.
+	Path string
+	// Err is the error decoding the member.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return "jitjson: " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalPartial decodes JitJSON[T] like Unmarshal, but when members of a struct do
+// not fit their fields, such as a string where a number is expected or a number that
+// overflows, it decodes every other member and reports each failure rather than
+// stopping at the first, for tolerant ingestion of loosely specified feeds:
+//
+//	order, errs := jit.UnmarshalPartial()
+//	for _, e := range errs {
+//		log.Printf("ignoring %s: %v", e.Path, e.Err)
+//	}
+//
+// Fields whose members fail are left as decoded as far as possible, usually the zero
+// value. Nested structs are decoded member by member in the same way. Data that is not
+// well-formed, or does not decode into T at all, is reported as a single FieldError
+// with the path $. The value is cached as by Unmarshal only if there are no errors; the
+// member-by-member decoding uses encoding/json whichever parser is configured, with the
+// times within each member converted as configured by WithTimeFormat.
+func (jit *JitJSON[T]) UnmarshalPartial() (T, []FieldError) {
+	val, err := jit.Unmarshal()
+	if err == nil {
+		return val, nil
+	}
+	if !IsTypeMismatch(err) || jit.data == nil {
+		return val, []FieldError{{Path: "$", Err: err}}
+	}
+	data, lerr := jit.opts.load(jit.data)
+	if lerr != nil {
+		return val, []FieldError{{Path: "$", Err: lerr}}
+	}
+
+	var partial T
+	rv := reflect.ValueOf(&partial).Elem()
+	if rv.Kind() != reflect.Struct {
+		return val, []FieldError{{Path: "$", Err: err}}
+	}
+	var f TimeFormat
+	if jit.opts.convertsTimes() {
+		f = jit.opts.timeFormat
+	}
+	if err := jit.opts.charge(data); err != nil {
+		return val, []FieldError{{Path: "$", Err: err}}
+	}
+	var errs []FieldError
+	decodePartial(data, rv, "$", f, &errs)
+	return partial, errs
+}
+
+// decodePartial decodes the members of the JSON object data into the fields of the
+// struct rv one at a time, appending an error for each member that fails. The times
+// within each member are converted from f first, if it is not nil.
+func decodePartial(data []byte, rv reflect.Value, path string, f TimeFormat, errs *[]FieldError) {
+	ok := splitObject(data, func(key, val []byte) bool {
+		name, err := unquote(key)
+		if err != nil {
+			return false
+		}
+		field, ok := structField(rv, name)
+		if !ok {
+			return true
+		}
+		ptr := field.Addr().Interface()
+		src := val
+		if f != nil {
+			src, _, err = parseTimes(f, val, ptr)
+		}
+		if err == nil {
+			err = json.Unmarshal(src, ptr)
+		}
+		if err != nil {
+			fieldPath := path + "." + name
+			if field.Kind() == reflect.Struct && isContainer(val, '{', '}') {
+				decodePartial(val, field, fieldPath, f, errs)
+			} else {
+				*errs = append(*errs, FieldError{Path: fieldPath, Err: classify(err)})
+			}
+		}
+		return true
+	})
+	if !ok {
+		*errs = append(*errs, FieldError{Path: path, Err: &SyntaxError{Err: errors.New("invalid json object")}})
+	}
+}
+
+// structField returns the field of the struct rv that encoding/json decodes the member
+// name into, as located by fieldIndex, allocating the nil embedded struct pointers
+// along the way as encoding/json does.
+func structField(rv reflect.Value, name string) (reflect.Value, bool) {
+	index, ok := fieldIndex(rv.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// fieldIndex returns the index sequence of the field of the struct type t that
+// encoding/json decodes the member name into, preferring an exact match of the field's
+// JSON name over a case-insensitive one.
+func fieldIndex(t reflect.Type, name string) ([]int, bool) {
+	fields := jsonFields(t)
+	for _, f := range fields {
+		if f.name == name {
+			return f.index, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f.index, true
+		}
+	}
+	return nil, false
+}
+
+// jsonField is a field of a struct type as encoding/json sees it.
+type jsonField struct {
+	name   string
+	tagged bool
+	index  []int
+	typ    reflect.Type
+}
+
+// fieldCache caches jsonFields by reflect.Type.
+var fieldCache sync.Map
+
+// jsonFields returns the fields of the struct type t that encoding/json encodes and
+// decodes, in index order. Fields of embedded structs are promoted following the rules
+// of encoding/json: of the fields sharing a name, the shallowest wins, then the one
+// with a JSON tag; if that leaves more than one, none of them is used.
+func jsonFields(t reflect.Type) []jsonField {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]jsonField)
+	}
+
+	var fields []jsonField
+	var current []jsonField
+	next := []jsonField{{typ: t}}
+	var count, nextCount map[reflect.Type]int
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				if !validTag(name) {
+					name = ""
+				}
+				index := append(append([]int(nil), f.index...), i)
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := jsonField{name: name, tagged: name != "", index: index, typ: ft}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					fields = append(fields, field)
+					if count[f.typ] > 1 {
+						// The struct is embedded more than once at this depth, so its
+						// fields conflict with themselves and are dropped below.
+						fields = append(fields, field)
+					}
+					continue
+				}
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, jsonField{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		switch {
+		case a.name != b.name:
+			return a.name < b.name
+		case len(a.index) != len(b.index):
+			return len(a.index) < len(b.index)
+		case a.tagged != b.tagged:
+			return a.tagged
+		}
+		return slices.Compare(a.index, b.index) < 0
+	})
+	out := fields[:0]
+	for i := 0; i < len(fields); {
+		n := 1
+		for i+n < len(fields) && fields[i+n].name == fields[i].name {
+			n++
+		}
+		// The fields are ordered by depth and then tag, so the first dominates the
+		// others unless the second is as shallow and as tagged.
+		if n == 1 || len(fields[i].index) != len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			out = append(out, fields[i])
+		}
+		i += n
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return slices.Compare(out[i].index, out[j].index) < 0
+	})
+
+	f, _ := fieldCache.LoadOrStore(t, out)
+	return f.([]jsonField)
+}
+
+// validTag reports whether s is a JSON name encoding/json accepts in a struct tag.
+func validTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
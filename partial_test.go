@@ -0,0 +1,91 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestUnmarshalPartial(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  int
+	}
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type Record struct {
+		Base
+		Name    string `json:"name"`
+		Count   int8
+		Tags    []string
+		Address Address
+		Skip    int `json:"-"`
+	}
+
+	jit := jitjson.NewFromBytes[Record]([]byte(`{
+		"id": 7,
+		"name": "widget",
+		"count": 300,
+		"tags": ["a", 1],
+		"address": {"city": "Oslo", "zip": "N-0150"},
+		"skip": 1
+	}`))
+	rec, errs := jit.UnmarshalPartial()
+
+	if rec.ID != 7 || rec.Name != "widget" || rec.Address.City != "Oslo" {
+		t.Errorf("expected the valid members to be decoded, got %+v", rec)
+	}
+	if rec.Skip != 0 {
+		t.Error("expected fields tagged - to be skipped")
+	}
+	paths := map[string]bool{}
+	for _, e := range errs {
+		paths[e.Path] = true
+		if !jitjson.IsTypeMismatch(e.Err) {
+			t.Errorf("%s: expected a type mismatch, got %v", e.Path, e.Err)
+		}
+	}
+	if len(errs) != 3 || !paths["$.count"] || !paths["$.tags"] || !paths["$.address.zip"] {
+		t.Errorf("expected errors for count, tags and address.zip, got %v", errs)
+	}
+
+	ok := jitjson.NewFromBytes[Record]([]byte(`{"name": "ok"}`))
+	if rec, errs := ok.UnmarshalPartial(); errs != nil || rec.Name != "ok" {
+		t.Errorf("expected a clean decode, got %+v, %v", rec, errs)
+	}
+
+	bad := jitjson.NewFromBytes[Record]([]byte(`{"name": `))
+	if _, errs := bad.UnmarshalPartial(); len(errs) != 1 || errs[0].Path != "$" || !jitjson.IsSyntaxError(errs[0].Err) {
+		t.Errorf("expected a single syntax error, got %v", errs)
+	}
+}
+
+func TestUnmarshalPartialEmbedded(t *testing.T) {
+	type Inner struct {
+		Name string
+		ID   int `json:"id"`
+		Code int
+	}
+	type Other struct {
+		Code int
+	}
+	type Outer struct {
+		*Inner
+		Other
+		Name  string
+		Count int8
+	}
+
+	jit := jitjson.NewFromBytes[Outer]([]byte(`{"Name": "outer", "id": 3, "Code": 9, "Count": 300}`))
+	rec, errs := jit.UnmarshalPartial()
+	if len(errs) != 1 || errs[0].Path != "$.Count" {
+		t.Errorf("expected an error for Count, got %v", errs)
+	}
+	if rec.Name != "outer" || rec.Inner == nil || rec.Inner.Name != "" || rec.Inner.ID != 3 {
+		t.Errorf("expected the shallowest fields to be decoded, got %+v", rec)
+	}
+	if rec.Inner.Code != 0 || rec.Other.Code != 0 {
+		t.Errorf("expected the conflicting Code fields to be ignored, got %+v", rec)
+	}
+}
@@ -0,0 +1,97 @@
+package jitjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// Lazy is implemented by *JitJSON[T] and *AnyJitJSON. Integrations can type-assert
+// to Lazy to pass raw JSON bytes through without a decode/encode round trip.
+type Lazy interface {
+	json.Marshaler
+	json.Unmarshaler
+	lazy()
+}
+
+func (jit *JitJSON[T]) lazy() {}
+
+func (a *AnyJitJSON) lazy() {}
+
+// Marshal is a drop-in replacement for json.Marshal. Lazy values are written from
+// their stored bytes as-is, while any other value is encoded by encoding/json.
+// It suits frameworks that accept a pluggable JSON encoder function.
+func Marshal(v any) ([]byte, error) {
+	lazy, ok := v.(Lazy)
+	if !ok {
+		return json.Marshal(v)
+	}
+	data, err := lazy.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return []byte("null"), nil
+	}
+	return data, nil
+}
+
+// MarshalAll writes the items to w as a JSON array. Each item is written from its
+// MarshalJSON result, so JitJSON[T] and AnyJitJSON values holding bytes are spliced in
+// as-is and only those lacking stored bytes are encoded. Nil items are written as null.
+// Every item is marshaled before anything is written, so w receives nothing if an item
+// fails. The encodings of all items are therefore held in memory together until the array
+// is written; stored bytes are referenced rather than copied, so only encoded items add to
+// the memory held. Writes to w are buffered.
+func MarshalAll(w io.Writer, items ...json.Marshaler) error {
+	encoded := make([][]byte, len(items))
+	for i, item := range items {
+		data, err := marshalItem(item)
+		if err != nil {
+			return err
+		}
+		encoded[i] = data
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+	for i, data := range encoded {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.Write(data)
+	}
+	bw.WriteByte(']')
+	return bw.Flush()
+}
+
+// marshalItem returns the MarshalJSON result of item, or null if item is nil or returns
+// no encoding.
+func marshalItem(item json.Marshaler) ([]byte, error) {
+	if v := reflect.ValueOf(item); !v.IsValid() || v.Kind() == reflect.Pointer && v.IsNil() {
+		return []byte("null"), nil
+	}
+	data, err := item.MarshalJSON()
+	if err != nil || data != nil {
+		return data, err
+	}
+	return []byte("null"), nil
+}
+
+// Unmarshal is a drop-in replacement for json.Unmarshal. When v is a Lazy value, data
+// is validated with ScanValid and a copy is stored without decoding, so the caller may
+// reuse data afterwards. Any other value is decoded by encoding/json.
+func Unmarshal(data []byte, v any) error {
+	lazy, ok := v.(Lazy)
+	if !ok {
+		return json.Unmarshal(data, v)
+	}
+	if !ScanValid(data) {
+		return errors.New("invalid json")
+	}
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	return lazy.UnmarshalJSON(buf)
+}
@@ -0,0 +1,119 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestMarshalPassthrough(t *testing.T) {
+	jsonData := []byte(`{"Name": "John",  "Age": 30}`)
+
+	t.Run("Lazy values", func(t *testing.T) {
+		var jit jitjson.JitJSON[Person]
+		if err := jitjson.Unmarshal(jsonData, &jit); err != nil {
+			t.Fatal(err)
+		}
+
+		// the caller's buffer may be reused
+		input := string(jsonData)
+		jsonData[2] = 'X'
+		defer func() { jsonData[2] = 'N' }()
+
+		data, err := jitjson.Marshal(&jit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != input {
+			t.Errorf("expected %s, got %s", input, data)
+		}
+
+		if err := jitjson.Unmarshal([]byte(`{"Name":`), &jit); err == nil {
+			t.Error("expected error for invalid json")
+		}
+
+		data, err = jitjson.Marshal(&jitjson.JitJSON[Person]{})
+		if err != nil || string(data) != "null" {
+			t.Errorf("expected null, got %s, %v", data, err)
+		}
+	})
+
+	t.Run("Other values", func(t *testing.T) {
+		var p Person
+		if err := jitjson.Unmarshal(jsonData, &p); err != nil {
+			t.Fatal(err)
+		}
+		data, err := jitjson.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"Name":"John","Age":30,"City":""}` {
+			t.Errorf("unexpected encoding %s", data)
+		}
+	})
+}
+
+func TestMarshalAll(t *testing.T) {
+	var encodes int
+	count := jitjson.WithOnMarshal(func(jitjson.ParseEvent) { encodes++ })
+	stored := jitjson.NewFromBytes[Person]([]byte(`{"Name": "John",  "Age": 30}`), count)
+	fresh := jitjson.New(Person{Name: "Jane", Age: 25}, count)
+	raw, err := jitjson.NewAny([]byte(`[1, 2]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing *jitjson.JitJSON[Person]
+
+	var buf bytes.Buffer
+	if err := jitjson.MarshalAll(&buf, stored, fresh, raw, missing, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"Name": "John",  "Age": 30},{"Name":"Jane","Age":25,"City":""},[1, 2],null,null]`
+	if buf.String() != want {
+		t.Errorf("expected %s, got %s", want, buf.String())
+	}
+	if encodes != 1 {
+		t.Errorf("expected only the fresh value to be encoded, got %d encodes", encodes)
+	}
+
+	buf.Reset()
+	if err := jitjson.MarshalAll(&buf); err != nil || buf.String() != "[]" {
+		t.Errorf("expected [], got %s, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	large := jitjson.NewFromBytes[string]([]byte(`"` + strings.Repeat("x", 8<<10) + `"`))
+	if err := jitjson.MarshalAll(&buf, large, jitjson.New(make(chan int))); err == nil {
+		t.Error("expected an error for an item that cannot be marshaled")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written on error, got %d bytes", buf.Len())
+	}
+}
+
+func TestMarshalNil(t *testing.T) {
+	var jit *jitjson.JitJSON[Person]
+	if data, err := jit.Marshal(); data != nil || err != nil {
+		t.Errorf("expected nil, got %s, %v", data, err)
+	}
+	if data, err := jit.MarshalJSON(); string(data) != "null" || err != nil {
+		t.Errorf("expected null, got %s, %v", data, err)
+	}
+	var a *jitjson.AnyJitJSON
+	if data, err := a.MarshalJSON(); string(data) != "null" || err != nil {
+		t.Errorf("expected null, got %s, %v", data, err)
+	}
+	if data, err := jitjson.Marshal(jit); string(data) != "null" || err != nil {
+		t.Errorf("expected null, got %s, %v", data, err)
+	}
+	if p, err := jit.Unmarshal(); p != (Person{}) || err != nil {
+		t.Errorf("expected the zero value, got %v, %v", p, err)
+	}
+	for p := range jitjson.Sample([]*jitjson.JitJSON[Person]{jit}, 1, nil) {
+		if p != (Person{}) {
+			t.Errorf("expected the zero value, got %v", p)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package jitjson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Paths are dot-separated lists of object member names, with numeric segments indexing
+// arrays, such as "items.0.qty". They are resolved by stepping through the raw encoding
+// one segment at a time, without parsing the containers along the way. Paths into
+// documents, as used by AnyJitJSON.Get, Update and TemplateFuncs, match member names
+// exactly, as MemberBytes does. Paths into the encodings of typed values, as used by Find,
+// GroupBy and the other helpers taking a path, match member names as FieldBytes does.
+
+// splitPath splits a dot-separated path into its segments.
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// pathBytes returns the raw encoding of the value at path within the JSON value data,
+// matching member names as FieldBytes does. It reports false if the value is missing,
+// or a value along path is not an object or array.
+func pathBytes(data []byte, path string) ([]byte, bool, error) {
+	for _, seg := range splitPath(path) {
+		raw, ok, err := stepBytes(data, seg, FieldBytes)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		data = raw
+	}
+	return data, true, nil
+}
+
+// stepBytes returns the raw encoding of the member or element at seg of the JSON value
+// data, looking members up with member. It reports false if there is none, or data is
+// not an object or array.
+func stepBytes(data []byte, seg string, member func(data []byte, key string) ([]byte, bool, error)) ([]byte, bool, error) {
+	i := skipSpace(data, 0)
+	switch {
+	case i < len(data) && data[i] == '{':
+		return member(data, seg)
+	case i < len(data) && data[i] == '[':
+		n, err := strconv.Atoi(seg)
+		if err != nil {
+			return nil, false, nil
+		}
+		return nthElement(data, n)
+	}
+	return nil, false, nil
+}
+
+// lookup returns the value at path within AnyJitJSON. Nodes holding their encoding are
+// stepped through by scanning it, returning new nodes; the others, such as those built
+// by NewAnyFromValue or staged by Update, are stepped through their members and
+// elements. It reports false if the value does not exist.
+func (a *AnyJitJSON) lookup(path string) (*AnyJitJSON, bool, error) {
+	node := a
+	for _, seg := range splitPath(path) {
+		if node.data == nil {
+			child, err := childOf(node, seg)
+			if err != nil || child == nil {
+				return nil, false, nil
+			}
+			node = child
+			continue
+		}
+		raw, ok, err := stepBytes(node.data, seg, MemberBytes)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		node = &AnyJitJSON{}
+		if err := node.set(raw); err != nil {
+			return nil, false, err
+		}
+	}
+	return node, true, nil
+}
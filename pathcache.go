@@ -0,0 +1,68 @@
+package jitjson
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// maxCachedPaths bounds the paths cached for a document; the cache is cleared when it
+// is full.
+const maxCachedPaths = 1024
+
+// pathCache holds the values resolved by Get for a document, keyed by path.
+type pathCache struct {
+	// encode encodes the document once, before any path is resolved.
+	encode sync.Once
+	mu     sync.RWMutex
+	nodes  map[string]*AnyJitJSON
+}
+
+// loadPaths returns the path cache of AnyJitJSON, or nil if there is none.
+func (a *AnyJitJSON) loadPaths() *pathCache {
+	return (*pathCache)(atomic.LoadPointer(&a.paths))
+}
+
+// storePaths sets the path cache of AnyJitJSON to c.
+func (a *AnyJitJSON) storePaths(c *pathCache) {
+	atomic.StorePointer(&a.paths, unsafe.Pointer(c))
+}
+
+// Get returns the value at path within AnyJitJSON, reporting false if it does not exist.
+// Paths are dot-separated member names, with numeric segments indexing arrays, as for
+// Update; the empty path is the whole document.
+//
+// The value is located by scanning the encoding of the document, without parsing the
+// containers along path, and is cached so later calls with the same path return it
+// without scanning again. A document built by NewAnyFromValue is encoded by the first
+// call. Get is safe for concurrent use with other calls to Get, but not with methods
+// that change the document; Update and UnmarshalJSON clear the cache. The value returned
+// is shared by those calls and holds its encoding, so concurrent callers may read it
+// with Get or MarshalJSON; the As methods decode it in place.
+func (a *AnyJitJSON) Get(path string) (*AnyJitJSON, bool) {
+	if path == "" {
+		return a, true
+	}
+	c := a.loadPaths()
+	if c == nil {
+		c = &pathCache{nodes: map[string]*AnyJitJSON{}}
+		if !atomic.CompareAndSwapPointer(&a.paths, nil, unsafe.Pointer(c)) {
+			c = a.loadPaths()
+		}
+	}
+	c.encode.Do(func() { a.encoded() })
+
+	c.mu.RLock()
+	node, ok := c.nodes[path]
+	c.mu.RUnlock()
+	if !ok {
+		node, _, _ = a.lookup(path)
+		c.mu.Lock()
+		if len(c.nodes) >= maxCachedPaths {
+			clear(c.nodes)
+		}
+		c.nodes[path] = node
+		c.mu.Unlock()
+	}
+	return node, node != nil
+}
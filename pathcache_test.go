@@ -0,0 +1,87 @@
+package jitjson_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestAnyJitJSONGet(t *testing.T) {
+	doc, err := jitjson.NewAny([]byte(`{"a": {"b": [{"c": 1}, {"c": "two"}]}, "x": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v, ok := doc.Get("a.b.1.c")
+				if out, _ := v.MarshalJSON(); !ok || string(out) != `"two"` {
+					t.Errorf("expected two, got %s, %v", out, ok)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	first, _ := doc.Get("a.b.0")
+	if again, _ := doc.Get("a.b.0"); again != first {
+		t.Error("expected the cached value")
+	}
+	if v, ok := doc.Get("x"); !ok || !v.IsNull() {
+		t.Errorf("expected null, got %v, %v", v, ok)
+	}
+	for _, path := range []string{"missing", "a.b.2", "a.b.x", "x.y"} {
+		if _, ok := doc.Get(path); ok {
+			t.Errorf("%s: expected no value", path)
+		}
+	}
+
+	err = doc.Update(func(tx *jitjson.Tx) error {
+		return tx.Set("a.b.0.c", 3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := doc.Get("a.b.0"); !ok || v.String() == first.String() {
+		t.Errorf("expected the updated value, got %v", v)
+	}
+
+	cp := *doc
+	if err := cp.UnmarshalJSON([]byte(`{"x": 1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := cp.Get("x"); !ok || v.String() != "1" {
+		t.Errorf("expected the copy's value, got %v", v)
+	}
+	if v, ok := doc.Get("x"); !ok || !v.IsNull() {
+		t.Errorf("expected changing the copy to leave the original, got %v", v)
+	}
+}
+
+func TestAnyJitJSONGetValue(t *testing.T) {
+	doc, err := jitjson.NewAnyFromValue(map[string]any{"tags": []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, ok := doc.Get("tags")
+			if out, _ := v.MarshalJSON(); !ok || string(out) != `["a","b"]` {
+				t.Errorf("expected the tags, got %s, %v", out, ok)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, ok := doc.Get("tags.1"); !ok || v.String() != `"b"` {
+		t.Errorf("expected b, got %v, %v", v, ok)
+	}
+}
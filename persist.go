@@ -0,0 +1,131 @@
+package jitjson
+
+import (
+	"context"
+	"fmt"
+)
+
+// Store is a key-value store of raw JSON encodings, such as a Redis or memcached
+// client, used by PersistentJitJSON[T]. Get returns nil data, and no error, when no
+// value is stored under the key.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// PersistentJitJSON is a JitJSON[T] backed by a Store under a key. The encoding is read
+// from the store on first access and decoded only when the value is requested, and
+// values set since then are written back by Flush, so caching layers integrate without
+// glue code:
+//
+//	session := jitjson.NewPersistent[Session](redisStore, "session:"+id)
+//	s, err := session.Get(ctx)
+//	s.Visits++
+//	session.Set(s)
+//	err = session.Flush(ctx)
+//
+// Like JitJSON[T], it is not safe for concurrent use.
+type PersistentJitJSON[T any] struct {
+	store  Store
+	key    string
+	jit    *JitJSON[T]
+	opts   []Option
+	loaded bool
+	dirty  bool
+}
+
+// NewPersistent returns a PersistentJitJSON[T] for the value stored under key in store.
+// Nothing is read until the value is first accessed. Opts configure the JitJSON[T]
+// holding the value.
+func NewPersistent[T any](store Store, key string, opts ...Option) *PersistentJitJSON[T] {
+	return &PersistentJitJSON[T]{store: store, key: key, opts: opts}
+}
+
+// Key returns the key the value is stored under.
+func (p *PersistentJitJSON[T]) Key() string {
+	return p.key
+}
+
+// load reads the encoding from the store on first access. A missing value is held as
+// an empty JitJSON[T].
+func (p *PersistentJitJSON[T]) load(ctx context.Context) error {
+	if p.loaded {
+		return nil
+	}
+	data, err := p.store.Get(ctx, p.key)
+	if err != nil {
+		return fmt.Errorf("jitjson: loading %q: %w", p.key, err)
+	}
+	if data == nil {
+		p.jit = &JitJSON[T]{opts: newOptions(p.opts)}
+	} else {
+		p.jit = NewFromBytes[T](data, p.opts...)
+	}
+	p.loaded = true
+	return nil
+}
+
+// Get returns the value, reading its encoding from the store on first access and
+// decoding it on first use. A key with no stored value gives the zero value of T.
+func (p *PersistentJitJSON[T]) Get(ctx context.Context) (T, error) {
+	if err := p.load(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	return p.jit.Unmarshal()
+}
+
+// Bytes returns the encoding of the value, reading it from the store on first access
+// without decoding it. A key with no stored value gives nil.
+func (p *PersistentJitJSON[T]) Bytes(ctx context.Context) ([]byte, error) {
+	if err := p.load(ctx); err != nil {
+		return nil, err
+	}
+	return p.jit.Marshal()
+}
+
+// Set replaces the value without reading the store, marking it to be written back by
+// the next Flush.
+func (p *PersistentJitJSON[T]) Set(val T) {
+	if !p.loaded {
+		p.jit = &JitJSON[T]{opts: newOptions(p.opts)}
+		p.loaded = true
+	}
+	p.jit.Set(val)
+	p.dirty = true
+}
+
+// Update applies fn to the current value, reading it from the store if needed, and
+// marks the result to be written back by the next Flush. The value is unchanged if it
+// cannot be read.
+func (p *PersistentJitJSON[T]) Update(ctx context.Context, fn func(*T)) error {
+	val, err := p.Get(ctx)
+	if err != nil {
+		return err
+	}
+	fn(&val)
+	p.Set(val)
+	return nil
+}
+
+// Dirty reports whether the value has been set since it was last read or written back.
+func (p *PersistentJitJSON[T]) Dirty() bool {
+	return p.dirty
+}
+
+// Flush writes the value back to the store if it has been set since it was last read
+// or written back. The value is marshaled once, and the encoding kept for later reads.
+func (p *PersistentJitJSON[T]) Flush(ctx context.Context) error {
+	if !p.dirty {
+		return nil
+	}
+	data, err := p.jit.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := p.store.Put(ctx, p.key, data); err != nil {
+		return fmt.Errorf("jitjson: storing %q: %w", p.key, err)
+	}
+	p.dirty = false
+	return nil
+}
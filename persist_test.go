@@ -0,0 +1,82 @@
+package jitjson_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// mapStore is an in-memory jitjson.Store counting its calls.
+type mapStore struct {
+	m          map[string][]byte
+	gets, puts int
+	err        error
+}
+
+func (s *mapStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.gets++
+	return s.m[key], s.err
+}
+
+func (s *mapStore) Put(ctx context.Context, key string, data []byte) error {
+	s.puts++
+	if s.err != nil {
+		return s.err
+	}
+	s.m[key] = data
+	return nil
+}
+
+func TestPersistentJitJSON(t *testing.T) {
+	ctx := context.Background()
+	store := &mapStore{m: map[string][]byte{"p:1": []byte(`{"Name":"John","Age":30}`)}}
+
+	p := jitjson.NewPersistent[Person](store, "p:1")
+	if store.gets != 0 {
+		t.Error("expected nothing to be read before first access")
+	}
+	data, err := p.Bytes(ctx)
+	if err != nil || string(data) != `{"Name":"John","Age":30}` {
+		t.Errorf("expected the stored bytes, got %s, %v", data, err)
+	}
+	if err := p.Flush(ctx); err != nil || store.puts != 0 {
+		t.Errorf("expected a clean value not to be written, got %d puts, %v", store.puts, err)
+	}
+
+	if err := p.Update(ctx, func(v *Person) { v.Age++ }); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Dirty() {
+		t.Error("expected the value to be dirty after Update")
+	}
+	if err := p.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if store.gets != 1 || store.puts != 1 || p.Dirty() {
+		t.Errorf("expected one read and one write, got %d and %d", store.gets, store.puts)
+	}
+	if got := string(store.m["p:1"]); got != `{"Name":"John","Age":31,"City":""}` {
+		t.Errorf("expected the updated value to be stored, got %s", got)
+	}
+
+	missing := jitjson.NewPersistent[Person](store, "p:2")
+	if v, err := missing.Get(ctx); err != nil || v != (Person{}) {
+		t.Errorf("expected the zero value for a missing key, got %+v, %v", v, err)
+	}
+	missing.Set(Person{Name: "Jane"})
+	if err := missing.Flush(ctx); err != nil || store.m["p:2"] == nil {
+		t.Errorf("expected the new value to be stored, got %v", err)
+	}
+
+	store.err = errors.New("unavailable")
+	failing := jitjson.NewPersistent[Person](store, "p:1")
+	if _, err := failing.Get(ctx); !errors.Is(err, store.err) {
+		t.Errorf("expected the store error, got %v", err)
+	}
+	failing.Set(Person{Name: "Joe"})
+	if err := failing.Flush(ctx); !errors.Is(err, store.err) || !failing.Dirty() {
+		t.Errorf("expected a failed write to leave the value dirty, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package jitjson
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typePools holds the package-level pools used by WithPool(nil), keyed by reflect.Type.
+var typePools sync.Map
+
+// WithPool makes Unmarshal decode into *T values obtained from pool, which are returned
+// to it by Release. This suits high-throughput servers that decode into large structs
+// which can be reused between requests. The pool's New function may be nil; values of
+// the wrong type are discarded. If pool is nil, a package-level pool shared by all
+// values of type T is used.
+func WithPool(pool *sync.Pool) Option {
+	return func(o *options) {
+		o.pool = pool
+		o.typePool = pool == nil
+	}
+}
+
+// poolFor returns the pool configured for JitJSON[T], or nil if pooling is disabled.
+func poolFor[T any](o *options) *sync.Pool {
+	switch {
+	case o == nil:
+		return nil
+	case o.pool != nil:
+		return o.pool
+	case o.typePool:
+		return typePool[T]()
+	default:
+		return nil
+	}
+}
+
+// typePool returns the package-level pool of *T values.
+func typePool[T any]() *sync.Pool {
+	typ := reflect.TypeFor[T]()
+	if p, ok := typePools.Load(typ); ok {
+		return p.(*sync.Pool)
+	}
+	p, _ := typePools.LoadOrStore(typ, &sync.Pool{})
+	return p.(*sync.Pool)
+}
+
+// newValue returns a zeroed *T to decode into, taken from the configured pool if any.
+func (jit *JitJSON[T]) newValue() *T {
+	if pool := poolFor[T](jit.opts); pool != nil {
+		if val, ok := pool.Get().(*T); ok && val != nil {
+			var zero T
+			*val = zero
+			return val
+		}
+	}
+	return new(T)
+}
+
+// Release discards the decoded value of JitJSON[T], returning it to the configured pool
+// if WithPool is set. The JSON encoding is kept, so a later Unmarshal decodes it again.
+// Values returned by Unmarshal must not be used after Release when T holds references
+// into the pooled value. If there is no encoding to fall back on, Release does nothing.
+func (jit *JitJSON[T]) Release() {
+	if jit.val == nil || jit.data == nil {
+		return
+	}
+	if pool := poolFor[T](jit.opts); pool != nil {
+		pool.Put(jit.val)
+	}
+	jit.val = nil
+}
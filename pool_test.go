@@ -0,0 +1,83 @@
+package jitjson_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestJitJSON_Pool(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+
+	t.Run("User pool", func(t *testing.T) {
+		var gets int
+		pool := &sync.Pool{New: func() any {
+			gets++
+			return &Person{}
+		}}
+
+		jit := jitjson.NewFromBytes[Person](jsonData, jitjson.WithPool(pool))
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" || gets != 1 {
+			t.Error("expected value to be decoded into a pooled target")
+		}
+
+		jit.Release()
+
+		// released values are decoded again from the retained bytes
+		p, err = jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" || p.Age != 30 || p.City != "New York" {
+			t.Error("values do not match")
+		}
+	})
+
+	t.Run("Reused targets are zeroed", func(t *testing.T) {
+		pool := &sync.Pool{}
+		pool.Put(&Person{Name: "Stale", Age: 99, City: "Stale"})
+
+		jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"Jane"}`), jitjson.WithPool(pool))
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "Jane" || p.Age != 0 || p.City != "" {
+			t.Errorf("expected zeroed target, got %+v", p)
+		}
+	})
+
+	t.Run("Type pool", func(t *testing.T) {
+		jit := jitjson.NewFromBytes[Person](jsonData, jitjson.WithPool(nil))
+		if _, err := jit.Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+		jit.Release()
+
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" {
+			t.Error("values do not match")
+		}
+	})
+
+	t.Run("Release without bytes", func(t *testing.T) {
+		jit := jitjson.New(Person{Name: "John"}, jitjson.WithPool(nil))
+		jit.Release()
+
+		p, err := jit.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "John" {
+			t.Error("expected value to be kept")
+		}
+	})
+}
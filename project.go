@@ -0,0 +1,27 @@
+package jitjson
+
+// Project returns a JitJSON[Summary] holding the same encoding as jit, so that a small
+// view of a payload can be decoded while the full value stays deferred:
+//
+//	order := jitjson.NewFromBytes[Order](data)
+//	summary, err := jitjson.Project[Order, OrderSummary](order).Unmarshal()
+//
+// The encoding is shared rather than copied, and neither value's later decoding affects
+// the other. If jit holds only a value, it is marshaled first and the encoding cached
+// in jit; if that fails, the error is returned by the projection's Unmarshal. Options of
+// jit are not inherited, as pools are specific to the type decoded into; opts configure
+// the projection instead.
+func Project[Full, Summary any](jit *JitJSON[Full], opts ...Option) *JitJSON[Summary] {
+	proj := &JitJSON[Summary]{opts: newOptions(opts)}
+	data, err := jit.Marshal()
+	if err != nil {
+		proj.val = new(Summary)
+		proj.verr = err
+		return proj
+	}
+	if data != nil {
+		recordDeferredUnmarshal(len(data))
+		proj.setData(data)
+	}
+	return proj
+}
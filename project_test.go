@@ -0,0 +1,60 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type PersonSummary struct {
+	Name string
+}
+
+func TestProject(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+	jit := jitjson.NewFromBytes[Person](jsonData)
+
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	summary, err := jitjson.Project[Person, PersonSummary](jit, count).Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Name != "John" {
+		t.Errorf("expected John, got %s", summary.Name)
+	}
+	if decodes != 1 {
+		t.Errorf("expected one decoding, got %d", decodes)
+	}
+
+	// the full value is still deferred
+	if jit.DebugState().HasValue {
+		t.Error("expected the full value not to be decoded")
+	}
+	data, err := jit.Marshal()
+	if err != nil || string(data) != string(jsonData) {
+		t.Errorf("expected stored bytes, got %s, %v", data, err)
+	}
+
+	proj := jitjson.Project[Person, PersonSummary](jitjson.New(Person{Name: "Jane"}))
+	if summary, err := proj.Unmarshal(); err != nil || summary.Name != "Jane" {
+		t.Errorf("expected Jane, got %+v, %v", summary, err)
+	}
+
+	var empty jitjson.JitJSON[Person]
+	if summary, err := jitjson.Project[Person, PersonSummary](&empty).Unmarshal(); err != nil || summary.Name != "" {
+		t.Errorf("expected zero value, got %+v, %v", summary, err)
+	}
+
+	bad := jitjson.New[failingMarshaler](failingMarshaler{})
+	if _, err := jitjson.Project[failingMarshaler, PersonSummary](bad).Unmarshal(); !errors.Is(err, errMarshal) {
+		t.Errorf("expected marshal error, got %v", err)
+	}
+}
+
+var errMarshal = errors.New("marshal failed")
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) { return nil, errMarshal }
@@ -0,0 +1,284 @@
+package jitjson
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Span is the byte range of a JSON value within a document.
+type Span struct {
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length"`
+}
+
+// DocumentIndex records the spans of the top-level elements or members of a document.
+// It can be persisted (it marshals to JSON) so later processes fetch only the slices
+// of a remote document that they access, without scanning it again.
+type DocumentIndex struct {
+	Type     ValueType       `json:"type"`
+	Elements []Span          `json:"elements,omitempty"`
+	Members  map[string]Span `json:"members,omitempty"`
+}
+
+// RemoteDocument is a JSON document read through an io.ReaderAt, such as a range-request
+// client for S3 or other object storage. Values are fetched by byte range on demand, so
+// queries over huge documents only download the slices they actually access.
+type RemoteDocument struct {
+	r     io.ReaderAt
+	size  int64
+	index *DocumentIndex
+}
+
+// NewRemoteDocument creates a RemoteDocument over size bytes of r. If index is nil, it
+// is built by BuildIndex on first access, which reads the document once sequentially.
+func NewRemoteDocument(r io.ReaderAt, size int64, index *DocumentIndex) *RemoteDocument {
+	return &RemoteDocument{r: r, size: size, index: index}
+}
+
+// Index returns the structural index of the document, building it if needed.
+func (d *RemoteDocument) Index() (*DocumentIndex, error) {
+	if d.index != nil {
+		return d.index, nil
+	}
+	index, err := BuildIndex(io.NewSectionReader(d.r, 0, d.size))
+	if err != nil {
+		return nil, err
+	}
+	d.index = index
+	return index, nil
+}
+
+// Len returns the number of top-level elements or members of the document.
+func (d *RemoteDocument) Len() (int, error) {
+	index, err := d.Index()
+	if err != nil {
+		return 0, err
+	}
+	if index.Type == TypeObject {
+		return len(index.Members), nil
+	}
+	return len(index.Elements), nil
+}
+
+// Element fetches element i of a top-level array.
+func (d *RemoteDocument) Element(i int) (*AnyJitJSON, error) {
+	index, err := d.Index()
+	if err != nil {
+		return nil, err
+	}
+	if index.Type != TypeArray {
+		return nil, errors.New("jitjson: document is not an array")
+	}
+	if i < 0 || i >= len(index.Elements) {
+		return nil, fmt.Errorf("jitjson: index %d out of range", i)
+	}
+	return d.Fetch(index.Elements[i])
+}
+
+// Member fetches the member key of a top-level object.
+func (d *RemoteDocument) Member(key string) (*AnyJitJSON, error) {
+	index, err := d.Index()
+	if err != nil {
+		return nil, err
+	}
+	if index.Type != TypeObject {
+		return nil, errors.New("jitjson: document is not an object")
+	}
+	span, ok := index.Members[key]
+	if !ok {
+		return nil, fmt.Errorf("jitjson: member %q not found", key)
+	}
+	return d.Fetch(span)
+}
+
+// Fetch reads the bytes of span from the document as an AnyJitJSON.
+func (d *RemoteDocument) Fetch(span Span) (*AnyJitJSON, error) {
+	if span.Offset < 0 || span.Length < 0 || span.Offset+span.Length > d.size {
+		return nil, errors.New("jitjson: span out of range")
+	}
+	buf := make([]byte, span.Length)
+	if _, err := d.r.ReadAt(buf, span.Offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return NewAny(buf)
+}
+
+// BuildIndex reads a JSON array or object from r in a single sequential pass and
+// records the spans of its top-level elements or members. Only member keys are kept
+// in memory, so arbitrarily large documents can be indexed. The top-level structure is
+// validated, including the separators between values and that nothing but whitespace
+// follows the document; the values themselves are validated when fetched.
+func BuildIndex(r io.Reader) (*DocumentIndex, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	pos := int64(-1)
+	next := func() (byte, error) {
+		c, err := br.ReadByte()
+		if err == nil {
+			pos++
+		}
+		return c, err
+	}
+
+	var c byte
+	var err error
+	for c, err = next(); err == nil && isSpace(c); c, err = next() {
+	}
+	if err != nil {
+		return nil, errors.New("jitjson: empty document")
+	}
+
+	index := &DocumentIndex{}
+	switch c {
+	case '[':
+		index.Type = TypeArray
+	case '{':
+		index.Type = TypeObject
+		index.Members = map[string]Span{}
+	default:
+		return nil, errors.New("jitjson: document must be an array or object")
+	}
+	isObject := index.Type == TypeObject
+
+	var (
+		depth     = 1
+		inString  bool
+		escaped   bool
+		inKey     bool
+		expectKey = isObject
+		expectCol bool
+		key       []byte
+		start     = int64(-1)
+		end       int64
+		complete  bool // the top-level value at start has ended
+	)
+	count := 0
+	closeValue := func(closing bool) error {
+		if start < 0 {
+			if closing && count == 0 && key == nil {
+				return nil // empty array or object
+			}
+			return fmt.Errorf("jitjson: missing value at offset %d", pos)
+		}
+		count++
+		span := Span{Offset: start, Length: end - start}
+		if isObject {
+			k, err := unquote(key)
+			if err != nil {
+				return fmt.Errorf("jitjson: invalid key at offset %d", pos)
+			}
+			index.Members[k] = span
+			key = nil
+		} else {
+			index.Elements = append(index.Elements, span)
+		}
+		start, complete = -1, false
+		return nil
+	}
+
+	for {
+		c, err = next()
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: unexpected end of document at offset %d", pos+1)
+		}
+
+		if inString {
+			if inKey {
+				key = append(key, c)
+			} else {
+				end = pos + 1
+			}
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				expectCol = inKey
+				complete = depth == 1 && !inKey
+				inKey = false
+			}
+			continue
+		}
+		if isSpace(c) {
+			complete = complete || depth == 1 && start >= 0
+			continue
+		}
+
+		if depth == 1 && expectCol {
+			if c != ':' {
+				return nil, fmt.Errorf("jitjson: expected ':' at offset %d", pos)
+			}
+			expectCol = false
+			continue
+		}
+		if depth == 1 {
+			switch c {
+			case ',':
+				if err := closeValue(false); err != nil {
+					return nil, err
+				}
+				expectKey = isObject
+				continue
+			case ']', '}':
+				if (c == ']') == isObject {
+					return nil, fmt.Errorf("jitjson: mismatched %q at offset %d", c, pos)
+				}
+				if err := closeValue(true); err != nil {
+					return nil, err
+				}
+				if err := trailing(next, &pos); err != nil {
+					return nil, err
+				}
+				return index, nil
+			case ':':
+				return nil, fmt.Errorf("jitjson: unexpected ':' at offset %d", pos)
+			}
+			if complete || start >= 0 && (c == '"' || c == '[' || c == '{') {
+				return nil, fmt.Errorf("jitjson: expected ',' at offset %d", pos)
+			}
+			if expectKey {
+				if c != '"' {
+					return nil, fmt.Errorf("jitjson: expected key at offset %d", pos)
+				}
+				expectKey = false
+				inString, inKey = true, true
+				key = append(key[:0], c)
+				continue
+			}
+			if start < 0 {
+				start = pos
+			}
+		}
+
+		end = pos + 1
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			complete = depth == 1
+		}
+	}
+}
+
+// trailing checks that only whitespace follows the document read by next, where pos
+// is the offset of the last byte read.
+func trailing(next func() (byte, error), pos *int64) error {
+	for {
+		c, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !isSpace(c) {
+			return fmt.Errorf("jitjson: unexpected data after document at offset %d", *pos)
+		}
+	}
+}
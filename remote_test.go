@@ -0,0 +1,111 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// countingReaderAt records the bytes read through ReadAt.
+type countingReaderAt struct {
+	r     *bytes.Reader
+	bytes int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.bytes += n
+	return n, err
+}
+
+func TestRemoteDocument(t *testing.T) {
+	t.Run("Array", func(t *testing.T) {
+		data := []byte(` [ {"Name": "John", "Tags": ["a,b", "]"]}, "text \" ]", 42 , [ ] ] `)
+		index, err := jitjson.BuildIndex(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(index.Elements) != 4 {
+			t.Fatalf("expected 4 elements, got %d", len(index.Elements))
+		}
+
+		// reuse the persisted index so only the requested slice is read
+		r := &countingReaderAt{r: bytes.NewReader(data)}
+		doc := jitjson.NewRemoteDocument(r, int64(len(data)), index)
+
+		elem, err := doc.Element(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, ok := elem.AsString(); !ok || s != `text " ]` {
+			t.Errorf("unexpected element %v", elem)
+		}
+		if r.bytes != int(index.Elements[1].Length) {
+			t.Errorf("expected %d bytes read, got %d", index.Elements[1].Length, r.bytes)
+		}
+
+		elem, err = doc.Element(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if elem.Type() != jitjson.TypeObject {
+			t.Errorf("expected TypeObject, got %v", elem.Type())
+		}
+		if _, err := doc.Element(4); err == nil {
+			t.Error("expected error for out of range element")
+		}
+	})
+
+	t.Run("Object", func(t *testing.T) {
+		data := []byte(`{"users": [1, 2, 3], "meta": {"count": 3}, "esc\"key": null}`)
+		doc := jitjson.NewRemoteDocument(bytes.NewReader(data), int64(len(data)), nil)
+
+		n, err := doc.Len()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 3 {
+			t.Errorf("expected 3 members, got %d", n)
+		}
+
+		meta, err := doc.Member("meta")
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj, ok := meta.AsObject()
+		if !ok {
+			t.Fatal("expected object member")
+		}
+		if n, _ := obj["count"].AsNumber(); n != "3" {
+			t.Errorf("expected count 3, got %v", n)
+		}
+		if v, err := doc.Member(`esc"key`); err != nil || !v.IsNull() {
+			t.Errorf("expected null member, got %v, %v", v, err)
+		}
+		if _, err := doc.Member("missing"); err == nil {
+			t.Error("expected error for missing member")
+		}
+
+		index, _ := doc.Index()
+		if _, err := json.Marshal(index); err != nil {
+			t.Errorf("expected index to be serializable: %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, input := range []string{``, `1`, `[1,]`, `[,1]`, `{"a" 1}`, `{"a":}`, `[1`, `{"a":1]`, `{1:2}`,
+			`[1 2]`, `["a" "b"]`, `[1"a"]`, `[{}1]`, `[[] []]`, `{"a":1 2}`, `{"a":"b" "c":1}`, `[1] x`, `{}{}`} {
+			if _, err := jitjson.BuildIndex(strings.NewReader(input)); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		}
+		for _, input := range []string{`[]`, `{}`, ` [ ] `, "[ 1 , \"a\" ,[ ] ]\n", `{"a" : {"b":[1, 2]} }`} {
+			if _, err := jitjson.BuildIndex(strings.NewReader(input)); err != nil {
+				t.Errorf("unexpected error for %q: %v", input, err)
+			}
+		}
+	})
+}
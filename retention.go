@@ -0,0 +1,45 @@
+package jitjson
+
+import "strconv"
+
+// Retention selects which representations JitJSON[T] keeps after parsing, trading
+// memory for the cost of parsing again.
+type Retention int
+
+const (
+	// KeepBoth keeps the encoding and the decoded value, so neither Marshal nor
+	// Unmarshal parses twice. It is the default.
+	KeepBoth Retention = iota
+	// KeepBytes keeps only the encoding: Unmarshal decodes on every call without caching
+	// the value, and values stored by New or Set are dropped once marshaled. It suits
+	// values that are decoded rarely but held for long.
+	KeepBytes
+	// KeepValue keeps only the decoded value: the encoding is dropped once Unmarshal has
+	// decoded it successfully, and Marshal encodes on every call without caching the
+	// encoding. It suits large payloads whose decoded form is used repeatedly.
+	KeepValue
+)
+
+func (r Retention) String() string {
+	switch r {
+	case KeepBoth:
+		return "KeepBoth"
+	case KeepBytes:
+		return "KeepBytes"
+	case KeepValue:
+		return "KeepValue"
+	}
+	return "Retention(" + strconv.Itoa(int(r)) + ")"
+}
+
+// WithRetention sets which representations JitJSON[T] keeps after parsing.
+func WithRetention(r Retention) Option {
+	return func(o *options) {
+		o.retention = r
+	}
+}
+
+// keeps reports whether the configured retention keeps the representation r.
+func (o *options) keeps(r Retention) bool {
+	return o == nil || o.retention == KeepBoth || o.retention == r
+}
@@ -0,0 +1,70 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWithRetention(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+
+	t.Run("KeepBytes", func(t *testing.T) {
+		var decodes int
+		count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+		jit := jitjson.NewFromBytes[Person](jsonData, jitjson.WithRetention(jitjson.KeepBytes), count)
+		for i := 0; i < 2; i++ {
+			if p, err := jit.Unmarshal(); err != nil || p.Name != "John" {
+				t.Fatalf("expected John, got %+v, %v", p, err)
+			}
+		}
+		if decodes != 2 {
+			t.Errorf("expected every Unmarshal to decode, got %d", decodes)
+		}
+
+		decodes = 0
+		set := jitjson.New(Person{Name: "Jane"}, jitjson.WithRetention(jitjson.KeepBytes), count)
+		if _, err := set.Marshal(); err != nil {
+			t.Fatal(err)
+		}
+		if p, _ := set.Unmarshal(); p.Name != "Jane" {
+			t.Errorf("expected Jane, got %s", p.Name)
+		}
+		if decodes != 1 {
+			t.Errorf("expected the value to be dropped once marshaled, got %d decodes", decodes)
+		}
+	})
+
+	t.Run("KeepValue", func(t *testing.T) {
+		var encodes int
+		count := jitjson.WithOnMarshal(func(jitjson.ParseEvent) { encodes++ })
+		jit := jitjson.NewFromBytes[Person](jsonData, jitjson.WithRetention(jitjson.KeepValue), count)
+		if _, err := jit.Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			data, err := jit.Marshal()
+			if err != nil || string(data) != `{"Name":"John","Age":30,"City":"New York"}` {
+				t.Fatalf("unexpected encoding %s, %v", data, err)
+			}
+		}
+		if encodes != 2 {
+			t.Errorf("expected every Marshal to encode, got %d", encodes)
+		}
+		if _, err := jit.Unmarshal(); err != nil {
+			t.Error(err)
+		}
+
+		bad := jitjson.NewFromBytes[Person]([]byte(`[]`), jitjson.WithRetention(jitjson.KeepValue))
+		if _, err := bad.Unmarshal(); err == nil {
+			t.Error("expected decoding error")
+		}
+		if data, _ := bad.Marshal(); string(data) != `[]` {
+			t.Errorf("expected the encoding to be kept after a failed decoding, got %s", data)
+		}
+	})
+
+	if s := jitjson.KeepValue.String(); s != "KeepValue" {
+		t.Errorf("expected KeepValue, got %s", s)
+	}
+}
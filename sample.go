@@ -0,0 +1,52 @@
+package jitjson
+
+import (
+	"iter"
+	"math"
+	"math/rand/v2"
+)
+
+// sampleScale is the denominator of the fractions used to sample at an even stride, so
+// decimal fractions such as 0.1 or 0.3 are represented exactly.
+const sampleScale = 1_000_000_000
+
+// Sample returns an iterator over the decoded values of a random subset of items, each
+// chosen with probability fraction, so sampling jobs only pay to decode the items they
+// report on:
+//
+//	for order := range jitjson.Sample(orders, 0.01, rand.New(rand.NewPCG(1, 2))) {
+//		totals.Add(order.Total)
+//	}
+//
+// Items that are not chosen are not decoded. If r is nil, items are chosen at an even
+// stride instead, one in every 1/fraction, which gives repeatable samples: the item at
+// index i is chosen when floor((i+1)·fraction) exceeds floor(i·fraction), so exactly
+// floor(n·fraction) of n items are chosen. A fraction of 0 or less chooses no items and
+// 1 or more chooses every item. Items that fail to decode are skipped; use Unmarshal on the items to see
+// the errors.
+func Sample[T any](items []*JitJSON[T], fraction float64, r *rand.Rand) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if fraction <= 0 {
+			return
+		}
+		p := uint64(math.Round(fraction * sampleScale))
+		for i, item := range items {
+			if fraction < 1 {
+				if r != nil {
+					if r.Float64() >= fraction {
+						continue
+					}
+				} else if uint64(i+1)*p/sampleScale == uint64(i)*p/sampleScale {
+					continue
+				}
+			}
+			val, err := item.Unmarshal()
+			if err != nil {
+				continue
+			}
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package jitjson_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestSample(t *testing.T) {
+	items := make([]*jitjson.JitJSON[Person], 100)
+	for i := range items {
+		items[i] = jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`))
+	}
+
+	count := func(fraction float64, r *rand.Rand) int {
+		n := 0
+		for p := range jitjson.Sample(items, fraction, r) {
+			if p.Name != "John" {
+				t.Errorf("expected John, got %+v", p)
+			}
+			n++
+		}
+		return n
+	}
+
+	for _, tc := range []struct {
+		fraction float64
+		want     int
+	}{{0.1, 10}, {0.25, 25}, {0.3, 30}, {0.7, 70}, {0.99, 99}} {
+		if n := count(tc.fraction, nil); n != tc.want {
+			t.Errorf("expected an even stride of %v to choose %d items, got %d", tc.fraction, tc.want, n)
+		}
+	}
+
+	var decodes int
+	hooked := make([]*jitjson.JitJSON[Person], 100)
+	for i := range hooked {
+		hooked[i] = jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`),
+			jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+	}
+	for range jitjson.Sample(hooked, 0.25, nil) {
+	}
+	if decodes != 25 {
+		t.Errorf("expected only chosen items to be decoded, got %d decodes", decodes)
+	}
+
+	if n := count(0.5, rand.New(rand.NewPCG(1, 2))); n < 30 || n > 70 {
+		t.Errorf("expected about 50 items, got %d", n)
+	}
+	if n := count(0, nil); n != 0 {
+		t.Errorf("expected no items, got %d", n)
+	}
+	if n := count(1, rand.New(rand.NewPCG(1, 2))); n != 100 {
+		t.Errorf("expected every item, got %d", n)
+	}
+
+	items[0] = jitjson.NewFromBytes[Person]([]byte(`{"Name":`))
+	if n := count(1, nil); n != 99 {
+		t.Errorf("expected invalid items to be skipped, got %d", n)
+	}
+}
@@ -0,0 +1,32 @@
+package jitjson
+
+import "github.com/mcwalrus/go-jitjson/scan"
+
+// The structural scanning used throughout the package is implemented by package scan.
+
+func isSpace(c byte) bool                     { return scan.IsSpace(c) }
+func skipSpace(data []byte, i int) int        { return scan.SkipSpace(data, i) }
+func stringEnd(data []byte, i int) int        { return scan.SkipString(data, i) }
+func valueEnd(data []byte, i int) int         { return scan.SkipValue(data, i) }
+func splitArray(data []byte) ([][]byte, bool) { return scan.Elements(data) }
+func scanString(data []byte, i int) int       { return scan.String(data, i) }
+func scanNumber(data []byte, i int) int       { return scan.Number(data, i) }
+func scanScalar(data []byte, i int) int       { return scan.Scalar(data, i) }
+func depthExceeds(data []byte, max int) bool  { return scan.DepthExceeds(data, max) }
+
+func splitObject(data []byte, fn func(key, val []byte) bool) bool {
+	return scan.Members(data, fn)
+}
+
+func scanLiteral(data []byte, i int, lit string) int {
+	return scan.Literal(data, i, lit)
+}
+
+// ScanValid reports whether data is a single valid JSON value, optionally surrounded by
+// whitespace. It is implemented as a non-recursive state machine and performs no
+// allocations for documents nested up to 64 levels deep, making it considerably cheaper
+// than json.Valid for checking payloads before their decoding is deferred. Like
+// json.Valid, invalid UTF-8 inside strings is not rejected.
+func ScanValid(data []byte) bool {
+	return scan.Valid(data)
+}
@@ -0,0 +1,464 @@
+// Package scan provides the structural JSON scanning primitives that jitjson is built
+// on, so lazy abstractions can be built on the same primitives without a second JSON
+// library. The functions work on byte offsets into the data and never allocate for the
+// values they skip or delimit:
+//
+//	start, end, ok := scan.FieldBounds(data, "items")
+//	if ok {
+//		items, _ := scan.Elements(data[start:end]) // raw elements, not decoded
+//	}
+//
+// Functions taking an index i return the index just past what they scanned, or -1 if
+// the data is malformed at that point.
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// IsSpace reports whether c is insignificant JSON whitespace.
+func IsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// SkipSpace returns the index of the first non-whitespace byte in data at or after i.
+func SkipSpace(data []byte, i int) int {
+	for i < len(data) && IsSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+// SkipString returns the index just past the JSON string starting at data[i],
+// or -1 if the string is not terminated.
+func SkipString(data []byte, i int) int {
+	if i >= len(data) || data[i] != '"' {
+		return -1
+	}
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// SkipValue returns the index just past the JSON value starting at data[i], or -1
+// if the value is malformed. Scalars are delimited but not validated; arrays and
+// objects are checked for balanced brackets without recursion.
+func SkipValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+	switch data[i] {
+	case '"':
+		return SkipString(data, i)
+	case '{', '[':
+		var stack []byte
+		for i < len(data) {
+			switch c := data[i]; c {
+			case '"':
+				i = SkipString(data, i)
+				if i < 0 {
+					return -1
+				}
+				continue
+			case '{', '[':
+				stack = append(stack, c)
+			case '}', ']':
+				open := byte('{')
+				if c == ']' {
+					open = '['
+				}
+				if len(stack) == 0 || stack[len(stack)-1] != open {
+					return -1
+				}
+				stack = stack[:len(stack)-1]
+				if len(stack) == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return -1
+	case '}', ']', ',', ':':
+		return -1
+	default:
+		for i < len(data) {
+			switch c := data[i]; {
+			case IsSpace(c), c == ',', c == ']', c == '}', c == ':':
+				return i
+			}
+			i++
+		}
+		return i
+	}
+}
+
+// NextValue returns the bounds of the JSON value at or after data[i], skipping leading
+// whitespace. The value is delimited as by SkipValue. If there is no value, or it is
+// malformed, end is -1.
+func NextValue(data []byte, i int) (start, end int) {
+	start = SkipSpace(data, i)
+	return start, SkipValue(data, start)
+}
+
+// FieldBounds returns the bounds of the value of the member key of the JSON object in
+// data, scanning the object structurally without decoding it. Keys are compared exactly
+// after unescaping; if there are several members named key, the last is returned, as
+// encoding/json would decode. If the object has no such member or is malformed, ok is
+// false.
+func FieldBounds(data []byte, key string) (start, end int, ok bool) {
+	valid := Members(data, func(k, val []byte) bool {
+		if !keyEquals(k, key) {
+			return true
+		}
+		// val is a sub-slice of data, so its offset follows from the capacities
+		start = cap(data) - cap(val)
+		end = start + len(val)
+		ok = true
+		return true
+	})
+	if !valid {
+		return 0, 0, false
+	}
+	return start, end, ok
+}
+
+// keyEquals reports whether the raw quoted key equals key once unescaped.
+func keyEquals(raw []byte, key string) bool {
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return len(raw) == len(key)+2 && string(raw[1:len(raw)-1]) == key
+	}
+	var s string
+	return json.Unmarshal(raw, &s) == nil && s == key
+}
+
+// Elements returns the raw elements of the JSON array in data. Each element is a
+// sub-slice of data, so no bytes are copied.
+func Elements(data []byte) ([][]byte, bool) {
+	i := SkipSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return nil, false
+	}
+	var elems [][]byte
+	i = SkipSpace(data, i+1)
+	if i < len(data) && data[i] == ']' {
+		return elems, SkipSpace(data, i+1) == len(data)
+	}
+	for {
+		end := SkipValue(data, i)
+		if end < 0 {
+			return nil, false
+		}
+		elems = append(elems, data[i:end])
+		i = SkipSpace(data, end)
+		if i >= len(data) {
+			return nil, false
+		}
+		switch data[i] {
+		case ',':
+			i = SkipSpace(data, i+1)
+		case ']':
+			return elems, SkipSpace(data, i+1) == len(data)
+		default:
+			return nil, false
+		}
+	}
+}
+
+// Members calls fn for each member of the JSON object in data. The key is the raw
+// quoted key and the value is a sub-slice of data, so no bytes are copied.
+func Members(data []byte, fn func(key, val []byte) bool) bool {
+	i := SkipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return false
+	}
+	i = SkipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return SkipSpace(data, i+1) == len(data)
+	}
+	for {
+		if i >= len(data) || data[i] != '"' {
+			return false
+		}
+		keyEnd := SkipString(data, i)
+		if keyEnd < 0 {
+			return false
+		}
+		key := data[i:keyEnd]
+		i = SkipSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return false
+		}
+		i = SkipSpace(data, i+1)
+		end := SkipValue(data, i)
+		if end < 0 {
+			return false
+		}
+		if !fn(key, data[i:end]) {
+			return false
+		}
+		i = SkipSpace(data, end)
+		if i >= len(data) {
+			return false
+		}
+		switch data[i] {
+		case ',':
+			i = SkipSpace(data, i+1)
+		case '}':
+			return SkipSpace(data, i+1) == len(data)
+		default:
+			return false
+		}
+	}
+}
+
+// Valid reports whether data is a single valid JSON value, optionally surrounded by
+// whitespace. It is implemented as a non-recursive state machine and performs no
+// allocations for documents nested up to 64 levels deep, making it considerably cheaper
+// than json.Valid for checking payloads before their decoding is deferred. Like
+// json.Valid, invalid UTF-8 inside strings is not rejected.
+func Valid(data []byte) bool {
+	var buf [64]byte
+	stack := buf[:0]
+
+	i := SkipSpace(data, 0)
+	for {
+		// expect a value at data[i]
+		if i >= len(data) {
+			return false
+		}
+		switch c := data[i]; {
+		case c == '{':
+			i = SkipSpace(data, i+1)
+			if i < len(data) && data[i] == '}' {
+				i++
+				break
+			}
+			stack = append(stack, '{')
+			if i = scanKey(data, i); i < 0 {
+				return false
+			}
+			continue
+		case c == '[':
+			i = SkipSpace(data, i+1)
+			if i < len(data) && data[i] == ']' {
+				i++
+				break
+			}
+			stack = append(stack, '[')
+			continue
+		case c == '"':
+			i = String(data, i)
+		case c == '-' || c >= '0' && c <= '9':
+			i = Number(data, i)
+		case c == 't':
+			i = Literal(data, i, "true")
+		case c == 'f':
+			i = Literal(data, i, "false")
+		case c == 'n':
+			i = Literal(data, i, "null")
+		default:
+			return false
+		}
+		if i < 0 {
+			return false
+		}
+
+		// after a value: close containers or move on to the next element
+	next:
+		for {
+			i = SkipSpace(data, i)
+			if len(stack) == 0 {
+				return i == len(data)
+			}
+			if i >= len(data) {
+				return false
+			}
+			top := stack[len(stack)-1]
+			switch data[i] {
+			case ',':
+				i = SkipSpace(data, i+1)
+				if top == '{' {
+					if i = scanKey(data, i); i < 0 {
+						return false
+					}
+				}
+				break next
+			case ']', '}':
+				if (top == '[') != (data[i] == ']') {
+					return false
+				}
+				stack = stack[:len(stack)-1]
+				i++
+			default:
+				return false
+			}
+		}
+	}
+}
+
+// scanKey validates an object key and the following colon starting at data[i], and
+// returns the index of the member value, or -1 if malformed.
+func scanKey(data []byte, i int) int {
+	if i >= len(data) || data[i] != '"' {
+		return -1
+	}
+	if i = String(data, i); i < 0 {
+		return -1
+	}
+	i = SkipSpace(data, i)
+	if i >= len(data) || data[i] != ':' {
+		return -1
+	}
+	return SkipSpace(data, i+1)
+}
+
+// String validates the JSON string starting at data[i] and returns the index just
+// past it, or -1 if malformed.
+func String(data []byte, i int) int {
+	if i >= len(data) || data[i] != '"' {
+		return -1
+	}
+	for i++; i < len(data); i++ {
+		// fast path over unescaped content
+		for i < len(data) && data[i] >= 0x20 && data[i] != '"' && data[i] != '\\' {
+			i++
+		}
+		if i >= len(data) {
+			return -1
+		}
+		switch c := data[i]; {
+		case c == '"':
+			return i + 1
+		case c < 0x20:
+			return -1
+		case c == '\\':
+			i++
+			if i >= len(data) {
+				return -1
+			}
+			switch data[i] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				if i+4 >= len(data) {
+					return -1
+				}
+				for _, h := range data[i+1 : i+5] {
+					if !isHex(h) {
+						return -1
+					}
+				}
+				i += 4
+			default:
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// Number validates the JSON number starting at data[i] and returns the index just
+// past it, or -1 if malformed.
+func Number(data []byte, i int) int {
+	if i < len(data) && data[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(data) && data[i] == '0':
+		i++
+	case i < len(data) && data[i] >= '1' && data[i] <= '9':
+		i = scanDigits(data, i)
+	default:
+		return -1
+	}
+	if i < len(data) && data[i] == '.' {
+		j := scanDigits(data, i+1)
+		if j == i+1 {
+			return -1
+		}
+		i = j
+	}
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		i++
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			i++
+		}
+		j := scanDigits(data, i)
+		if j == i {
+			return -1
+		}
+		i = j
+	}
+	return i
+}
+
+// Scalar validates the JSON string, number or literal starting at data[i] and
+// returns the index just past it, or -1 if malformed.
+func Scalar(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+	switch c := data[i]; {
+	case c == '"':
+		return String(data, i)
+	case c == '-' || c >= '0' && c <= '9':
+		return Number(data, i)
+	case c == 't':
+		return Literal(data, i, "true")
+	case c == 'f':
+		return Literal(data, i, "false")
+	case c == 'n':
+		return Literal(data, i, "null")
+	}
+	return -1
+}
+
+// scanDigits returns the index of the first non-digit byte at or after data[i].
+func scanDigits(data []byte, i int) int {
+	for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+// Literal validates that lit appears at data[i] and returns the index just past it,
+// or -1 if it does not.
+func Literal(data []byte, i int, lit string) int {
+	if len(data)-i < len(lit) || string(data[i:i+len(lit)]) != lit {
+		return -1
+	}
+	return i + len(lit)
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// DepthExceeds reports whether arrays and objects in data are nested more than max
+// levels deep. Brackets inside strings are ignored; the data is not otherwise validated.
+func DepthExceeds(data []byte, max int) bool {
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			end := SkipString(data, i)
+			if end < 0 {
+				return false
+			}
+			i = end - 1
+		case '[', '{':
+			if depth++; depth > max {
+				return true
+			}
+		case ']', '}':
+			depth--
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package scan_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson/scan"
+)
+
+func TestNextValue(t *testing.T) {
+	data := []byte(` {"a": [1, "]"]} , 2`)
+	start, end := scan.NextValue(data, 0)
+	if string(data[start:end]) != `{"a": [1, "]"]}` {
+		t.Errorf("expected object, got %q", data[start:end])
+	}
+	start, end = scan.NextValue(data, scan.SkipSpace(data, end)+1)
+	if string(data[start:end]) != `2` {
+		t.Errorf("expected 2, got %q", data[start:end])
+	}
+	if _, end := scan.NextValue(data, len(data)); end != -1 {
+		t.Errorf("expected -1 past the end, got %d", end)
+	}
+	if end := scan.SkipValue([]byte(`[1, {]`), 0); end != -1 {
+		t.Errorf("expected -1 for unbalanced brackets, got %d", end)
+	}
+}
+
+func TestFieldBounds(t *testing.T) {
+	data := []byte(`{"id": 1, "items": [1, 2], "name": "x", "id": 2}`)
+	for key, want := range map[string]string{"items": `[1, 2]`, "name": `"x"`, "id": `2`} {
+		start, end, ok := scan.FieldBounds(data, key)
+		if !ok || string(data[start:end]) != want {
+			t.Errorf("%s: expected %s, got %q, %v", key, want, data[start:end], ok)
+		}
+	}
+	if _, _, ok := scan.FieldBounds(data, "ID"); ok {
+		t.Error("expected keys to be compared exactly")
+	}
+	if _, _, ok := scan.FieldBounds([]byte(`{"a":`), "a"); ok {
+		t.Error("expected malformed object to fail")
+	}
+}
+
+func TestElementsAndMembers(t *testing.T) {
+	elems, ok := scan.Elements([]byte(`[1, "a", [2]]`))
+	if !ok || len(elems) != 3 || string(elems[2]) != `[2]` {
+		t.Errorf("unexpected elements %q, %v", elems, ok)
+	}
+
+	var keys []string
+	ok = scan.Members([]byte(`{"a":1,"b":{}}`), func(key, val []byte) bool {
+		keys = append(keys, string(key))
+		return true
+	})
+	if !ok || len(keys) != 2 || keys[1] != `"b"` {
+		t.Errorf("unexpected members %q, %v", keys, ok)
+	}
+}
+
+func TestScalars(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		fn    func([]byte, int) int
+		want  int
+	}{
+		{`"a\"b" `, scan.String, 6},
+		{`"a`, scan.String, -1},
+		{`a"`, scan.String, -1},
+		{`-1.5e3,`, scan.Number, 6},
+		{`-`, scan.Number, -1},
+		{``, scan.Number, -1},
+		{`true`, scan.Scalar, 4},
+		{`[`, scan.Scalar, -1},
+	} {
+		if got := tc.fn([]byte(tc.input), 0); got != tc.want {
+			t.Errorf("%q: expected %d, got %d", tc.input, tc.want, got)
+		}
+	}
+	if !scan.Valid([]byte(` {"a":[1,true,null]} `)) || scan.Valid([]byte(`{"a":}`)) {
+		t.Error("unexpected Valid result")
+	}
+}
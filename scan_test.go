@@ -0,0 +1,30 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestScanValid(t *testing.T) {
+	inputs := []string{
+		// valid
+		`null`, `true`, `false`, `0`, `-0`, `1.5`, `-12e+3`, `1E-2`, `"text"`,
+		`"esc \" \\ \/ \b \f \n \r \t é"`, `[]`, `{}`, ` [ ] `, "\n{\n}\n",
+		`[1, "two", true, null, {"a": [1, {"b": {}}]}]`, `{"a":1,"b":[2,3],"c":{"d":null}}`,
+		strings.Repeat("[", 100) + strings.Repeat("]", 100),
+		// invalid
+		``, ` `, `nul`, `truex`, `01`, `1.`, `.5`, `1e`, `-`, `+1`, `"open`, `"bad \x"`,
+		`"\u12"`, "\"ctrl \x01\"", `[1,]`, `[1 2]`, `{"a"}`, `{"a":}`, `{a:1}`, `{"a":1,}`,
+		`[}`, `{]`, `[[]`, `[]]`, `{} {}`, `[1] x`, `{"a":1`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			want := json.Valid([]byte(input))
+			if got := ScanValid([]byte(input)); got != want {
+				t.Errorf("ScanValid(%q) = %v, want %v", input, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package jitjson
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// snapshotVersion is the version of the encoding produced by Snapshot.
+const snapshotVersion = 1
+
+// Snapshot encodes the state of items for Restore, so a long-running job can checkpoint
+// a partially processed lazy dataset and resume it later. Each item is recorded with
+// its raw encoding and parser, as by MarshalBinary, and whether it had been decoded.
+// Items holding only a value are marshaled first.
+func Snapshot[T any](items []*JitJSON[T]) ([]byte, error) {
+	buf := []byte{snapshotVersion}
+	buf = binary.AppendUvarint(buf, uint64(len(items)))
+	for i, item := range items {
+		if item == nil {
+			return nil, fmt.Errorf("jitjson: item %d is nil", i)
+		}
+		parsed := item.val != nil
+		enc, err := item.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		if parsed {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(enc)))
+		buf = append(buf, enc...)
+	}
+	return buf, nil
+}
+
+// Restore returns the items recorded by Snapshot, configured by opts. Items that had
+// been decoded are decoded again, so they are ready to use, while the others are
+// restored still lazy. The parsers recorded by Snapshot must be registered in this
+// process.
+func Restore[T any](data []byte, opts ...Option) ([]*JitJSON[T], error) {
+	if len(data) < 1 || data[0] != snapshotVersion {
+		return nil, errors.New("jitjson: unsupported snapshot encoding")
+	}
+	data = data[1:]
+	count, size := binary.Uvarint(data)
+	if size <= 0 || count > uint64(len(data)) {
+		return nil, errors.New("jitjson: truncated snapshot")
+	}
+	data = data[size:]
+
+	items := make([]*JitJSON[T], 0, count)
+	for i := 0; uint64(i) < count; i++ {
+		if len(data) < 1 {
+			return nil, errors.New("jitjson: truncated snapshot")
+		}
+		parsed := data[0] == 1
+		n, size := binary.Uvarint(data[1:])
+		if size <= 0 || uint64(len(data)-1-size) < n {
+			return nil, errors.New("jitjson: truncated snapshot")
+		}
+		enc := data[1+size : 1+size+int(n)]
+		data = data[1+size+int(n):]
+
+		item := &JitJSON[T]{opts: newOptions(opts)}
+		if err := item.UnmarshalBinary(enc); err != nil {
+			return nil, fmt.Errorf("jitjson: item %d: %w", i, err)
+		}
+		if parsed {
+			item.Unmarshal()
+		}
+		items = append(items, item)
+	}
+	if len(data) > 0 {
+		return nil, errors.New("jitjson: invalid data after snapshot")
+	}
+	return items, nil
+}
@@ -0,0 +1,54 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestSnapshot(t *testing.T) {
+	items := []*jitjson.JitJSON[Person]{
+		jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`)),
+		jitjson.NewFromBytes[Person]([]byte(`{"Name":"Jane","Age":25}`)),
+		jitjson.New(Person{Name: "Joe"}),
+		{},
+	}
+	if _, err := items[0].Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := jitjson.Snapshot(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	restored, err := jitjson.Restore[Person](data, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodes != 2 {
+		t.Errorf("expected only the decoded items to be decoded again, got %d", decodes)
+	}
+	if len(restored) != len(items) {
+		t.Fatalf("expected %d items, got %d", len(items), len(restored))
+	}
+
+	decodes = 0
+	for i, want := range []Person{{Name: "John", Age: 30}, {Name: "Jane", Age: 25}, {Name: "Joe"}, {}} {
+		got, err := restored[i].Unmarshal()
+		if err != nil || got != want {
+			t.Errorf("item %d: expected %+v, got %+v, %v", i, want, got, err)
+		}
+	}
+	if decodes != 1 {
+		t.Errorf("expected only the lazy item to be decoded on use, got %d", decodes)
+	}
+
+	for _, bad := range [][]byte{nil, {9}, data[:len(data)-1], append(data, 0)} {
+		if _, err := jitjson.Restore[Person](bad); err == nil {
+			t.Errorf("expected an error restoring %q", bad)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package jitjson
+
+import (
+	"bytes"
+	"sort"
+)
+
+// span locates a member or element within the encoding of its container. For elements,
+// only valStart and valEnd are set.
+type span struct {
+	key              string
+	keyStart, keyEnd int
+	valStart, valEnd int
+}
+
+// encode returns the encoding of the staged node. Containers copied by the Tx are
+// spliced from the encoding they were copied from; other nodes are encoded as usual.
+func (tx *Tx) encode(node *AnyJitJSON) ([]byte, error) {
+	orig, ok := tx.orig[node]
+	if !ok || node.data != nil {
+		return node.encoded()
+	}
+	var data []byte
+	var err error
+	switch c := node.val.(type) {
+	case map[string]*AnyJitJSON:
+		data, err = tx.spliceObject(orig, c)
+	case []*AnyJitJSON:
+		data, err = tx.spliceArray(orig, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	node.data = data
+	return data, nil
+}
+
+// spliceObject encodes the members of an object copied from orig. Members still present
+// keep their key, position and surrounding white space from orig, and later duplicates
+// of a key replace earlier ones; members added by the Tx follow in key order.
+func (tx *Tx) spliceObject(orig []byte, members map[string]*AnyJitJSON) ([]byte, error) {
+	var spans []span
+	last := map[string]int{}
+	splitObject(orig, func(key, val []byte) bool {
+		k, err := unquote(key)
+		if err != nil {
+			return false
+		}
+		ks, vs := offset(orig, key), offset(orig, val)
+		last[k] = len(spans)
+		spans = append(spans, span{key: k, keyStart: ks, keyEnd: ks + len(key), valStart: vs, valEnd: vs + len(val)})
+		return true
+	})
+
+	head, tail, sep := frame(orig, spans, '}')
+	colon := []byte{':'}
+	if len(spans) > 0 {
+		colon = orig[spans[0].keyEnd:spans[0].valStart]
+	}
+
+	out := append([]byte(nil), head...)
+	written := 0
+	for i, s := range spans {
+		member, ok := members[s.key]
+		if !ok || last[s.key] != i {
+			continue
+		}
+		if written > 0 {
+			out = append(out, orig[spans[i-1].valEnd:s.keyStart]...)
+		}
+		out = append(out, orig[s.keyStart:s.valStart]...)
+		b, err := tx.encode(member)
+		if err != nil {
+			return nil, err
+		}
+		out = appendValue(out, b)
+		written++
+	}
+
+	var added []string
+	for k := range members {
+		if _, ok := last[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	for _, k := range added {
+		if written > 0 {
+			out = append(out, sep...)
+		}
+		var err error
+		if out, err = appendString(out, k); err != nil {
+			return nil, err
+		}
+		out = append(out, colon...)
+		b, err := tx.encode(members[k])
+		if err != nil {
+			return nil, err
+		}
+		out = appendValue(out, b)
+		written++
+	}
+	return append(out, tail...), nil
+}
+
+// spliceArray encodes the elements of an array copied from orig, separating them as
+// the elements at the same positions in orig were.
+func (tx *Tx) spliceArray(orig []byte, elems []*AnyJitJSON) ([]byte, error) {
+	raw, _ := splitArray(orig)
+	spans := make([]span, len(raw))
+	for i, elem := range raw {
+		start := offset(orig, elem)
+		spans[i] = span{valStart: start, valEnd: start + len(elem)}
+	}
+
+	head, tail, sep := frame(orig, spans, ']')
+	out := append([]byte(nil), head...)
+	for i, elem := range elems {
+		switch {
+		case i > 0 && i < len(spans):
+			out = append(out, orig[spans[i-1].valEnd:spans[i].valStart]...)
+		case i > 0:
+			out = append(out, sep...)
+		}
+		b, err := tx.encode(elem)
+		if err != nil {
+			return nil, err
+		}
+		out = appendValue(out, b)
+	}
+	return append(out, tail...), nil
+}
+
+// frame returns the bytes of orig before its first member or element, those after its
+// last, and the last separator between two of them, or a comma if there is none.
+func frame(orig []byte, spans []span, close byte) (head, tail, sep []byte) {
+	sep = []byte{','}
+	if len(spans) == 0 {
+		i := bytes.LastIndexByte(orig, close)
+		return orig[:i], orig[i:], sep
+	}
+	first, last := spans[0], spans[len(spans)-1]
+	start := first.valStart
+	if first.keyEnd > 0 {
+		start = first.keyStart
+	}
+	if len(spans) > 1 {
+		prev := spans[len(spans)-2]
+		end := last.valStart
+		if last.keyEnd > 0 {
+			end = last.keyStart
+		}
+		sep = orig[prev.valEnd:end]
+	}
+	return orig[:start], orig[last.valEnd:], sep
+}
+
+// offset returns the position of sub within data, of which it is a sub-slice.
+func offset(data, sub []byte) int {
+	return cap(data) - cap(sub)
+}
@@ -0,0 +1,70 @@
+package jitjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SplitObject reads a single JSON object from r and returns its members as AnyJitJSON
+// values holding their raw bytes, without decoding them. Only the object's structure is
+// parsed, so a large document can be dispatched field by field to different
+// processors. Like encoding/json, the last of duplicate members wins. Members exceeding
+// the limits set by Configure are rejected. Use SplitObjectFunc to avoid holding every member
+// in memory at once.
+func SplitObject(r io.Reader) (map[string]*AnyJitJSON, error) {
+	members := map[string]*AnyJitJSON{}
+	err := SplitObjectFunc(r, func(key string, val *AnyJitJSON) error {
+		members[key] = val
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// SplitObjectFunc reads a single JSON object from r like SplitObject, calling fn with
+// each member in order as soon as it has been read. Each member is read into memory in
+// turn, but the object as a whole never is. If fn returns an error, reading stops and
+// the error is returned.
+func SplitObjectFunc(r io.Reader, fn func(key string, val *AnyJitJSON) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return errors.New("jitjson: expected a json object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("jitjson: member %q: %w", key, err)
+		}
+		if err := (*options)(nil).checkLimits(raw); err != nil {
+			return fmt.Errorf("jitjson: member %q: %w", key, err)
+		}
+		val := &AnyJitJSON{}
+		if err := val.set(raw); err != nil {
+			return fmt.Errorf("jitjson: member %q: %w", key, err)
+		}
+		if err := fn(key, val); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("jitjson: invalid character after top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package jitjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestSplitObject(t *testing.T) {
+	input := `{"users": [{"name": "a"}, {"name": "b"}], "count": 2, "meta": {"v": "1"}, "count": 3}`
+	members, err := jitjson.SplitObject(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 3 {
+		t.Fatalf("expected 3 members, got %d", len(members))
+	}
+	if users, ok := members["users"].AsArray(); !ok || len(users) != 2 {
+		t.Errorf("expected two users, got %v", members["users"])
+	}
+	if n, ok := members["count"].AsNumber(); !ok || n != "3" {
+		t.Errorf("expected the last count, got %v", n)
+	}
+
+	for _, input := range []string{`[1]`, `{"a":}`, `{"a":1`, `{"a":1} {}`, ``} {
+		if _, err := jitjson.SplitObject(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestSplitObjectFunc(t *testing.T) {
+	input := `{"a": 1, "b": "x", "c": null}`
+	var keys []string
+	err := jitjson.SplitObjectFunc(strings.NewReader(input), func(key string, val *jitjson.AnyJitJSON) error {
+		keys = append(keys, key+":"+val.Type().String())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, ",") != "a:TypeNumber,b:TypeString,c:TypeNull" {
+		t.Errorf("unexpected members %v", keys)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = jitjson.SplitObjectFunc(strings.NewReader(input), func(string, *jitjson.AnyJitJSON) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Errorf("expected to stop after the first member, got %v after %d calls", err, calls)
+	}
+}
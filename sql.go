@@ -0,0 +1,76 @@
+package jitjson
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// scanBytes copies a database value into a new byte slice, since drivers such as
+// pgx reuse their read buffers between rows. A nil value returns nil.
+func scanBytes(src any) ([]byte, error) {
+	switch src := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return append([]byte(nil), src...), nil
+	case string:
+		return []byte(src), nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T into jitjson", src)
+	}
+}
+
+// Scan implements sql.Scanner so json and jsonb columns can be scanned into JitJSON[T]
+// without decoding. The bytes are copied from the driver's buffer once and decoded
+// only when Unmarshal is called. A NULL column resets JitJSON[T] to empty. This works
+// with database/sql drivers and with pgx, which prefers sql.Scanner for jsonb targets;
+// the jitpgx module registers codecs that skip pgx's intermediate copy.
+func (jit *JitJSON[T]) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		jit.val = nil
+		jit.data = nil
+		return nil
+	}
+	return jit.UnmarshalJSON(data)
+}
+
+// Value implements driver.Valuer so JitJSON[T] can be used as a query argument. The
+// value is marshaled on demand, or its stored bytes are passed as-is. An empty
+// JitJSON[T] is written as NULL.
+func (jit *JitJSON[T]) Value() (driver.Value, error) {
+	data, err := jit.Marshal()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Scan implements sql.Scanner so json and jsonb columns can be scanned into AnyJitJSON.
+// The bytes are copied from the driver's buffer once. A NULL column is stored as null.
+func (a *AnyJitJSON) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = []byte("null")
+	}
+	if !ScanValid(data) {
+		return errors.New("cannot scan invalid json into jitjson")
+	}
+	return a.set(data)
+}
+
+// Value implements driver.Valuer so AnyJitJSON can be used as a query argument. The
+// stored bytes are passed as-is, and null is written as NULL.
+func (a *AnyJitJSON) Value() (driver.Value, error) {
+	if a == nil || a.IsNull() {
+		return nil, nil
+	}
+	return a.encoded()
+}
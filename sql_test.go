@@ -0,0 +1,78 @@
+package jitjson_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+var (
+	_ sql.Scanner   = (*jitjson.JitJSON[Person])(nil)
+	_ driver.Valuer = (*jitjson.JitJSON[Person])(nil)
+	_ sql.Scanner   = (*jitjson.AnyJitJSON)(nil)
+	_ driver.Valuer = (*jitjson.AnyJitJSON)(nil)
+)
+
+func TestJitJSON_Scan(t *testing.T) {
+	buf := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+
+	var jit jitjson.JitJSON[Person]
+	if err := jit.Scan(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// drivers reuse their buffers between rows
+	copy(buf, `{"Name":"Jane"}                            `)
+
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" || p.Age != 30 {
+		t.Error("expected scanned bytes to be copied")
+	}
+
+	v, err := jit.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v.([]byte)) != `{"Name":"John","Age":30,"City":"New York"}` {
+		t.Errorf("unexpected value %s", v)
+	}
+
+	if err := jit.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := jit.Value(); v != nil {
+		t.Errorf("expected NULL, got %v", v)
+	}
+
+	if err := jit.Scan(42); err == nil {
+		t.Error("expected error for unsupported source")
+	}
+}
+
+func TestAnyJitJSON_Scan(t *testing.T) {
+	var a jitjson.AnyJitJSON
+	if err := a.Scan(`{"key": [1, 2]}`); err != nil {
+		t.Fatal(err)
+	}
+	if a.Type() != jitjson.TypeObject {
+		t.Errorf("expected TypeObject, got %v", a.Type())
+	}
+	if v, _ := a.Value(); string(v.([]byte)) != `{"key": [1, 2]}` {
+		t.Errorf("unexpected value %s", v)
+	}
+
+	if err := a.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsNull() {
+		t.Error("expected null")
+	}
+	if err := a.Scan([]byte(`{"key":`)); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
@@ -0,0 +1,170 @@
+package jitjson
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stats holds the package-wide counters reported by Stats.
+var stats struct {
+	deferredMarshals   atomic.Uint64
+	deferredUnmarshals atomic.Uint64
+	marshals           atomic.Uint64
+	unmarshals         atomic.Uint64
+	marshalCacheHits   atomic.Uint64
+	unmarshalCacheHits atomic.Uint64
+	bytesDeferred      atomic.Uint64
+	bytesDecoded       atomic.Uint64
+}
+
+// StatsSnapshot is a point-in-time copy of the package-wide parse counters. All
+// counters are cumulative since the process started; use Delta to measure an interval.
+type StatsSnapshot struct {
+	// DeferredMarshals counts values stored without being encoded (New, Set).
+	DeferredMarshals uint64
+	// DeferredUnmarshals counts encodings stored without being decoded
+	// (NewFromBytes, UnmarshalJSON).
+	DeferredUnmarshals uint64
+	// Marshals counts encodings actually performed by Marshal.
+	Marshals uint64
+	// Unmarshals counts decodings actually performed by Unmarshal.
+	Unmarshals uint64
+	// MarshalCacheHits counts Marshal calls answered from stored bytes.
+	MarshalCacheHits uint64
+	// UnmarshalCacheHits counts Unmarshal calls answered from a stored value.
+	UnmarshalCacheHits uint64
+	// BytesDeferred counts the encoded bytes stored for deferred decoding. Like the
+	// other counters it only grows; it is not the number of bytes still held.
+	BytesDeferred uint64
+	// BytesDecoded counts the encoded bytes decoded by Unmarshal after being deferred.
+	BytesDecoded uint64
+}
+
+// Stats returns a snapshot of the package-wide counters, which show how much parsing
+// has been avoided by deferring it. Counters are updated atomically, so Stats is safe
+// to call from any goroutine.
+func Stats() StatsSnapshot {
+	return StatsSnapshot{
+		DeferredMarshals:   stats.deferredMarshals.Load(),
+		DeferredUnmarshals: stats.deferredUnmarshals.Load(),
+		Marshals:           stats.marshals.Load(),
+		Unmarshals:         stats.unmarshals.Load(),
+		MarshalCacheHits:   stats.marshalCacheHits.Load(),
+		UnmarshalCacheHits: stats.unmarshalCacheHits.Load(),
+		BytesDeferred:      stats.bytesDeferred.Load(),
+		BytesDecoded:       stats.bytesDecoded.Load(),
+	}
+}
+
+// Delta returns the change in each counter since prev, an earlier snapshot.
+func (s StatsSnapshot) Delta(prev StatsSnapshot) StatsSnapshot {
+	return StatsSnapshot{
+		DeferredMarshals:   s.DeferredMarshals - prev.DeferredMarshals,
+		DeferredUnmarshals: s.DeferredUnmarshals - prev.DeferredUnmarshals,
+		Marshals:           s.Marshals - prev.Marshals,
+		Unmarshals:         s.Unmarshals - prev.Unmarshals,
+		MarshalCacheHits:   s.MarshalCacheHits - prev.MarshalCacheHits,
+		UnmarshalCacheHits: s.UnmarshalCacheHits - prev.UnmarshalCacheHits,
+		BytesDeferred:      s.BytesDeferred - prev.BytesDeferred,
+		BytesDecoded:       s.BytesDecoded - prev.BytesDecoded,
+	}
+}
+
+// AvoidedUnmarshals returns the number of deferred encodings that were never decoded.
+func (s StatsSnapshot) AvoidedUnmarshals() uint64 {
+	if s.Unmarshals > s.DeferredUnmarshals {
+		return 0
+	}
+	return s.DeferredUnmarshals - s.Unmarshals
+}
+
+// AvoidedMarshals returns the number of deferred values that were never encoded.
+func (s StatsSnapshot) AvoidedMarshals() uint64 {
+	if s.Marshals > s.DeferredMarshals {
+		return 0
+	}
+	return s.DeferredMarshals - s.Marshals
+}
+
+// UndecodedBytes returns the number of deferred encoded bytes that were never decoded.
+// Unlike the counters it can go down, as deferred encodings are decoded, so it is
+// reported as a gauge.
+func (s StatsSnapshot) UndecodedBytes() uint64 {
+	if s.BytesDecoded > s.BytesDeferred {
+		return 0
+	}
+	return s.BytesDeferred - s.BytesDecoded
+}
+
+// recordDeferredUnmarshal records that n encoded bytes were stored without decoding.
+func recordDeferredUnmarshal(n int) {
+	stats.deferredUnmarshals.Add(1)
+	stats.bytesDeferred.Add(uint64(n))
+}
+
+// recordUnmarshal records that n deferred encoded bytes were decoded.
+func recordUnmarshal(n int) {
+	stats.unmarshals.Add(1)
+	stats.bytesDecoded.Add(uint64(n))
+}
+
+// timings enables the latency counters reported by ParserTimings.
+var timings atomic.Bool
+
+// EnableParserTimings starts recording the latency of the encodings and decodings
+// performed with each parser, as reported by ParserTimings. It is off by default, as it
+// reads the clock around every encoding and decoding; the jitmetrics module enables it.
+func EnableParserTimings() {
+	timings.Store(true)
+}
+
+// parserCounters holds the latency counters of a single parser.
+type parserCounters struct {
+	marshals       atomic.Uint64
+	marshalNanos   atomic.Uint64
+	unmarshals     atomic.Uint64
+	unmarshalNanos atomic.Uint64
+}
+
+// parserStats maps parser names to their *parserCounters.
+var parserStats sync.Map
+
+// countersFor returns the latency counters of the parser registered under name.
+func countersFor(name string) *parserCounters {
+	if c, ok := parserStats.Load(name); ok {
+		return c.(*parserCounters)
+	}
+	c, _ := parserStats.LoadOrStore(name, &parserCounters{})
+	return c.(*parserCounters)
+}
+
+// ParserTiming is a point-in-time copy of the latency counters of a parser. Like
+// StatsSnapshot, the counters are cumulative since the process started.
+type ParserTiming struct {
+	// Marshals counts encodings performed with the parser.
+	Marshals uint64
+	// MarshalTime is the total time spent in those encodings.
+	MarshalTime time.Duration
+	// Unmarshals counts decodings performed with the parser.
+	Unmarshals uint64
+	// UnmarshalTime is the total time spent in those decodings.
+	UnmarshalTime time.Duration
+}
+
+// ParserTimings returns the latency counters of every parser that has been used by
+// Marshal or Unmarshal since EnableParserTimings was called, keyed by parser name.
+func ParserTimings() map[string]ParserTiming {
+	timings := map[string]ParserTiming{}
+	parserStats.Range(func(name, c any) bool {
+		pc := c.(*parserCounters)
+		timings[name.(string)] = ParserTiming{
+			Marshals:      pc.marshals.Load(),
+			MarshalTime:   time.Duration(pc.marshalNanos.Load()),
+			Unmarshals:    pc.unmarshals.Load(),
+			UnmarshalTime: time.Duration(pc.unmarshalNanos.Load()),
+		}
+		return true
+	})
+	return timings
+}
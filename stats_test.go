@@ -0,0 +1,114 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestStats(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+	before := jitjson.Stats()
+
+	jits := []*jitjson.JitJSON[Person]{
+		jitjson.NewFromBytes[Person](jsonData),
+		jitjson.NewFromBytes[Person](jsonData),
+		jitjson.NewFromBytes[Person](jsonData),
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := jits[0].Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jit := jitjson.New(Person{Name: "Jane"})
+	jitjson.New(Person{Name: "Jim"})
+	for i := 0; i < 3; i++ {
+		if _, err := jit.Marshal(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	delta := jitjson.Stats().Delta(before)
+	want := jitjson.StatsSnapshot{
+		DeferredMarshals:   2,
+		DeferredUnmarshals: 3,
+		Marshals:           1,
+		Unmarshals:         1,
+		MarshalCacheHits:   2,
+		UnmarshalCacheHits: 1,
+		BytesDeferred:      uint64(3 * len(jsonData)),
+		BytesDecoded:       uint64(len(jsonData)),
+	}
+	if delta != want {
+		t.Errorf("expected %+v, got %+v", want, delta)
+	}
+	if delta.AvoidedUnmarshals() != 2 {
+		t.Errorf("expected 2 avoided unmarshals, got %d", delta.AvoidedUnmarshals())
+	}
+	if n := delta.UndecodedBytes(); n != uint64(2*len(jsonData)) {
+		t.Errorf("expected %d undecoded bytes, got %d", 2*len(jsonData), n)
+	}
+	if delta.AvoidedMarshals() != 1 {
+		t.Errorf("expected 1 avoided marshal, got %d", delta.AvoidedMarshals())
+	}
+}
+
+func TestStatsBytesDeferred(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+	tests := []struct {
+		name     string
+		new      func()
+		deferred uint64
+	}{
+		{"Project", func() {
+			jitjson.Project[Person, struct{ Name string }](jitjson.NewFromBytes[Person](jsonData))
+		}, 2},
+		{"ToJit", func() {
+			a, _ := jitjson.NewAny(jsonData)
+			jitjson.ToJit[Person](a)
+		}, 1},
+		{"Dedup", func() {
+			store := jitjson.NewDedup[Person](false, 0)
+			store.NewFromBytes(jsonData)
+			store.NewFromBytes(jsonData)
+		}, 2},
+		{"Dedup shared value", func() {
+			store := jitjson.NewDedup[Person](true, 0)
+			store.NewFromBytes(jsonData)
+			store.NewFromBytes(jsonData)
+		}, 1},
+	}
+	for _, tc := range tests {
+		before := jitjson.Stats()
+		tc.new()
+		delta := jitjson.Stats().Delta(before)
+		if delta.DeferredUnmarshals != tc.deferred || delta.BytesDeferred != tc.deferred*uint64(len(jsonData)) {
+			t.Errorf("%s: expected %d encodings and %d bytes deferred, got %d and %d", tc.name, tc.deferred, tc.deferred*uint64(len(jsonData)), delta.DeferredUnmarshals, delta.BytesDeferred)
+		}
+	}
+}
+
+func TestParserTimings(t *testing.T) {
+	jitjson.EnableParserTimings()
+	before := jitjson.ParserTimings()[jitjson.DefaultParser]
+
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`))
+	if _, err := jit.Unmarshal(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jitjson.New(Person{Name: "Jane"}).Marshal(); err != nil {
+		t.Fatal(err)
+	}
+
+	after, ok := jitjson.ParserTimings()[jitjson.DefaultParser]
+	if !ok {
+		t.Fatal("expected timings for the default parser")
+	}
+	if after.Marshals-before.Marshals != 1 || after.Unmarshals-before.Unmarshals != 1 {
+		t.Errorf("expected one marshal and one unmarshal, got %+v", after)
+	}
+	if after.MarshalTime < before.MarshalTime || after.UnmarshalTime < before.UnmarshalTime {
+		t.Errorf("expected latencies to accumulate, got %+v", after)
+	}
+}
@@ -0,0 +1,140 @@
+package jitjson
+
+import (
+	"bytes"
+	"sync"
+	"unsafe"
+)
+
+// SwapBytes stores data as the encoding of JitJSON[T], discarding any decoded value, and
+// returns the stored bytes it replaces, or nil if there were none. The data is checked
+// and held as by UnmarshalJSON; if it is rejected, the error is returned and nothing is
+// replaced.
+//
+// JitJSON[T] is not safe for concurrent use; caches where refresher goroutines replace
+// payloads while readers decode them should hold a SyncJitJSON[T] instead.
+func (jit *JitJSON[T]) SwapBytes(data []byte) (old []byte, err error) {
+	old = jit.data
+	if err := jit.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+// CompareAndSwapBytes stores data as the encoding of JitJSON[T] like SwapBytes, but only
+// if the stored encoding is still old, so a refresher can detect that another replaced
+// the payload since it was read. old matches the bytes returned by an earlier SwapBytes,
+// or the encoding Marshal returns for the stored bytes, such as the decrypted or
+// canonical encoding under WithBytesTransform or WithCanonical. A nil old matches a
+// JitJSON[T] holding no encoding.
+func (jit *JitJSON[T]) CompareAndSwapBytes(old, data []byte) (swapped bool, err error) {
+	if !jit.holds(old) {
+		return false, nil
+	}
+	if _, err := jit.SwapBytes(data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// holds reports whether the encoding stored by JitJSON[T] is old, either as the stored
+// bytes or as Marshal returns them.
+func (jit *JitJSON[T]) holds(old []byte) bool {
+	if jit.data == nil || old == nil {
+		return jit.data == nil && old == nil
+	}
+	if sameBytes(jit.data, old) {
+		return true
+	}
+	data, err := jit.opts.load(jit.data)
+	if err != nil {
+		return false
+	}
+	if jit.opts.canonicalizes() && !jit.isCanonical() {
+		if data, err = Canonicalize(data); err != nil {
+			return false
+		}
+	}
+	return bytes.Equal(data, old)
+}
+
+// sameBytes reports whether a and b are the same slice of the same array.
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (a == nil) == (b == nil) && unsafe.SliceData(a) == unsafe.SliceData(b)
+}
+
+// SyncJitJSON[T] is a JitJSON[T] guarded by a mutex, for values shared between
+// goroutines, such as cache entries whose payload refresher goroutines replace while
+// readers decode it. Each method holds the lock for its duration, so SwapBytes and
+// CompareAndSwapBytes are atomic with respect to the others, and the payload is decoded
+// once however many readers ask for it. The zero value holds no value and uses the
+// default options.
+type SyncJitJSON[T any] struct {
+	mu  sync.Mutex
+	jit *JitJSON[T]
+}
+
+// NewSync returns a SyncJitJSON[T] holding jit, which must not be used directly
+// afterwards.
+func NewSync[T any](jit *JitJSON[T]) *SyncJitJSON[T] {
+	return &SyncJitJSON[T]{jit: jit}
+}
+
+// lock locks s and returns the JitJSON[T] it holds.
+func (s *SyncJitJSON[T]) lock() *JitJSON[T] {
+	s.mu.Lock()
+	if s.jit == nil {
+		s.jit = &JitJSON[T]{}
+	}
+	return s.jit
+}
+
+// Unmarshal returns the value, decoding it as by JitJSON[T].Unmarshal.
+func (s *SyncJitJSON[T]) Unmarshal() (T, error) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.Unmarshal()
+}
+
+// Marshal returns the encoding of the value as by JitJSON[T].Marshal.
+func (s *SyncJitJSON[T]) Marshal() ([]byte, error) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.Marshal()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *SyncJitJSON[T]) MarshalJSON() ([]byte, error) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SyncJitJSON[T]) UnmarshalJSON(data []byte) error {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.UnmarshalJSON(data)
+}
+
+// Set sets the value as by JitJSON[T].Set.
+func (s *SyncJitJSON[T]) Set(val T) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	jit.Set(val)
+}
+
+// SwapBytes atomically replaces the stored encoding as by JitJSON[T].SwapBytes.
+func (s *SyncJitJSON[T]) SwapBytes(data []byte) (old []byte, err error) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.SwapBytes(data)
+}
+
+// CompareAndSwapBytes atomically replaces the stored encoding if it is still old, as by
+// JitJSON[T].CompareAndSwapBytes.
+func (s *SyncJitJSON[T]) CompareAndSwapBytes(old, data []byte) (swapped bool, err error) {
+	jit := s.lock()
+	defer s.mu.Unlock()
+	return jit.CompareAndSwapBytes(old, data)
+}
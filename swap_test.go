@@ -0,0 +1,128 @@
+package jitjson_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestSwapBytes(t *testing.T) {
+	first := []byte(`{"Name":"John"}`)
+	second := []byte(`{"Name":"Jane"}`)
+
+	jit := jitjson.NewFromBytes[Person](first)
+	if p, _ := jit.Unmarshal(); p.Name != "John" {
+		t.Fatalf("expected John, got %s", p.Name)
+	}
+	if old, err := jit.SwapBytes(second); err != nil || string(old) != string(first) {
+		t.Errorf("expected old encoding %s, got %s, %v", first, old, err)
+	}
+	if p, _ := jit.Unmarshal(); p.Name != "Jane" {
+		t.Errorf("expected decoded value to be discarded, got %s", p.Name)
+	}
+
+	var empty jitjson.JitJSON[Person]
+	if old, err := empty.SwapBytes(first); err != nil || old != nil {
+		t.Errorf("expected nil, got %s, %v", old, err)
+	}
+
+	limited := jitjson.NewFromBytes[Person](first, jitjson.WithMaxBytes(16))
+	if _, err := limited.SwapBytes([]byte(`{"Name":"Jacqueline"}`)); !jitjson.IsLimitError(err) {
+		t.Errorf("expected a limit error, got %v", err)
+	}
+	if p, _ := limited.Unmarshal(); p.Name != "John" {
+		t.Errorf("expected the rejected swap to keep John, got %s", p.Name)
+	}
+
+	transformed := jitjson.NewFromBytes[Person](first, jitjson.WithBytesTransform(shortTransform{max: 16}))
+	if _, err := transformed.SwapBytes([]byte(`{"Name":"Jacqueline"}`)); !errors.Is(err, errTransform) {
+		t.Errorf("expected a transform error, got %v", err)
+	}
+	if data, err := transformed.Marshal(); err != nil || string(data) != string(first) {
+		t.Errorf("expected the failed swap to keep %s, got %s, %v", first, data, err)
+	}
+	if p, err := transformed.Unmarshal(); err != nil || p.Name != "John" {
+		t.Errorf("expected the failed swap to keep John, got %s, %v", p.Name, err)
+	}
+}
+
+// shortTransform stores encodings of at most max bytes unchanged and fails to store
+// longer ones.
+type shortTransform struct {
+	max int
+}
+
+func (s shortTransform) Store(data []byte) ([]byte, error) {
+	if len(data) > s.max {
+		return nil, errTransform
+	}
+	return data, nil
+}
+
+func (shortTransform) Load(stored []byte) ([]byte, error) { return stored, nil }
+
+func TestCompareAndSwapBytes(t *testing.T) {
+	first := []byte(`{"Name":"John"}`)
+	jit := jitjson.NewFromBytes[Person](first)
+
+	current, _ := jit.Marshal()
+	if swapped, err := jit.CompareAndSwapBytes(current, []byte(`{"Name":"Jane"}`)); !swapped || err != nil {
+		t.Errorf("expected swap against the stored encoding to succeed, got %v", err)
+	}
+	if swapped, _ := jit.CompareAndSwapBytes(current, []byte(`{"Name":"Jill"}`)); swapped {
+		t.Error("expected swap against a replaced encoding to fail")
+	}
+	if p, _ := jit.Unmarshal(); p.Name != "Jane" {
+		t.Errorf("expected Jane, got %s", p.Name)
+	}
+
+	var empty jitjson.JitJSON[Person]
+	if swapped, _ := empty.CompareAndSwapBytes(nil, first); !swapped {
+		t.Error("expected nil to match an empty value")
+	}
+
+	canonical := jitjson.NewFromBytes[Person]([]byte(`{ "Name" : "John" }`), jitjson.WithCanonical())
+	current, _ = canonical.Marshal()
+	if swapped, _ := canonical.CompareAndSwapBytes(current, []byte(`{"Name":"Jane"}`)); !swapped {
+		t.Error("expected swap against the canonical encoding to succeed")
+	}
+}
+
+func TestSyncJitJSON(t *testing.T) {
+	shared := jitjson.NewSync(jitjson.NewFromBytes[Person]([]byte(`{"Name":"v0"}`)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				current, _ := shared.Marshal()
+				if _, err := shared.CompareAndSwapBytes(current, []byte(`{"Name":"next"}`)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := shared.Unmarshal(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if p, _ := shared.Unmarshal(); p.Name != "next" {
+		t.Errorf("expected next, got %s", p.Name)
+	}
+
+	var zero jitjson.SyncJitJSON[Person]
+	if old, err := zero.SwapBytes([]byte(`{"Name":"John"}`)); old != nil || err != nil {
+		t.Errorf("expected no old encoding, got %s, %v", old, err)
+	}
+}
@@ -0,0 +1,151 @@
+package jitjson
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// Table gives lazy access to the rows and cells of tabular JSON, an array of arrays
+// such as [["id","name"],[1,"John"],[2,"Jane"]], as produced by many analytics and
+// spreadsheet exports. Rows are located by scanning the outer array once, on first
+// access, and cells by scanning their row, so reading a few cells of a wide dataset
+// decodes only those cells. Like JitJSON[T], a Table is not safe for concurrent use.
+type Table struct {
+	data []byte
+	rows [][]byte
+}
+
+// NewTable returns a Table over the JSON array data, which is not copied. Only the
+// outer brackets are checked; rows are checked as they are scanned.
+func NewTable(data []byte) (*Table, error) {
+	if !isContainer(data, '[', ']') {
+		return nil, errors.New("jitjson: table is not a json array")
+	}
+	return &Table{data: data}, nil
+}
+
+// index locates the rows of the table on first use.
+func (t *Table) index() ([][]byte, error) {
+	if t.rows != nil {
+		return t.rows, nil
+	}
+	rows, ok := splitArray(t.data)
+	if !ok {
+		return nil, errors.New("jitjson: invalid json array")
+	}
+	if rows == nil {
+		rows = [][]byte{}
+	}
+	t.rows = rows
+	return rows, nil
+}
+
+// Len returns the number of rows.
+func (t *Table) Len() (int, error) {
+	rows, err := t.index()
+	return len(rows), err
+}
+
+// Rows returns an iterator over the rows, each held as an AnyJitJSON without being
+// parsed. Iteration stops early if the table is malformed; use Len to check it.
+func (t *Table) Rows() iter.Seq[*AnyJitJSON] {
+	return func(yield func(*AnyJitJSON) bool) {
+		rows, err := t.index()
+		if err != nil {
+			return
+		}
+		for _, raw := range rows {
+			row := &AnyJitJSON{}
+			if row.set(raw) != nil || !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+// rowBytes returns the raw encoding of row i.
+func (t *Table) rowBytes(i int) ([]byte, error) {
+	rows, err := t.index()
+	if err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= len(rows) {
+		return nil, fmt.Errorf("jitjson: row %d out of range [0, %d)", i, len(rows))
+	}
+	return rows[i], nil
+}
+
+// Row returns row i as an AnyJitJSON without parsing it.
+func (t *Table) Row(i int) (*AnyJitJSON, error) {
+	raw, err := t.rowBytes(i)
+	if err != nil {
+		return nil, err
+	}
+	row := &AnyJitJSON{}
+	if err := row.set(raw); err != nil {
+		return nil, fmt.Errorf("jitjson: row %d: %w", i, err)
+	}
+	return row, nil
+}
+
+// Cell returns the value in column col of row, scanning the row only as far as the
+// cell. A column beyond the end of a row is an error.
+func (t *Table) Cell(row, col int) (*AnyJitJSON, error) {
+	raw, err := t.rowBytes(row)
+	if err != nil {
+		return nil, err
+	}
+	cell, ok, err := nthElement(raw, col)
+	if err != nil {
+		return nil, fmt.Errorf("jitjson: row %d: %w", row, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("jitjson: row %d: column %d out of range", row, col)
+	}
+	a := &AnyJitJSON{}
+	if err := a.set(cell); err != nil {
+		return nil, fmt.Errorf("jitjson: row %d, column %d: %w", row, col, err)
+	}
+	return a, nil
+}
+
+// TableRow returns row i of t as a JitJSON[T] configured by opts, decoded into T, such
+// as []float64 or a type with its own UnmarshalJSON, only when its Unmarshal is called.
+func TableRow[T any](t *Table, i int, opts ...Option) (*JitJSON[T], error) {
+	raw, err := t.rowBytes(i)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromBytes[T](raw, opts...), nil
+}
+
+// nthElement returns the raw element n of the JSON array data, skipping the elements
+// before it without splitting the rest of the array. It reports false if the array has
+// no element n.
+func nthElement(data []byte, n int) ([]byte, bool, error) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return nil, false, errors.New("row is not a json array")
+	}
+	if n < 0 {
+		return nil, false, nil
+	}
+	i = skipSpace(data, i+1)
+	for col := 0; i < len(data) && data[i] != ']'; col++ {
+		end := valueEnd(data, i)
+		if end < 0 {
+			return nil, false, errors.New("invalid json array")
+		}
+		if col == n {
+			return data[i:end], true, nil
+		}
+		i = skipSpace(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		} else if i >= len(data) || data[i] != ']' {
+			return nil, false, errors.New("invalid json array")
+		}
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,62 @@
+package jitjson_test
+
+import (
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestTable(t *testing.T) {
+	table, err := jitjson.NewTable([]byte(`[
+		["id", "name", "score"],
+		[1, "John", 9.5],
+		[2, "Jane", 7]
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := table.Len(); err != nil || n != 3 {
+		t.Errorf("expected 3 rows, got %d, %v", n, err)
+	}
+
+	cell, err := table.Cell(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := cell.AsString(); !ok || name != "Jane" {
+		t.Errorf("expected Jane, got %q", name)
+	}
+	if cell, err := table.Cell(1, 2); err != nil || cell.Type() != jitjson.TypeNumber {
+		t.Errorf("expected a number, got %v, %v", cell, err)
+	}
+	if _, err := table.Cell(1, 3); err == nil {
+		t.Error("expected an error for a column beyond the row")
+	}
+	if _, err := table.Cell(3, 0); err == nil {
+		t.Error("expected an error for a row beyond the table")
+	}
+
+	var types []jitjson.ValueType
+	for row := range table.Rows() {
+		types = append(types, row.Type())
+	}
+	if len(types) != 3 || types[0] != jitjson.TypeArray {
+		t.Errorf("expected 3 array rows, got %v", types)
+	}
+
+	row, err := jitjson.TableRow[[3]any](table, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := row.Unmarshal(); err != nil || v[1] != "John" || v[2] != 9.5 {
+		t.Errorf("expected [1 John 9.5], got %v, %v", v, err)
+	}
+
+	if _, err := jitjson.NewTable([]byte(`{"rows": []}`)); err == nil {
+		t.Error("expected an error for a non-array table")
+	}
+	broken, _ := jitjson.NewTable([]byte(`[[1] [2]]`))
+	if _, err := broken.Len(); err == nil {
+		t.Error("expected an error for a malformed table")
+	}
+}
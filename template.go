@@ -0,0 +1,90 @@
+package jitjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// TemplateFuncs returns functions for text/template and html/template that read
+// JitJSON[T] and AnyJitJSON values, decoding only the parts a template references, so
+// dashboards over large payloads do not force full decodes:
+//
+//	tmpl := template.New("page").Funcs(jitjson.TemplateFuncs())
+//
+// The functions are:
+//
+//	jitField v path   the member at the dot-separated path, decoded as by
+//	                  AnyJitJSON.ToInterface; numeric segments index arrays, as in
+//	                  "items.0.sku", and a missing path gives nil
+//	jitPretty v       the encoding of v, indented
+//	jitJSON v         the encoding of v
+//
+// Member names are matched exactly. The results are plain values and strings, so
+// html/template escapes them for the context they appear in.
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"jitField":  templateField,
+		"jitPretty": templatePretty,
+		"jitJSON":   templateJSON,
+	}
+}
+
+// templateAny returns v, a *AnyJitJSON or other json.Marshaler, as an AnyJitJSON.
+func templateAny(v any) (*AnyJitJSON, error) {
+	switch v := v.(type) {
+	case *AnyJitJSON:
+		return v, nil
+	case json.Marshaler:
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			data = []byte("null")
+		}
+		a := &AnyJitJSON{}
+		return a, a.set(data)
+	}
+	return nil, fmt.Errorf("jitjson: %T is not a JitJSON or AnyJitJSON value", v)
+}
+
+func templateField(v any, path string) (any, error) {
+	a, err := templateAny(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok, err := a.lookup(path)
+	if err != nil {
+		return nil, fmt.Errorf("jitjson: %s: %w", path, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return a.ToInterface()
+}
+
+func templatePretty(v any) (string, error) {
+	a, err := templateAny(v)
+	if err != nil {
+		return "", err
+	}
+	data, err := a.encoded()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func templateJSON(v any) (string, error) {
+	a, err := templateAny(v)
+	if err != nil {
+		return "", err
+	}
+	data, err := a.encoded()
+	return string(data), err
+}
@@ -0,0 +1,58 @@
+package jitjson_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	order := jitjson.NewFromBytes[map[string]any]([]byte(
+		`{"id": 7, "customer": {"name": "<John>"}, "items": [{"sku": "A-1"}, {"sku": "B-2"}]}`))
+	doc, err := jitjson.NewAny([]byte(`{"status": "ok"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]any{"Order": order, "Doc": doc}
+
+	tmpl := template.Must(template.New("t").Funcs(jitjson.TemplateFuncs()).Parse(
+		`{{jitField .Order "customer.name"}} {{jitField .Order "items.1.sku"}} {{jitField .Order "missing.x"}} ` +
+			`{{jitField .Doc "status"}} {{jitJSON .Doc}}`))
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<John> B-2 <no value> ok {"status": "ok"}`; sb.String() != want {
+		t.Errorf("expected %q, got %q", want, sb.String())
+	}
+	if order.DebugState().HasValue {
+		t.Error("expected only the referenced members to be decoded")
+	}
+
+	pretty := template.Must(template.New("t").Funcs(jitjson.TemplateFuncs()).Parse(`{{jitPretty .Doc}}`))
+	sb.Reset()
+	if err := pretty.Execute(&sb, data); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"status\": \"ok\"\n}"; sb.String() != want {
+		t.Errorf("expected %q, got %q", want, sb.String())
+	}
+
+	html := htmltemplate.Must(htmltemplate.New("t").Funcs(jitjson.TemplateFuncs()).Parse(
+		`<p>{{jitField .Order "customer.name"}}</p>`))
+	sb.Reset()
+	if err := html.Execute(&sb, data); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<p>&lt;John&gt;</p>`; sb.String() != want {
+		t.Errorf("expected %q, got %q", want, sb.String())
+	}
+
+	bad := template.Must(template.New("t").Funcs(jitjson.TemplateFuncs()).Parse(`{{jitField . "x"}}`))
+	if err := bad.Execute(&sb, 42); err == nil {
+		t.Error("expected an error for a non-JitJSON value")
+	}
+}
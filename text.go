@@ -0,0 +1,76 @@
+package jitjson
+
+import (
+	"errors"
+	"flag"
+)
+
+// MarshalText implements encoding.TextMarshaler, returning the JSON encoding of the
+// value, so JitJSON[T] can be used wherever the text interfaces are expected.
+func (jit *JitJSON[T]) MarshalText() ([]byte, error) {
+	data, err := jit.Marshal()
+	if err != nil || data != nil {
+		return data, err
+	}
+	return []byte("null"), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The text must be valid JSON; it
+// is copied and stored without decoding, so config loaders that populate structs from
+// environment variables can defer the decode like any other source.
+func (jit *JitJSON[T]) UnmarshalText(text []byte) error {
+	if !ScanValid(text) {
+		return errors.New("invalid json")
+	}
+	return jit.UnmarshalJSON(append([]byte(nil), text...))
+}
+
+// Flag returns a flag.Value for JitJSON[T], so JSON values can be passed as command
+// line flags with flag.Var(jit.Flag(), "name", "usage"). The flag is stored without
+// being decoded.
+func (jit *JitJSON[T]) Flag() flag.Value {
+	return jitFlag[T]{jit}
+}
+
+// jitFlag adapts JitJSON[T] to flag.Value, whose Set method conflicts with JitJSON[T].Set.
+type jitFlag[T any] struct {
+	jit *JitJSON[T]
+}
+
+func (f jitFlag[T]) String() string {
+	if f.jit == nil {
+		return ""
+	}
+	data, err := f.jit.Marshal()
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (f jitFlag[T]) Set(s string) error {
+	return f.jit.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the JSON encoding.
+func (a *AnyJitJSON) MarshalText() ([]byte, error) {
+	data, err := a.encoded()
+	if data == nil && err == nil {
+		return []byte("null"), nil
+	}
+	return data, err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The text must be valid JSON and
+// is copied before being stored.
+func (a *AnyJitJSON) UnmarshalText(text []byte) error {
+	if !ScanValid(text) {
+		return errors.New("invalid json")
+	}
+	return a.set(append([]byte(nil), text...))
+}
+
+// Set implements flag.Value, so AnyJitJSON can be used with flag.Var.
+func (a *AnyJitJSON) Set(s string) error {
+	return a.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,78 @@
+package jitjson_test
+
+import (
+	"encoding"
+	"flag"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+var (
+	_ encoding.TextMarshaler   = (*jitjson.JitJSON[Person])(nil)
+	_ encoding.TextUnmarshaler = (*jitjson.JitJSON[Person])(nil)
+	_ encoding.TextMarshaler   = (*jitjson.AnyJitJSON)(nil)
+	_ encoding.TextUnmarshaler = (*jitjson.AnyJitJSON)(nil)
+	_ flag.Value               = (*jitjson.AnyJitJSON)(nil)
+)
+
+func TestJitJSON_Text(t *testing.T) {
+	var jit jitjson.JitJSON[Person]
+	text := []byte(`{"Name":"John","Age":30}`)
+	if err := jit.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	text[2] = 'X'
+
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "John" {
+		t.Error("expected text to be copied")
+	}
+
+	out, err := jit.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"Name":"John","Age":30}` {
+		t.Errorf("unexpected text %s", out)
+	}
+
+	if err := jit.UnmarshalText([]byte(`{"Name":`)); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
+
+func TestFlag(t *testing.T) {
+	var jit jitjson.JitJSON[Person]
+	var any jitjson.AnyJitJSON
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(jit.Flag(), "person", "person as json")
+	fs.Var(&any, "any", "any json")
+
+	err := fs.Parse([]string{"-person", `{"Name":"Jane"}`, "-any", `[1,2,3]`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Jane" {
+		t.Error("values do not match")
+	}
+	if arr, ok := any.AsArray(); !ok || len(arr) != 3 {
+		t.Error("expected array flag")
+	}
+	if s := fs.Lookup("person").Value.String(); s != `{"Name":"Jane"}` {
+		t.Errorf("unexpected flag string %s", s)
+	}
+
+	if err := fs.Parse([]string{"-any", `{`}); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
@@ -0,0 +1,309 @@
+package jitjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TimeFormat converts between a JSON encoding of timestamps and time.Time, for use with
+// WithTimeFormat. TimeLayout, UnixSeconds and UnixMillis provide the common encodings.
+type TimeFormat interface {
+	// ParseTime parses the JSON value raw, such as a quoted string or a number literal.
+	ParseTime(raw []byte) (time.Time, error)
+	// AppendTime appends the JSON encoding of t to dst.
+	AppendTime(dst []byte, t time.Time) []byte
+}
+
+// WithTimeFormat makes JitJSON[T] decode and encode the time.Time values within T, in
+// struct fields, slices, arrays, maps and through pointers, with f rather than as
+// RFC 3339 strings, so feeds of Unix timestamps or other layouts can be decoded lazily
+// without custom UnmarshalJSON wrappers:
+//
+//	jit := jitjson.NewFromBytes[Event](data, jitjson.WithTimeFormat(jitjson.UnixMillis()))
+//
+// The members holding times are converted in the data before and after the configured
+// parser runs, which is unaffected otherwise. Types implementing json.Unmarshaler or
+// json.Marshaler, other than time.Time, are left to their own methods. It applies to
+// JSON parsers only.
+func WithTimeFormat(f TimeFormat) Option {
+	return func(o *options) {
+		o.timeFormat = f
+	}
+}
+
+// TimeLayout returns a TimeFormat encoding times as strings in layout, as accepted by
+// time.Parse and time.Time.Format.
+func TimeLayout(layout string) TimeFormat {
+	return layoutFormat(layout)
+}
+
+// UnixSeconds returns a TimeFormat encoding times as numbers of seconds since the Unix
+// epoch. Fractional seconds are accepted when parsing; decoded times are in UTC.
+func UnixSeconds() TimeFormat {
+	return unixFormat(time.Second)
+}
+
+// UnixMillis returns a TimeFormat encoding times as numbers of milliseconds since the
+// Unix epoch. Fractional milliseconds are accepted when parsing; decoded times are in UTC.
+func UnixMillis() TimeFormat {
+	return unixFormat(time.Millisecond)
+}
+
+type layoutFormat string
+
+func (f layoutFormat) ParseTime(raw []byte) (time.Time, error) {
+	if len(raw) == 0 || raw[0] != '"' {
+		return time.Time{}, fmt.Errorf("expected a string, got %s", raw)
+	}
+	s, err := unquote(raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(string(f), s)
+}
+
+func (f layoutFormat) AppendTime(dst []byte, t time.Time) []byte {
+	s, _ := json.Marshal(t.Format(string(f)))
+	return append(dst, s...)
+}
+
+type unixFormat time.Duration
+
+func (f unixFormat) ParseTime(raw []byte) (time.Time, error) {
+	if n, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+		switch time.Duration(f) {
+		case time.Second:
+			return time.Unix(n, 0).UTC(), nil
+		case time.Millisecond:
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(0, n*int64(f)).UTC(), nil
+	}
+	x, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil || len(raw) == 0 || raw[0] == '"' {
+		return time.Time{}, fmt.Errorf("expected a number, got %s", raw)
+	}
+	return time.Unix(0, int64(x*float64(f))).UTC(), nil
+}
+
+func (f unixFormat) AppendTime(dst []byte, t time.Time) []byte {
+	switch time.Duration(f) {
+	case time.Second:
+		return strconv.AppendInt(dst, t.Unix(), 10)
+	case time.Millisecond:
+		return strconv.AppendInt(dst, t.UnixMilli(), 10)
+	}
+	return strconv.AppendInt(dst, t.UnixNano()/int64(f), 10)
+}
+
+// parseTimes returns data with the times within it, as decoded into the type of v,
+// converted from f to RFC 3339 strings, and the spans of the converted times.
+func parseTimes(f TimeFormat, data []byte, v any) ([]byte, []timeSpan, error) {
+	return convertTimes(data, reflect.TypeOf(v), func(dst, raw []byte) ([]byte, error) {
+		t, err := f.ParseTime(raw)
+		if err != nil {
+			return nil, &TypeError{Err: fmt.Errorf("jitjson: parsing time %s: %w", raw, err)}
+		}
+		dst = append(dst, '"')
+		dst = t.AppendFormat(dst, time.RFC3339Nano)
+		return append(dst, '"'), nil
+	})
+}
+
+// formatTimes returns data, the encoding of v, with the RFC 3339 strings of the times
+// within it converted to f.
+func formatTimes(f TimeFormat, data []byte, v any) ([]byte, error) {
+	out, _, err := convertTimes(data, reflect.TypeOf(v), func(dst, raw []byte) ([]byte, error) {
+		if raw[0] != '"' {
+			return append(dst, raw...), nil
+		}
+		s, err := unquote(raw)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("jitjson: formatting time %s: %w", raw, err)
+		}
+		return f.AppendTime(dst, t), nil
+	})
+	return out, err
+}
+
+// errMalformed stops findTimes at data that is not well-formed, which is left for the
+// parser to report.
+var errMalformed = errors.New("jitjson: malformed json")
+
+// timeSpan locates a converted time by its offsets in the data and in the output of
+// convertTimes.
+type timeSpan struct {
+	start, end       int
+	outStart, outEnd int
+}
+
+// convertTimes returns data with each member holding a time.Time of the type t replaced
+// by convert, and the spans of the converted times. The rest of data is copied as is, so
+// offsets outside the times can be mapped back with sourceOffset. Data that is not
+// well-formed, or a type without times, is returned as is.
+func convertTimes(data []byte, t reflect.Type, convert func(dst, raw []byte) ([]byte, error)) ([]byte, []timeSpan, error) {
+	if t == nil || !hasTime(t) {
+		return data, nil, nil
+	}
+	var spans []timeSpan
+	if err := findTimes(data, bytes.TrimSpace(data), t, &spans); err != nil || len(spans) == 0 {
+		return data, nil, nil
+	}
+	out := make([]byte, 0, len(data))
+	prev := 0
+	for i, s := range spans {
+		out = append(out, data[prev:s.start]...)
+		spans[i].outStart = len(out)
+		var err error
+		if out, err = convert(out, data[s.start:s.end]); err != nil {
+			return nil, nil, err
+		}
+		spans[i].outEnd = len(out)
+		prev = s.end
+	}
+	return append(out, data[prev:]...), spans, nil
+}
+
+// sourceOffset returns the offset in the data given to convertTimes corresponding to off
+// in its output, given the spans of the converted times. Offsets within a converted time
+// map to its start.
+func sourceOffset(spans []timeSpan, off int64) int64 {
+	for i := len(spans) - 1; i >= 0; i-- {
+		s := spans[i]
+		switch {
+		case off >= int64(s.outEnd):
+			return off - int64(s.outEnd) + int64(s.end)
+		case off >= int64(s.outStart):
+			return int64(s.start)
+		}
+	}
+	return off
+}
+
+// findTimes appends the spans of the members of data, a part of root, holding a
+// time.Time of the type t, in order.
+func findTimes(root, data []byte, t reflect.Type, spans *[]timeSpan) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case len(data) == 0:
+		return errMalformed
+	case scanLiteral(data, 0, "null") == len(data):
+		return nil
+	case t == timeType:
+		start := offset(root, data)
+		*spans = append(*spans, timeSpan{start: start, end: start + len(data)})
+		return nil
+	case !hasTime(t):
+		return nil
+	}
+
+	var err error
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		if !isContainer(data, '{', '}') {
+			return nil
+		}
+		ok := splitObject(data, func(key, val []byte) bool {
+			elem := t
+			if t.Kind() == reflect.Map {
+				elem = t.Elem()
+			} else {
+				name, uerr := unquote(key)
+				if uerr != nil {
+					err = errMalformed
+					return false
+				}
+				index, found := fieldIndex(t, name)
+				if !found {
+					return true
+				}
+				elem = t.FieldByIndex(index).Type
+			}
+			err = findTimes(root, val, elem, spans)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errMalformed
+		}
+	case reflect.Slice, reflect.Array:
+		if !isContainer(data, '[', ']') {
+			return nil
+		}
+		elems, ok := splitArray(data)
+		if !ok {
+			return errMalformed
+		}
+		for _, elem := range elems {
+			if err := findTimes(root, elem, t.Elem(), spans); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	marshalerType   = reflect.TypeFor[json.Marshaler]()
+	textType        = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	// timeTypes caches hasTime by reflect.Type.
+	timeTypes sync.Map
+)
+
+// hasTime reports whether values of the type t can hold a time.Time that encoding/json
+// decodes as part of them, rather than through a method of another type.
+func hasTime(t reflect.Type) bool {
+	if has, ok := timeTypes.Load(t); ok {
+		return has.(bool)
+	}
+	has := containsTime(t, map[reflect.Type]bool{})
+	timeTypes.Store(t, has)
+	return has
+}
+
+func containsTime(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	p := reflect.PointerTo(t)
+	if p.Implements(unmarshalerType) || p.Implements(marshalerType) || p.Implements(textType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if (f.IsExported() || f.Anonymous) && f.Tag.Get("json") != "-" && containsTime(f.Type, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return containsTime(t.Elem(), seen)
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type Event struct {
+	Name     string               `json:"name"`
+	At       time.Time            `json:"at"`
+	Ended    *time.Time           `json:"ended"`
+	History  []time.Time          `json:"history"`
+	Marks    map[string]time.Time `json:"marks"`
+	Duration time.Duration        `json:"duration"`
+}
+
+func TestWithTimeFormatUnixMillis(t *testing.T) {
+	data := []byte(`{"name": "deploy", "at": 1700000000123, "ended": null, "history": [0, 1000], "marks": {"a": 2000}, "duration": 5}`)
+	jit := jitjson.NewFromBytes[Event](data, jitjson.WithTimeFormat(jitjson.UnixMillis()))
+
+	ev, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.UnixMilli(1700000000123).UTC(); !ev.At.Equal(want) {
+		t.Errorf("expected %v, got %v", want, ev.At)
+	}
+	if ev.Ended != nil || len(ev.History) != 2 || !ev.History[1].Equal(time.Unix(1, 0)) {
+		t.Errorf("unexpected event %+v", ev)
+	}
+	if !ev.Marks["a"].Equal(time.Unix(2, 0)) || ev.Duration != 5 {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	ev.Name = "rollback"
+	jit.Set(ev)
+	out, err := jit.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"rollback","at":1700000000123,"ended":null,"history":[0,1000],"marks":{"a":2000},"duration":5}`
+	if string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+}
+
+func TestWithTimeFormatEmbedded(t *testing.T) {
+	type Stamp struct {
+		At time.Time
+	}
+	type Row struct {
+		Stamp
+		At int64
+	}
+	jit := jitjson.NewFromBytes[Row]([]byte(`{"At": 5}`), jitjson.WithTimeFormat(jitjson.UnixMillis()))
+	row, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.At != 5 || !row.Stamp.At.IsZero() {
+		t.Errorf("expected the shallower field to be decoded, got %+v", row)
+	}
+}
+
+func TestWithTimeFormatLayout(t *testing.T) {
+	type Row struct {
+		Day time.Time `json:"day"`
+	}
+	jit := jitjson.NewFromBytes[[]Row]([]byte(`[{"day": "2024-03-01"}, {"day": "2024-03-02"}]`),
+		jitjson.WithTimeFormat(jitjson.TimeLayout(time.DateOnly)))
+	rows, err := jit.Unmarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1].Day != time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected rows %v", rows)
+	}
+
+	out, err := jitjson.New(Row{Day: rows[0].Day}, jitjson.WithTimeFormat(jitjson.TimeLayout(time.DateOnly))).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"day":"2024-03-01"}` {
+		t.Errorf("unexpected encoding %s", out)
+	}
+}
+
+func TestWithTimeFormatErrors(t *testing.T) {
+	jit := jitjson.NewFromBytes[Event]([]byte(`{"at": "yesterday"}`), jitjson.WithTimeFormat(jitjson.UnixSeconds()))
+	if _, err := jit.Unmarshal(); !jitjson.IsTypeMismatch(err) {
+		t.Errorf("expected a type mismatch, got %v", err)
+	}
+
+	jit = jitjson.NewFromBytes[Event]([]byte(`{"at": 1,`), jitjson.WithTimeFormat(jitjson.UnixSeconds()))
+	if _, err := jit.Unmarshal(); !jitjson.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+
+	jit = jitjson.NewFromBytes[Event]([]byte(`{"at": 1.5}`), jitjson.WithTimeFormat(jitjson.UnixSeconds()))
+	ev, err := jit.Unmarshal()
+	if err != nil || !ev.At.Equal(time.Unix(1, 5e8)) {
+		t.Errorf("expected fractional seconds, got %v, %v", ev.At, err)
+	}
+}
+
+func TestWithTimeFormatParseError(t *testing.T) {
+	data := []byte("{\n  \"at\": 1700000000123,\n  \"history\": [0],\n  \"name\": 5\n}")
+	jit := jitjson.NewFromBytes[Event](data, jitjson.WithTimeFormat(jitjson.UnixMillis()))
+	_, err := jit.Unmarshal()
+	var pe *jitjson.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+
+	// The same document without times fails at the same position.
+	_, want := jitjson.NewFromBytes[struct {
+		At      int64   `json:"at"`
+		History []int64 `json:"history"`
+		Name    string  `json:"name"`
+	}](data).Unmarshal()
+	var wantPE *jitjson.ParseError
+	if !errors.As(want, &wantPE) {
+		t.Fatalf("expected a parse error, got %v", want)
+	}
+	if pe.Offset != wantPE.Offset || pe.Line != 4 || pe.Path != "$.name" {
+		t.Errorf("expected offset %d on line 4 at $.name, got %d on line %d at %s", wantPE.Offset, pe.Offset, pe.Line, pe.Path)
+	}
+}
+
+func TestUnmarshalPartialTimeFormat(t *testing.T) {
+	type Reading struct {
+		At    time.Time   `json:"at"`
+		Seen  []time.Time `json:"seen"`
+		Count int8        `json:"count"`
+	}
+	jit := jitjson.NewFromBytes[Reading]([]byte(`{"at": 1000, "seen": [2000, "soon"], "count": 300}`),
+		jitjson.WithTimeFormat(jitjson.UnixMillis()))
+	r, errs := jit.UnmarshalPartial()
+	if !r.At.Equal(time.Unix(1, 0)) {
+		t.Errorf("expected the time to be converted, got %v", r.At)
+	}
+	if len(errs) != 2 || errs[0].Path != "$.seen" || errs[1].Path != "$.count" {
+		t.Errorf("expected errors for seen and count, got %v", errs)
+	}
+}
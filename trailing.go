@@ -0,0 +1,40 @@
+package jitjson
+
+import "errors"
+
+// ErrTrailingData is returned when data stored with WithRejectTrailingData holds
+// content after its first JSON value.
+var ErrTrailingData = errors.New("jitjson: trailing data after JSON value")
+
+// WithRejectTrailingData makes SetBytes, UnmarshalJSON and NewFromBytesChecked reject
+// data with non-whitespace content after the first JSON value, such as {"a":1}garbage,
+// which would otherwise only be detected by the deferred decode.
+func WithRejectTrailingData() Option {
+	return func(o *options) {
+		o.rejectTrailing = true
+	}
+}
+
+// checkTrailing returns ErrTrailingData if WithRejectTrailingData is set and data holds
+// content after its first value. Scalars are delimited but not validated.
+func (o *options) checkTrailing(data []byte) error {
+	if o == nil || !o.rejectTrailing {
+		return nil
+	}
+	end := valueEnd(data, skipSpace(data, 0))
+	if end >= 0 && skipSpace(data, end) != len(data) {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+// NewFromBytesChecked is like NewFromBytes, but checks data against the requirements
+// set by the options, as SetBytes does, so malformed payloads are rejected on arrival
+// rather than when they are decoded.
+func NewFromBytesChecked[T any](data []byte, opts ...Option) (*JitJSON[T], error) {
+	jit := &JitJSON[T]{opts: newOptions(opts)}
+	if err := jit.SetBytes(data); err != nil {
+		return nil, err
+	}
+	return jit, nil
+}
@@ -0,0 +1,36 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWithRejectTrailingData(t *testing.T) {
+	tests := []struct {
+		data string
+		err  error
+	}{
+		{`{"Name":"John"}`, nil},
+		{" {\"Name\":\"John\"} \n", nil},
+		{`{"Name":"John"}garbage`, jitjson.ErrTrailingData},
+		{`{"Name":"John"} {"Name":"Jane"}`, jitjson.ErrTrailingData},
+		{`"text" 1`, jitjson.ErrTrailingData},
+		{`12`, nil},
+	}
+	for _, tc := range tests {
+		_, err := jitjson.NewFromBytesChecked[Person]([]byte(tc.data), jitjson.WithRejectTrailingData())
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%q: expected %v, got %v", tc.data, tc.err, err)
+		}
+	}
+
+	jit, err := jitjson.NewFromBytesChecked[Person]([]byte(`{"Name":"John"}x`))
+	if err != nil {
+		t.Fatalf("expected trailing data to be accepted without the option, got %v", err)
+	}
+	if _, err := jit.Unmarshal(); err == nil {
+		t.Error("expected the deferred decode to fail")
+	}
+}
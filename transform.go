@@ -0,0 +1,54 @@
+package jitjson
+
+// BytesTransform transforms the encoding of a JitJSON[T] while it is stored, such as to
+// encrypt or compress it, so sensitive payloads can sit encrypted in memory and are only
+// decrypted at the moment of deferred decoding. Implementations must be safe for
+// concurrent use when shared between values.
+type BytesTransform interface {
+	// Store is called with the JSON encoding when it is stored, and returns the bytes
+	// to hold instead.
+	Store(data []byte) ([]byte, error)
+	// Load is called with the stored bytes when the JSON encoding is needed by Marshal
+	// or Unmarshal, and returns the encoding passed to Store.
+	Load(stored []byte) ([]byte, error)
+}
+
+// WithBytesTransform makes JitJSON[T] hold its encoding transformed by t. The stored
+// bytes are passed to Load on every Marshal and decoding Unmarshal, as the encoding is
+// never kept untransformed. Methods exposing the stored bytes directly, such as
+// SwapBytes, return them transformed.
+func WithBytesTransform(t BytesTransform) Option {
+	return func(o *options) {
+		o.transform = t
+	}
+}
+
+// store returns data as held by JitJSON[T], transformed by any BytesTransform.
+func (o *options) store(data []byte) ([]byte, error) {
+	if o == nil || o.transform == nil || data == nil {
+		return data, nil
+	}
+	return o.transform.Store(data)
+}
+
+// load returns the encoding held as data by JitJSON[T], reverting any BytesTransform.
+func (o *options) load(data []byte) ([]byte, error) {
+	if o == nil || o.transform == nil || data == nil {
+		return data, nil
+	}
+	return o.transform.Load(data)
+}
+
+// setData holds data as the encoding of JitJSON[T], transformed by any BytesTransform.
+// If the transform fails, nothing is held and the error is reported by Unmarshal.
+func (jit *JitJSON[T]) setData(data []byte) error {
+	stored, err := jit.opts.store(data)
+	if err != nil {
+		jit.data = nil
+		jit.val = new(T)
+		jit.verr = err
+		return err
+	}
+	jit.data = stored
+	return nil
+}
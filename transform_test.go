@@ -0,0 +1,90 @@
+package jitjson_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// base64Transform holds encodings base64 encoded and counts the calls made to it.
+type base64Transform struct {
+	stores, loads int
+}
+
+func (b *base64Transform) Store(data []byte) ([]byte, error) {
+	b.stores++
+	return []byte(base64.StdEncoding.EncodeToString(data)), nil
+}
+
+func (b *base64Transform) Load(stored []byte) ([]byte, error) {
+	b.loads++
+	return base64.StdEncoding.DecodeString(string(stored))
+}
+
+func TestWithBytesTransform(t *testing.T) {
+	jsonData := []byte(`{"Name":"John","Age":30,"City":"New York"}`)
+	transform := &base64Transform{}
+
+	jit := jitjson.NewFromBytes[Person](jsonData, jitjson.WithBytesTransform(transform))
+	if transform.stores != 1 || transform.loads != 0 {
+		t.Fatalf("expected bytes to be transformed when stored, got %+v", transform)
+	}
+	if old, _ := jit.SwapBytes(jsonData); bytes.Contains(old, []byte("John")) {
+		t.Errorf("expected stored bytes to be transformed, got %s", old)
+	}
+
+	p, err := jit.Unmarshal()
+	if err != nil || p.Name != "John" {
+		t.Fatalf("expected John, got %+v, %v", p, err)
+	}
+	data, err := jit.Marshal()
+	if err != nil || !bytes.Equal(data, jsonData) {
+		t.Errorf("expected original encoding, got %s, %v", data, err)
+	}
+
+	p.Name = "Jane"
+	jit.Set(p)
+	data, err = jit.Marshal()
+	if err != nil || !bytes.Contains(data, []byte("Jane")) {
+		t.Errorf("expected new encoding, got %s, %v", data, err)
+	}
+	if err := jit.UnmarshalJSON(jsonData); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := jit.Unmarshal(); p.Name != "John" {
+		t.Errorf("expected John, got %s", p.Name)
+	}
+
+	canonical := jitjson.NewFromBytes[Person]([]byte(`{"Name": "John"}`),
+		jitjson.WithBytesTransform(transform), jitjson.WithCanonical())
+	for i := 0; i < 2; i++ {
+		if data, err := canonical.Marshal(); err != nil || string(data) != `{"Name":"John"}` {
+			t.Errorf("expected canonical encoding, got %s, %v", data, err)
+		}
+	}
+}
+
+var errTransform = errors.New("transform failed")
+
+type failingTransform struct{}
+
+func (failingTransform) Store([]byte) ([]byte, error) { return nil, errTransform }
+func (failingTransform) Load([]byte) ([]byte, error)  { return nil, errTransform }
+
+func TestWithBytesTransformErrors(t *testing.T) {
+	jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John"}`), jitjson.WithBytesTransform(failingTransform{}))
+	if _, err := jit.Unmarshal(); !errors.Is(err, errTransform) {
+		t.Errorf("expected transform error from Unmarshal, got %v", err)
+	}
+	if err := jit.SetBytes([]byte(`{}`)); !errors.Is(err, errTransform) {
+		t.Errorf("expected transform error from SetBytes, got %v", err)
+	}
+
+	set := jitjson.New(Person{Name: "Jane"}, jitjson.WithBytesTransform(failingTransform{}))
+	if _, err := set.Marshal(); !errors.Is(err, errTransform) {
+		t.Errorf("expected transform error from Marshal, got %v", err)
+	}
+}
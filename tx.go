@@ -0,0 +1,212 @@
+package jitjson
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Tx stages the changes made to an AnyJitJSON within Update. Paths are dot-separated
+// member names, with numeric segments indexing arrays, such as "items.0.qty"; the empty
+// path is the whole document.
+type Tx struct {
+	root *AnyJitJSON
+	// orig maps the containers copied by the Tx to the encoding they were copied from.
+	orig map[*AnyJitJSON][]byte
+}
+
+// Update applies the changes made by fn to AnyJitJSON atomically: if fn returns nil, the
+// tree and its encoding are both replaced by the staged document, and if fn or encoding
+// the staged document fails, AnyJitJSON is left unchanged:
+//
+//	err := doc.Update(func(tx *jitjson.Tx) error {
+//		if err := tx.Set("status", "shipped"); err != nil {
+//			return err
+//		}
+//		return tx.Delete("draft")
+//	})
+//
+// Only the containers along the changed paths are copied; the rest of the document is
+// shared with the original. The new encoding is spliced from the original one, with
+// only the changed members and elements encoded again, so untouched parts of the
+// document keep their formatting and member order. The Tx must not be used after fn
+// returns.
+func (a *AnyJitJSON) Update(fn func(tx *Tx) error) error {
+	tx := &Tx{root: &AnyJitJSON{val: a.val, data: a.data}, orig: map[*AnyJitJSON][]byte{}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	data, err := tx.encode(tx.root)
+	if err != nil {
+		return err
+	}
+	a.val, a.data = tx.root.val, data
+	a.storePaths(nil)
+	return nil
+}
+
+// Fork returns a document sharing the encoding, the parsed members and elements, and the
+// paths resolved by Get of AnyJitJSON, for evaluating changes against a large base
+// document without copying it:
+//
+//	draft := base.Fork()
+//	err := draft.Update(func(tx *jitjson.Tx) error {
+//		return tx.Set("limits.daily", 500)
+//	})
+//
+// A document built by NewAnyFromValue is encoded first. Since Update copies the
+// containers it changes, changes to either document through Update or UnmarshalJSON are
+// not seen by the other. The As methods parse the shared
+// containers in place, so forks read from different goroutines should use Get.
+func (a *AnyJitJSON) Fork() *AnyJitJSON {
+	a.encoded()
+	f := &AnyJitJSON{val: a.val, data: a.data}
+	f.storePaths(a.loadPaths())
+	return f
+}
+
+// Get returns the staged value at path, reporting false if it does not exist.
+func (tx *Tx) Get(path string) (*AnyJitJSON, bool) {
+	node, ok, err := tx.root.lookup(path)
+	return node, ok && err == nil
+}
+
+// Set sets the value at path to v, converted as by NewAnyFromValue. The parent of path
+// must exist: a missing object member is added, and an array index equal to the length
+// of the array appends to it.
+func (tx *Tx) Set(path string, v any) error {
+	val, err := NewAnyFromValue(v)
+	if err != nil {
+		return err
+	}
+	segs := splitPath(path)
+	if len(segs) == 0 {
+		tx.root = val
+		return nil
+	}
+	return tx.edit(path, segs, func(c *AnyJitJSON, key string) error {
+		switch container := c.val.(type) {
+		case map[string]*AnyJitJSON:
+			container[key] = val
+		case []*AnyJitJSON:
+			i, err := arrayIndex(key, len(container)+1)
+			if err != nil {
+				return err
+			}
+			if i == len(container) {
+				c.val = append(container, val)
+			} else {
+				container[i] = val
+			}
+		}
+		return nil
+	})
+}
+
+// Delete removes the object member or array element at path, shifting later elements
+// down. Deleting a missing object member does nothing; the parent must exist.
+func (tx *Tx) Delete(path string) error {
+	segs := splitPath(path)
+	if len(segs) == 0 {
+		return fmt.Errorf("jitjson: cannot delete the document")
+	}
+	return tx.edit(path, segs, func(c *AnyJitJSON, key string) error {
+		switch container := c.val.(type) {
+		case map[string]*AnyJitJSON:
+			delete(container, key)
+		case []*AnyJitJSON:
+			i, err := arrayIndex(key, len(container))
+			if err != nil {
+				return err
+			}
+			c.val = slices.Delete(container, i, i+1)
+		}
+		return nil
+	})
+}
+
+// edit applies op to a copy of the container holding the last segment of segs, copying
+// the containers above it and replacing them in the staged document.
+func (tx *Tx) edit(path string, segs []string, op func(c *AnyJitJSON, key string) error) error {
+	root, err := tx.editIn(tx.root, segs, op)
+	if err != nil {
+		return fmt.Errorf("jitjson: %s: %w", path, err)
+	}
+	tx.root = root
+	return nil
+}
+
+// editIn returns a copy of the container node with op applied below it at segs.
+func (tx *Tx) editIn(node *AnyJitJSON, segs []string, op func(c *AnyJitJSON, key string) error) (*AnyJitJSON, error) {
+	c, err := tx.cloneContainer(node)
+	if err != nil {
+		return nil, err
+	}
+	if len(segs) == 1 {
+		return c, op(c, segs[0])
+	}
+	child, err := childOf(c, segs[0])
+	if err != nil {
+		return nil, err
+	}
+	if child == nil {
+		return nil, fmt.Errorf("missing member %q", segs[0])
+	}
+	child, err = tx.editIn(child, segs[1:], op)
+	if err != nil {
+		return nil, err
+	}
+	switch container := c.val.(type) {
+	case map[string]*AnyJitJSON:
+		container[segs[0]] = child
+	case []*AnyJitJSON:
+		i, _ := strconv.Atoi(segs[0])
+		container[i] = child
+	}
+	return c, nil
+}
+
+// cloneContainer returns an unencoded copy of the object or array node, sharing its
+// members or elements, and records the encoding it was copied from.
+func (tx *Tx) cloneContainer(node *AnyJitJSON) (*AnyJitJSON, error) {
+	var c *AnyJitJSON
+	if obj, ok := node.AsObject(); ok {
+		c = &AnyJitJSON{val: maps.Clone(obj)}
+	} else if arr, ok := node.AsArray(); ok {
+		c = &AnyJitJSON{val: slices.Clone(arr)}
+	} else {
+		return nil, fmt.Errorf("%v is not an object or array", node.Type())
+	}
+	if node.data != nil {
+		tx.orig[c] = node.data
+	} else if orig, ok := tx.orig[node]; ok {
+		tx.orig[c] = orig
+	}
+	return c, nil
+}
+
+// childOf returns the member or element of node at seg, or nil if an object has no
+// such member.
+func childOf(node *AnyJitJSON, seg string) (*AnyJitJSON, error) {
+	if obj, ok := node.AsObject(); ok {
+		return obj[seg], nil
+	}
+	if arr, ok := node.AsArray(); ok {
+		i, err := arrayIndex(seg, len(arr))
+		if err != nil {
+			return nil, err
+		}
+		return arr[i], nil
+	}
+	return nil, fmt.Errorf("%v is not an object or array", node.Type())
+}
+
+// arrayIndex parses seg as an index below n.
+func arrayIndex(seg string, n int) (int, error) {
+	i, err := strconv.Atoi(seg)
+	if err != nil || i < 0 || i >= n {
+		return 0, fmt.Errorf("invalid array index %q", seg)
+	}
+	return i, nil
+}
@@ -0,0 +1,169 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestUpdate(t *testing.T) {
+	doc, err := jitjson.NewAny([]byte(`{"status": "new", "draft": true, "items": [{"sku": "A", "qty": 1}, {"sku": "B", "qty": 2}], "meta": {"v": 1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := doc.AsObject()
+
+	err = doc.Update(func(tx *jitjson.Tx) error {
+		if err := tx.Set("status", "shipped"); err != nil {
+			return err
+		}
+		if err := tx.Set("items.1.qty", 5); err != nil {
+			return err
+		}
+		if err := tx.Set("items.2", map[string]any{"sku": "C"}); err != nil {
+			return err
+		}
+		if err := tx.Delete("items.0"); err != nil {
+			return err
+		}
+		if v, ok := tx.Get("items.0.qty"); !ok || v.String() != "5" {
+			t.Errorf("expected the staged value, got %v", v)
+		}
+		return tx.Delete("draft")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := doc.MarshalJSON()
+	want := `{"status": "shipped", "items": [{"sku": "B", "qty": 5}, {"sku":"C"}], "meta": {"v": 1}}`
+	if string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+	obj, _ := doc.AsObject()
+	if obj["meta"] != meta["meta"] {
+		t.Error("expected unchanged members to be shared")
+	}
+}
+
+func TestUpdateSplice(t *testing.T) {
+	doc, err := jitjson.NewAny([]byte(`{
+  "id": 1,
+  "tags": [ ],
+  "dup": 1,
+  "dup": 2,
+  "nested": {"keep": [1,2,  3], "n": 1}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.Update(func(tx *jitjson.Tx) error {
+		for path, v := range map[string]any{"nested.n": 2, "tags.0": "x", "added": true, "dup": 3} {
+			if err := tx.Set(path, v); err != nil {
+				return err
+			}
+		}
+		return tx.Delete("id")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := doc.MarshalJSON()
+	want := `{
+  "tags": [ "x"],
+  "dup": 3,
+  "nested": {"keep": [1,2,  3], "n": 2},
+  "added": true
+}`
+	if string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+
+	err = doc.Update(func(tx *jitjson.Tx) error {
+		if err := tx.Set("nested.keep.3", 4); err != nil {
+			return err
+		}
+		return tx.Delete("nested.keep.0")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _ := doc.Get("nested.keep")
+	if out, _ := v.MarshalJSON(); string(out) != `[2,3,  4]` {
+		t.Errorf("expected the spliced array, got %s", out)
+	}
+}
+
+func TestUpdateRollback(t *testing.T) {
+	data := `{"a": {"b": [1, 2]}}`
+	doc, err := jitjson.NewAny([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errAbort := errors.New("abort")
+
+	for _, fn := range []func(tx *jitjson.Tx) error{
+		func(tx *jitjson.Tx) error {
+			if err := tx.Set("a.b.0", 10); err != nil {
+				return err
+			}
+			return errAbort
+		},
+		func(tx *jitjson.Tx) error {
+			tx.Set("a.c", true)
+			return tx.Set("a.b.5", 1)
+		},
+		func(tx *jitjson.Tx) error { return tx.Set("a.missing.x", 1) },
+		func(tx *jitjson.Tx) error { return tx.Delete("a.b.x") },
+		func(tx *jitjson.Tx) error { return tx.Set("a.b.0.x", 1) },
+	} {
+		if err := doc.Update(fn); err == nil {
+			t.Error("expected an error")
+		}
+		out, _ := doc.MarshalJSON()
+		if string(out) != data {
+			t.Errorf("expected the document to be unchanged, got %s", out)
+		}
+		a, _ := doc.AsObject()
+		b, _ := a["a"].AsObject()
+		if len(b) != 1 {
+			t.Errorf("expected the tree to be unchanged, got %v", b)
+		}
+	}
+}
+
+func TestFork(t *testing.T) {
+	data := `{"limits": {"daily": 100}, "rules": [1, 2, 3]}`
+	base, err := jitjson.NewAny([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, _ := base.Get("rules")
+
+	draft := base.Fork()
+	if v, _ := draft.Get("rules"); v != rules {
+		t.Error("expected the resolved paths to be shared")
+	}
+	err = draft.Update(func(tx *jitjson.Tx) error {
+		if err := tx.Set("limits.daily", 500); err != nil {
+			return err
+		}
+		return tx.Delete("rules.0")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out, _ := base.MarshalJSON(); string(out) != data {
+		t.Errorf("expected the base to be unchanged, got %s", out)
+	}
+	if v, _ := base.Get("limits.daily"); v.String() != "100" {
+		t.Errorf("expected 100, got %v", v)
+	}
+	if v, _ := draft.Get("limits.daily"); v.String() != "500" {
+		t.Errorf("expected 500, got %v", v)
+	}
+	if v, _ := draft.Get("rules"); v.String() == rules.String() {
+		t.Errorf("expected the changed rules, got %v", v)
+	}
+}
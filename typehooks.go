@@ -0,0 +1,119 @@
+package jitjson
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// typeHooks holds the hooks registered for a type T.
+type typeHooks[T any] struct {
+	mu    sync.RWMutex
+	hooks []func(*T) error
+}
+
+// decodeHooks and encodeHooks map types to their *typeHooks. hasDecodeHooks and
+// hasEncodeHooks record whether any are registered, so other types skip the lookup.
+var (
+	decodeHooks, encodeHooks       sync.Map
+	hasDecodeHooks, hasEncodeHooks atomic.Bool
+)
+
+// RegisterDecodeHook registers fn to be applied to every value of type T after it is
+// decoded successfully by Unmarshal, UnmarshalValue and the other decoding methods, for
+// normalization such as filling defaults or converting units that would otherwise be
+// repeated at every call site:
+//
+//	jitjson.RegisterDecodeHook(func(o *Order) error {
+//		o.Currency = strings.ToUpper(o.Currency)
+//		return nil
+//	})
+//
+// Hooks run in the order registered, before any validator set by WithValidator, and
+// not on values set with New or Set. An error from a hook is returned by Unmarshal
+// with the decoded value, like a validation error. It is typically called from init.
+func RegisterDecodeHook[T any](fn func(*T) error) {
+	registerHook(&decodeHooks, fn)
+	hasDecodeHooks.Store(true)
+}
+
+// RegisterEncodeHook registers fn to be applied to a copy of every value of type T just
+// before Marshal encodes it, so the emitted JSON can omit or redact internal fields
+// without separate DTO structs:
+//
+//	jitjson.RegisterEncodeHook(func(u *User) error {
+//		u.PasswordHash = ""
+//		u.Email = redact(u.Email)
+//		return nil
+//	})
+//
+// The copy is shallow, so hooks should replace rather than modify the slices, maps and
+// pointers of T. Hooks run in the order registered. Data stored as given, such as by
+// NewFromBytes or UnmarshalJSON, is marshaled as is. The encoding produced by the hooks
+// is not stored, so each Marshal runs them again and the value is kept whatever the
+// Retention. An error from a hook is returned by Marshal. It is typically called from
+// init.
+func RegisterEncodeHook[T any](fn func(*T) error) {
+	registerHook(&encodeHooks, fn)
+	hasEncodeHooks.Store(true)
+}
+
+// registerHook appends fn to the hooks of T in registry.
+func registerHook[T any](registry *sync.Map, fn func(*T) error) {
+	r, _ := registry.LoadOrStore(reflect.TypeFor[T](), &typeHooks[T]{})
+	h := r.(*typeHooks[T])
+	h.mu.Lock()
+	h.hooks = append(h.hooks, fn)
+	h.mu.Unlock()
+}
+
+// hooksFor returns the hooks of T in registry.
+func hooksFor[T any](registry *sync.Map) []func(*T) error {
+	r, ok := registry.Load(reflect.TypeFor[T]())
+	if !ok {
+		return nil
+	}
+	h := r.(*typeHooks[T])
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.hooks
+}
+
+// runHooks applies hooks to val, naming the kind of hook in errors.
+func runHooks[T any](hooks []func(*T) error, kind string, val *T) error {
+	for _, fn := range hooks {
+		if err := fn(val); err != nil {
+			return fmt.Errorf("jitjson: %s hook for %v: %w", kind, reflect.TypeFor[T](), err)
+		}
+	}
+	return nil
+}
+
+// afterDecode applies the decode hooks registered for T and then the validator of o to
+// val, which was decoded successfully.
+func afterDecode[T any](o *options, val *T) error {
+	if hasDecodeHooks.Load() {
+		if err := runHooks(hooksFor[T](&decodeHooks), "decode", val); err != nil {
+			return err
+		}
+	}
+	return o.validate(val)
+}
+
+// encodeValue returns the value to encode for val: a copy modified by the encode hooks
+// registered for T, or val itself if there are none.
+func encodeValue[T any](val *T) (*T, error) {
+	if !hasEncodeHooks.Load() {
+		return val, nil
+	}
+	hooks := hooksFor[T](&encodeHooks)
+	if len(hooks) == 0 {
+		return val, nil
+	}
+	c := *val
+	if err := runHooks(hooks, "encode", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,114 @@
+package jitjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type price struct {
+	Currency string  `json:"currency"`
+	Cents    int     `json:"cents"`
+	Amount   float64 `json:"amount"`
+}
+
+var errNegative = errors.New("negative price")
+
+func init() {
+	jitjson.RegisterDecodeHook(func(p *price) error {
+		if p.Currency == "" {
+			p.Currency = "EUR"
+		}
+		p.Currency = strings.ToUpper(p.Currency)
+		return nil
+	})
+	jitjson.RegisterDecodeHook(func(p *price) error {
+		if p.Cents < 0 {
+			return errNegative
+		}
+		p.Amount = float64(p.Cents) / 100
+		return nil
+	})
+}
+
+func TestRegisterDecodeHook(t *testing.T) {
+	for _, opt := range [][]jitjson.Option{nil, {jitjson.WithEagerThreshold(1 << 10)}} {
+		jit := jitjson.NewFromBytes[price]([]byte(`{"currency": "usd", "cents": 250}`), opt...)
+		p, err := jit.Unmarshal()
+		if err != nil || p.Currency != "USD" || p.Amount != 2.5 {
+			t.Errorf("expected the hooks to apply, got %+v, %v", p, err)
+		}
+	}
+
+	jit := jitjson.NewFromBytes[price]([]byte(`{"cents": 100}`))
+	if p, err := jit.UnmarshalValue(); err != nil || p.Currency != "EUR" || p.Amount != 1 {
+		t.Errorf("expected the hooks to apply, got %+v, %v", p, err)
+	}
+
+	jit = jitjson.NewFromBytes[price]([]byte(`{"cents": -1}`))
+	p, err := jit.Unmarshal()
+	if !errors.Is(err, errNegative) || p.Currency != "EUR" {
+		t.Errorf("expected the hook error with the value, got %+v, %v", p, err)
+	}
+
+	jit = jitjson.New(price{Currency: "gbp"})
+	if p, err := jit.Unmarshal(); err != nil || p.Currency != "gbp" {
+		t.Errorf("expected set values to be left as is, got %+v, %v", p, err)
+	}
+}
+
+type credentials struct {
+	User     string   `json:"user"`
+	Password string   `json:"password,omitempty"`
+	Tokens   []string `json:"tokens,omitempty"`
+}
+
+var errLocked = errors.New("locked")
+
+func init() {
+	jitjson.RegisterEncodeHook(func(c *credentials) error {
+		if c.User == "locked" {
+			return errLocked
+		}
+		c.Password = ""
+		c.Tokens = nil
+		return nil
+	})
+}
+
+func TestRegisterEncodeHook(t *testing.T) {
+	creds := credentials{User: "john", Password: "secret", Tokens: []string{"t1"}}
+	jit := jitjson.New(creds)
+	out, err := jit.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"user":"john"}`; string(out) != want {
+		t.Errorf("expected %s, got %s", want, out)
+	}
+	if c, err := jit.Unmarshal(); err != nil || c.Password != "secret" || len(c.Tokens) != 1 {
+		t.Errorf("expected the value to be left as is, got %+v, %v", c, err)
+	}
+	jit.Release()
+	if c, err := jit.Unmarshal(); err != nil || c.Password != "secret" {
+		t.Errorf("expected the value to survive Release, got %+v, %v", c, err)
+	}
+	jit = jitjson.New(creds, jitjson.WithRetention(jitjson.KeepBytes))
+	if out, err := jit.Marshal(); err != nil || string(out) != `{"user":"john"}` {
+		t.Errorf("expected the hooked encoding, got %s, %v", out, err)
+	}
+	if c, err := jit.Unmarshal(); err != nil || c.Password != "secret" {
+		t.Errorf("expected the value to be kept with KeepBytes, got %+v, %v", c, err)
+	}
+
+	data := []byte(`{"user":"jane","password":"kept"}`)
+	if out, err := jitjson.NewFromBytes[credentials](data).Marshal(); err != nil || string(out) != string(data) {
+		t.Errorf("expected stored data as is, got %s, %v", out, err)
+	}
+
+	if _, err := jitjson.New(credentials{User: "locked"}).Marshal(); !errors.Is(err, errLocked) {
+		t.Errorf("expected the hook error, got %v", err)
+	}
+}
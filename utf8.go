@@ -0,0 +1,46 @@
+package jitjson
+
+import (
+	"bytes"
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned when data stored with WithValidUTF8 is not valid UTF-8.
+var ErrInvalidUTF8 = errors.New("jitjson: data is not valid UTF-8")
+
+// utf8BOM is the UTF-8 encoding of the byte order mark U+FEFF.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// WithStripBOM makes JitJSON[T] remove a leading UTF-8 byte order mark from the data
+// it stores, as written by some Windows tools, which would otherwise only fail the
+// deferred decode far from where the data was received.
+func WithStripBOM() Option {
+	return func(o *options) {
+		o.stripBOM = true
+	}
+}
+
+// WithValidUTF8 makes SetBytes and UnmarshalJSON reject data that is not valid UTF-8.
+// Without it, invalid bytes in strings are silently replaced with U+FFFD on decoding.
+func WithValidUTF8() Option {
+	return func(o *options) {
+		o.validUTF8 = true
+	}
+}
+
+// trimBOM removes a leading byte order mark from data if WithStripBOM is set.
+func (o *options) trimBOM(data []byte) []byte {
+	if o != nil && o.stripBOM {
+		return bytes.TrimPrefix(data, utf8BOM)
+	}
+	return data
+}
+
+// checkUTF8 returns ErrInvalidUTF8 if WithValidUTF8 is set and data is not valid UTF-8.
+func (o *options) checkUTF8(data []byte) error {
+	if o != nil && o.validUTF8 && !utf8.Valid(data) {
+		return ErrInvalidUTF8
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestWithStripBOM(t *testing.T) {
+	data := append([]byte{0xEF, 0xBB, 0xBF}, `{"Name":"John"}`...)
+
+	if _, err := jitjson.NewFromBytes[Person](data).Unmarshal(); err == nil {
+		t.Error("expected BOM to fail decoding without WithStripBOM")
+	}
+
+	jit := jitjson.NewFromBytes[Person](data, jitjson.WithStripBOM())
+	p, err := jit.Unmarshal()
+	if err != nil || p.Name != "John" {
+		t.Fatalf("expected John, got %+v %v", p, err)
+	}
+	out, _ := jitjson.NewFromBytes[Person](data, jitjson.WithStripBOM()).Marshal()
+	if string(out) != `{"Name":"John"}` {
+		t.Errorf("expected BOM to be stripped from stored bytes, got %q", out)
+	}
+
+	jit = jitjson.NewFromBytes[Person](nil, jitjson.WithStripBOM())
+	if err := jit.SetBytes(data); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := jit.Unmarshal(); p.Name != "John" {
+		t.Error("expected SetBytes to strip BOM")
+	}
+}
+
+func TestWithValidUTF8(t *testing.T) {
+	invalid := []byte("{\"Name\":\"Jo\xffhn\"}")
+
+	jit := jitjson.NewFromBytes[Person](nil, jitjson.WithValidUTF8())
+	if err := jit.SetBytes(invalid); !errors.Is(err, jitjson.ErrInvalidUTF8) {
+		t.Errorf("expected ErrInvalidUTF8, got %v", err)
+	}
+	if err := jit.SetBytes([]byte(`{"Name":"Jöhn"}`)); err != nil {
+		t.Errorf("expected valid UTF-8 to be accepted, got %v", err)
+	}
+
+	lenient := jitjson.NewFromBytes[Person](nil)
+	if err := lenient.SetBytes(invalid); err != nil {
+		t.Errorf("expected invalid UTF-8 to be accepted by default, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package jitjson
+
+// StructValidator validates decoded values. It is satisfied by *validator.Validate from
+// github.com/go-playground/validator/v10, which checks `validate` struct tags.
+type StructValidator interface {
+	Struct(s any) error
+}
+
+// WithValidator makes Unmarshal validate each value it decodes with v. The result is
+// cached with the value, so every later Unmarshal of the same value returns the
+// validation error too, and callers cannot consume a decoded value unvalidated.
+// Values stored by New or Set are not validated. T should be a struct type:
+//
+//	validate := validator.New(validator.WithRequiredStructEnabled())
+//	jit := jitjson.NewFromBytes[Order](data, jitjson.WithValidator(validate))
+//	order, err := jit.Unmarshal() // err is a validator.ValidationErrors on failure
+func WithValidator(v StructValidator) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// validate checks val with the configured StructValidator, if any.
+func (o *options) validate(val any) error {
+	if o == nil || o.validator == nil {
+		return nil
+	}
+	return o.validator.Struct(val)
+}
@@ -0,0 +1,56 @@
+package jitjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+// ageValidator requires Person.Age to be positive, like a `validate:"gt=0"` tag.
+type ageValidator struct {
+	calls int
+}
+
+func (v *ageValidator) Struct(s any) error {
+	v.calls++
+	if p, ok := s.(*Person); ok && p.Age <= 0 {
+		return errors.New("Age must be greater than 0")
+	}
+	return nil
+}
+
+func TestWithValidator(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		v := &ageValidator{}
+		jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":30}`), jitjson.WithValidator(v))
+		if _, err := jit.Unmarshal(); err != nil {
+			t.Fatal(err)
+		}
+		if v.calls != 1 {
+			t.Errorf("expected 1 validation, got %d", v.calls)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		v := &ageValidator{}
+		jit := jitjson.NewFromBytes[Person]([]byte(`{"Name":"John","Age":0}`), jitjson.WithValidator(v))
+		for i := 0; i < 2; i++ {
+			p, err := jit.Unmarshal()
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if p.Name != "John" {
+				t.Error("expected decoded value to be returned with the error")
+			}
+		}
+		if v.calls != 1 {
+			t.Errorf("expected validation result to be cached, got %d calls", v.calls)
+		}
+
+		jit.Set(Person{Name: "Jane"})
+		if _, err := jit.Unmarshal(); err != nil {
+			t.Errorf("expected Set to clear the validation error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package jitjson
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DefaultVersionPath is the member read for the schema version of a VersionedJitJSON[T],
+// unless changed with SetVersionPath.
+const DefaultVersionPath = "version"
+
+// versionDecoders holds the decode functions registered for the versions of T.
+type versionDecoders[T any] struct {
+	mu       sync.RWMutex
+	path     string
+	decoders map[string]func(data []byte) (T, error)
+}
+
+// versions maps types to their *versionDecoders.
+var versions sync.Map
+
+// versionsFor returns the registrations for T, creating them if needed.
+func versionsFor[T any]() *versionDecoders[T] {
+	typ := reflect.TypeFor[T]()
+	if r, ok := versions.Load(typ); ok {
+		return r.(*versionDecoders[T])
+	}
+	r, _ := versions.LoadOrStore(typ, &versionDecoders[T]{
+		path:     DefaultVersionPath,
+		decoders: map[string]func([]byte) (T, error){},
+	})
+	return r.(*versionDecoders[T])
+}
+
+// RegisterVersion registers decode as the function VersionedJitJSON[T] decodes data with
+// when its version member equals version, so stored events spanning several schema
+// versions decode into the current T:
+//
+//	jitjson.RegisterVersion("1", func(data []byte) (Order, error) {
+//		var v1 OrderV1
+//		if err := json.Unmarshal(data, &v1); err != nil {
+//			return Order{}, err
+//		}
+//		return v1.Upgrade(), nil
+//	})
+//
+// String versions are matched unquoted and other scalars by their literal text, so
+// "version": 1 matches "1". It is typically called from init.
+func RegisterVersion[T any](version string, decode func(data []byte) (T, error)) {
+	r := versionsFor[T]()
+	r.mu.Lock()
+	r.decoders[version] = decode
+	r.mu.Unlock()
+}
+
+// SetVersionPath sets the dot-separated path of the member read for the schema version
+// of a VersionedJitJSON[T], such as "meta.schema".
+func SetVersionPath[T any](path string) {
+	r := versionsFor[T]()
+	r.mu.Lock()
+	r.path = path
+	r.mu.Unlock()
+}
+
+// VersionedJitJSON[T] is a JitJSON[T] whose data is decoded with the function registered
+// with RegisterVersion for its schema version. The version is read from the raw bytes
+// only when the value is first requested, so decoding stays deferred. Versions without
+// a registered function, and data without the version member, are decoded into T as by
+// JitJSON[T]. Marshal returns the stored data as is until Set, whatever its version.
+type VersionedJitJSON[T any] struct {
+	jit JitJSON[T]
+}
+
+// NewVersioned creates a VersionedJitJSON[T] from JSON byte data.
+func NewVersioned[T any](data []byte, opts ...Option) *VersionedJitJSON[T] {
+	o := deferred(newOptions(opts))
+	data = o.trimBOM(data)
+	recordDeferredUnmarshal(len(data))
+	v := &VersionedJitJSON[T]{jit: JitJSON[T]{opts: o}}
+	v.jit.setData(data)
+	return v
+}
+
+// deferred returns o without an eager threshold, since data decoded eagerly would bypass
+// the registered decode functions.
+func deferred(o *options) *options {
+	if o == nil || o.eagerBelow == 0 {
+		return o
+	}
+	o = o.clone()
+	o.eagerBelow = 0
+	return o
+}
+
+// Version returns the schema version of the stored data, reporting false if there is
+// no data or it has no version member.
+func (v *VersionedJitJSON[T]) Version() (string, bool, error) {
+	if v.jit.data == nil {
+		return "", false, nil
+	}
+	data, err := v.jit.opts.load(v.jit.data)
+	if err != nil {
+		return "", false, err
+	}
+	r := versionsFor[T]()
+	r.mu.RLock()
+	path := r.path
+	r.mu.RUnlock()
+
+	raw, ok, err := pathBytes(data, path)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	version, err := keyString(raw, path)
+	return version, err == nil, err
+}
+
+// Unmarshal decodes the value of VersionedJitJSON[T] with the function registered for its
+// version, caching it as Unmarshal of JitJSON[T] does.
+func (v *VersionedJitJSON[T]) Unmarshal() (T, error) {
+	if v.jit.val != nil || v.jit.data == nil {
+		return v.jit.Unmarshal()
+	}
+	version, ok, err := v.Version()
+	if err != nil {
+		var val T
+		return val, fmt.Errorf("jitjson: reading version: %w", err)
+	}
+	r := versionsFor[T]()
+	r.mu.RLock()
+	decode := r.decoders[version]
+	r.mu.RUnlock()
+	if !ok || decode == nil {
+		return v.jit.Unmarshal()
+	}
+
+	data, err := v.jit.opts.load(v.jit.data)
+	if err != nil {
+		var val T
+		return val, err
+	}
+	if err := v.jit.opts.charge(data); err != nil {
+		var val T
+		return val, err
+	}
+	recordUnmarshal(len(data))
+	val, err := decode(data)
+	if err != nil {
+		return val, fmt.Errorf("jitjson: decoding version %s: %w", version, err)
+	}
+	v.jit.val = &val
+	v.jit.verr = afterDecode(v.jit.opts, v.jit.val)
+	return val, v.jit.verr
+}
+
+// Set VersionedJitJSON[T] to a new value.
+func (v *VersionedJitJSON[T]) Set(val T) {
+	v.jit.Set(val)
+}
+
+// Marshal returns the stored data, or the encoding of the value set with Set.
+func (v *VersionedJitJSON[T]) Marshal() ([]byte, error) {
+	return v.jit.Marshal()
+}
+
+// MarshalJSON can be used to marshal VersionedJitJSON[T] to JSON.
+func (v *VersionedJitJSON[T]) MarshalJSON() ([]byte, error) {
+	return v.jit.Marshal()
+}
+
+// UnmarshalJSON stores JSON data to be decoded later, as UnmarshalJSON of JitJSON[T].
+func (v *VersionedJitJSON[T]) UnmarshalJSON(data []byte) error {
+	if v.jit.opts == nil {
+		v.jit.opts = deferred(defaults.Load())
+	}
+	return v.jit.UnmarshalJSON(data)
+}
@@ -0,0 +1,91 @@
+package jitjson_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+type account struct {
+	Version  int    `json:"version"`
+	First    string `json:"first"`
+	Last     string `json:"last"`
+	Currency string `json:"currency"`
+}
+
+func init() {
+	jitjson.RegisterVersion("1", func(data []byte) (account, error) {
+		var v1 struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return account{}, err
+		}
+		first, last, _ := strings.Cut(v1.Name, " ")
+		return account{Version: 2, First: first, Last: last, Currency: "EUR"}, nil
+	})
+
+	jitjson.SetVersionPath[ledgerEntry]("meta.schema")
+	jitjson.RegisterVersion("legacy", func(data []byte) (ledgerEntry, error) {
+		return ledgerEntry{Amount: -1}, nil
+	})
+}
+
+type ledgerEntry struct {
+	Amount int `json:"amount"`
+}
+
+func TestVersionedJitJSON(t *testing.T) {
+	var events []*jitjson.VersionedJitJSON[account]
+	data := []byte(`[{"version": 1, "name": "John Smith"}, {"version": 2, "first": "Jane", "last": "Doe", "currency": "USD"}, {"first": "Ann"}]`)
+	if err := json.Unmarshal(data, &events); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, ok, err := events[0].Version(); err != nil || !ok || version != "1" {
+		t.Errorf("unexpected version %q, %v, %v", version, ok, err)
+	}
+	var decodes int
+	counted := jitjson.NewVersioned[account]([]byte(`{"version": 2, "first": "Jane"}`), jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ }))
+	if version, ok, err := counted.Version(); err != nil || !ok || version != "2" {
+		t.Errorf("unexpected version %q, %v, %v", version, ok, err)
+	}
+	if decodes != 0 {
+		t.Errorf("expected no decoding, got %d", decodes)
+	}
+
+	want := []account{
+		{Version: 2, First: "John", Last: "Smith", Currency: "EUR"},
+		{Version: 2, First: "Jane", Last: "Doe", Currency: "USD"},
+		{First: "Ann"},
+	}
+	for i, event := range events {
+		got, err := event.Unmarshal()
+		if err != nil || got != want[i] {
+			t.Errorf("%d: expected %v, got %v, %v", i, want[i], got, err)
+		}
+	}
+
+	out, err := events[0].Marshal()
+	if err != nil || string(out) != `{"version": 1, "name": "John Smith"}` {
+		t.Errorf("expected the stored data, got %s, %v", out, err)
+	}
+	events[0].Set(want[0])
+	if out, err := json.Marshal(events[0]); err != nil || !strings.Contains(string(out), `"first":"John"`) {
+		t.Errorf("expected the set value, got %s, %v", out, err)
+	}
+}
+
+func TestVersionedJitJSONPath(t *testing.T) {
+	jit := jitjson.NewVersioned[ledgerEntry]([]byte(`{"meta": {"schema": "legacy"}, "amount": 5}`), jitjson.WithEagerThreshold(1<<10))
+	if entry, err := jit.Unmarshal(); err != nil || entry.Amount != -1 {
+		t.Errorf("expected the registered decoder, got %v, %v", entry, err)
+	}
+
+	jit = jitjson.NewVersioned[ledgerEntry]([]byte(`{"meta": {"schema": ["x"]}}`))
+	if _, err := jit.Unmarshal(); err == nil {
+		t.Error("expected an error for a version that is not a scalar")
+	}
+}
@@ -0,0 +1,41 @@
+package jitjson
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSignature is returned by VerifiedPayload when a signature does not match.
+var ErrInvalidSignature = errors.New("jitjson: invalid signature")
+
+// VerifiedPayload checks that sig is the HMAC-SHA256 of body under secret, and returns
+// body as a JitJSON[T] configured by opts without decoding it:
+//
+//	body, err := io.ReadAll(r.Body)
+//	event, err := jitjson.VerifiedPayload[Event](body, r.Header.Get("X-Hub-Signature-256"), secret)
+//
+// The signature is computed over the exact bytes received, which are then held as they
+// are rather than re-encoded, so the payload stays byte-for-byte what was signed. Sig is
+// hex encoded, with an optional "sha256=" prefix as sent by GitHub and similar
+// services, and is compared in constant time. Body is not copied. An error wrapping
+// ErrInvalidSignature is returned if the signature does not match. A verified body is
+// then checked with ScanValid, so malformed payloads also fail here.
+func VerifiedPayload[T any](body []byte, sig string, secret []byte, opts ...Option) (*JitJSON[T], error) {
+	want, err := hex.DecodeString(strings.TrimPrefix(sig, "sha256="))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), want) {
+		return nil, ErrInvalidSignature
+	}
+	if !ScanValid(body) {
+		return nil, errors.New("jitjson: invalid json payload")
+	}
+	return NewFromBytes[T](body, opts...), nil
+}
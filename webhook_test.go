@@ -0,0 +1,52 @@
+package jitjson_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/mcwalrus/go-jitjson"
+)
+
+func TestVerifiedPayload(t *testing.T) {
+	secret := []byte("s3cret")
+	sign := func(body []byte) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	body := []byte(`{"Name": "John",  "Age": 30}`)
+
+	var decodes int
+	count := jitjson.WithOnUnmarshal(func(jitjson.ParseEvent) { decodes++ })
+	jit, err := jitjson.VerifiedPayload[Person](body, sign(body), secret, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodes != 0 {
+		t.Errorf("expected the payload not to be decoded, got %d decodes", decodes)
+	}
+	if data, _ := jit.Marshal(); string(data) != string(body) {
+		t.Errorf("expected the exact signed bytes, got %s", data)
+	}
+	if p, err := jit.Unmarshal(); err != nil || p.Name != "John" {
+		t.Errorf("expected John, got %+v, %v", p, err)
+	}
+
+	if _, err := jitjson.VerifiedPayload[Person](body, sign(body)[7:], secret); err != nil {
+		t.Errorf("expected an unprefixed signature to verify, got %v", err)
+	}
+	tampered := []byte(`{"Name": "John", "Age": 30}`)
+	if _, err := jitjson.VerifiedPayload[Person](tampered, sign(body), secret); !errors.Is(err, jitjson.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a modified body, got %v", err)
+	}
+	if _, err := jitjson.VerifiedPayload[Person](body, "sha256=zz", secret); !errors.Is(err, jitjson.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a malformed signature, got %v", err)
+	}
+	invalid := []byte(`{"Name": `)
+	if _, err := jitjson.VerifiedPayload[Person](invalid, sign(invalid), secret); err == nil || errors.Is(err, jitjson.ErrInvalidSignature) {
+		t.Errorf("expected a json error for a malformed payload, got %v", err)
+	}
+}